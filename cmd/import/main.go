@@ -0,0 +1,104 @@
+// Command import bulk-loads products from a CSV or JSON Lines file, upserting them by external SKU.
+//
+// Usage:
+//
+//	go run ./cmd/import -file products.csv [-format csv|jsonl] [-dry-run] [-batch-size 500]
+//
+// The per-row report is written to stdout as JSON and progress is logged while the file is read.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"product-catalog-service/config"
+	"product-catalog-service/infrastructure/log"
+	"product-catalog-service/internal/entity"
+	"product-catalog-service/internal/repository"
+	"product-catalog-service/internal/resource"
+	"product-catalog-service/internal/service"
+	"strings"
+)
+
+func main() {
+	file := flag.String("file", "", "path of the CSV or JSON Lines file to import")
+	format := flag.String("format", "", "input format, csv or jsonl (default: from the file extension)")
+	dryRun := flag.Bool("dry-run", false, "validate the file without writing anything")
+	batchSize := flag.Int("batch-size", 500, "number of rows upserted per transaction")
+	flag.Parse()
+
+	log.InitLogger()
+	if *file == "" {
+		log.Logger.Fatal().Msg("The -file flag is required")
+	}
+	if *format == "" {
+		*format = strings.TrimPrefix(strings.ToLower(filepath.Ext(*file)), ".")
+		if *format == "ndjson" {
+			*format = string(entity.ImportFormatJSONL)
+		}
+	}
+
+	appConfig := config.LoadConfig(
+		config.WithConfigFolder([]string{"./files/config"}),
+		config.WithConfigFile("config"),
+		config.WithConfigType("yaml"),
+	)
+
+	redisClient := resource.InitRedis(appConfig)
+	db := resource.InitDB(appConfig)
+
+	cacheRepo := repository.NewCacheRepository(redisClient)
+	if localCache := appConfig.LocalCache; localCache.Enabled {
+		// Nothing is read through it; it only publishes the invalidations of the imported products
+		// to the in-process caches of the server instances.
+		cacheRepo = repository.NewLRUCacheRepository(cacheRepo, redisClient, repository.LRUCacheOptions{
+			MaxEntries: localCache.MaxEntries,
+			MaxBytes:   localCache.MaxBytes,
+			TTL:        localCache.TTL,
+			Prefixes:   localCache.Prefixes,
+			Channel:    localCache.Channel,
+		})
+	}
+	var cacheOptions repository.ProductCacheOptions
+	if idFilter := appConfig.ProductCache.IDFilter; idFilter.Enabled {
+		// The filter is never built here; it only publishes the imported products to the filters of
+		// the server instances.
+		cacheOptions.IDFilter = repository.NewProductIDFilter(redisClient, db, idFilter.Channel, idFilter.ExpectedProducts, idFilter.FalsePositiveRate)
+	}
+	productRepo := repository.NewProductRepository(cacheRepo, db, cacheOptions)
+	// Server instances using the in-memory search index pick up imported products on restart.
+	searchRepo := repository.NewMySQLSearchRepository(db)
+	importService := service.NewImportService(productRepo, searchRepo)
+
+	f, err := os.Open(*file)
+	if err != nil {
+		log.Logger.Fatal().Err(err).Str("file", *file).Msg("Failed to open import file")
+	}
+	defer f.Close()
+
+	opts := entity.ImportOptions{
+		Format:    entity.ImportFormat(*format),
+		DryRun:    *dryRun,
+		BatchSize: *batchSize,
+	}
+	progress := func(p entity.ImportProgress) {
+		log.Logger.Info().Int("rowsRead", p.RowsRead).Int("rowsInvalid", p.RowsInvalid).Int("rowsUpserted", p.RowsUpserted).Msg("Import progress")
+	}
+
+	report, importErr := importService.ImportProducts(context.Background(), f, opts, progress)
+	if report != nil {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			log.Logger.Error().Err(err).Msg("Failed to write import report")
+		}
+	}
+	if importErr != nil {
+		log.Logger.Fatal().Err(importErr).Msg("Import failed")
+	}
+	if report.RowsInvalid > 0 {
+		os.Exit(2)
+	}
+}
@@ -1,20 +1,32 @@
 package main
 
 import (
+	"context"
+	"net"
 	"product-catalog-service/config"
 	"product-catalog-service/infrastructure/log"
+	"product-catalog-service/infrastructure/storage"
 	"product-catalog-service/internal/api"
+	"product-catalog-service/internal/entity"
+	"product-catalog-service/internal/grpcapi"
+	"product-catalog-service/internal/job"
 	"product-catalog-service/internal/repository"
 	"product-catalog-service/internal/resource"
 	"product-catalog-service/internal/service"
+	"product-catalog-service/internal/validation"
 	infrastructure "product-catalog-service/middleware"
 	"product-catalog-service/msgBroker"
+	"product-catalog-service/pkg/orderhash"
 	"product-catalog-service/routes"
+	"strings"
 	"time"
 
+	"github.com/go-redis/redis/v8"
 	echojwt "github.com/labstack/echo-jwt/v4"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
+	"google.golang.org/grpc"
+	"gorm.io/gorm"
 )
 
 func main() {
@@ -28,22 +40,217 @@ func main() {
 	redisClient := resource.InitRedis(appConfig)
 	db := resource.InitDB(appConfig)
 
-	cacheRepo := repository.NewCacheRepository(redisClient)
-	productRepo := repository.NewProductRepository(cacheRepo, db)
-	productService := service.NewProductService(productRepo)
-	productHandler := api.NewProductHandler(productService)
+	cacheRepo := initCacheRepository(appConfig, redisClient)
+	productIDFilter := initProductIDFilter(appConfig, redisClient, db)
+	productRepo := repository.NewProductRepository(cacheRepo, db, repository.ProductCacheOptions{
+		LockTTL:          appConfig.ProductCache.LockTTL,
+		EarlyRefreshBeta: appConfig.ProductCache.EarlyRefreshBeta,
+		NotFoundTTL:      appConfig.ProductCache.NotFoundTTL,
+		IDFilter:         productIDFilter,
+	})
+	categoryRepo := repository.NewCategoryRepository(db)
+	tagRepo := repository.NewTagRepository(db)
+	variantRepo := repository.NewVariantRepository(cacheRepo, db)
+	mediaRepo := repository.NewMediaRepository(db)
+	priceRepo := repository.NewPriceRepository(cacheRepo, db)
+	currencyRepo := repository.NewCurrencyRepository(db)
+	reservationRepo := repository.NewReservationRepository(cacheRepo, db)
+	stockEventRepo := repository.NewStockEventRepository(redisClient, appConfig.StockStream.Channel)
+	idempotencyRepo := repository.NewIdempotencyRepository(redisClient)
+	mediaStorage := storage.NewLocalStorage(appConfig.Media.StorageDir, appConfig.Media.BaseURL)
+	searchRepo := initSearchRepository(appConfig, db, productRepo)
+
+	currencyService := service.NewCurrencyService(currencyRepo, productRepo)
+	stockStreamService := service.NewStockStreamService(stockEventRepo, appConfig.StockStream.CoalesceWindow)
+	productService := service.NewProductService(productRepo, searchRepo, tagRepo, variantRepo, mediaRepo, reservationRepo, currencyService, stockStreamService, mediaStorage)
+	categoryService := service.NewCategoryService(categoryRepo, tagRepo, productRepo)
+	variantService := service.NewVariantService(variantRepo, productRepo)
+	mediaService := service.NewMediaService(mediaRepo, productRepo, mediaStorage, appConfig.Media.MaxUploadBytes)
+	importService := service.NewImportService(productRepo, searchRepo)
+	exportService := service.NewExportService(productRepo)
+	priceService := service.NewPriceService(priceRepo, productRepo)
+	pricingService := service.NewPricingService(productRepo, variantRepo, currencyService, appConfig.Pricing.Tolerance)
+	idempotencyService := service.NewIdempotencyService(idempotencyRepo, appConfig.API.IdempotencyTTL)
+	// The in-process cache is nil when disabled.
+	localCache, _ := cacheRepo.(repository.LRUCacheRepository)
+	cacheService := service.NewCacheService(localCache)
+
+	v1 := routes.V1Handlers{
+		Product:     api.NewProductHandler(productService),
+		Category:    api.NewCategoryHandler(categoryService),
+		Variant:     api.NewVariantHandler(variantService),
+		Media:       api.NewMediaHandler(mediaService),
+		Import:      api.NewImportHandler(importService),
+		Export:      api.NewExportHandler(exportService),
+		Pricing:     api.NewPricingHandler(pricingService),
+		Price:       api.NewPriceHandler(priceService),
+		Currency:    api.NewCurrencyHandler(currencyService),
+		StockStream: api.NewStockStreamHandler(productService, stockStreamService, appConfig.StockStream.Heartbeat),
+		Cache:       api.NewCacheHandler(cacheService),
+	}
 
-	consumer := msgBroker.NewMsgConsumer(productService)
+	validator := validation.New()
+
+	deadLetterWriter := msgBroker.NewKafkaWriter(appConfig.Kafka.Brokers, appConfig.Kafka.DeadLetterTopic)
+	consumer := msgBroker.NewMsgConsumer(productService, pricingService, initOrderHashKeyring(appConfig), deadLetterWriter, validator, appConfig.Kafka.LegacyPriceCurrency, appConfig.Kafka.LineReleaseBeforeOrderID)
 	go consumer.StartConsumer(appConfig.Kafka.Brokers, appConfig.Kafka.Topic, appConfig.Kafka.GroupID)
 
+	archiveJob := job.NewProductArchiveJob(productService, appConfig.Archive.Retention, appConfig.Archive.Interval, appConfig.Archive.BatchSize)
+	go archiveJob.Start(context.Background())
+
+	scheduleJob := job.NewProductScheduleJob(productService, appConfig.Schedule.Interval)
+	go scheduleJob.Start(context.Background())
+
+	priceScheduleJob := job.NewPriceScheduleJob(priceService, appConfig.Schedule.Interval)
+	go priceScheduleJob.Start(context.Background())
+	go stockStreamService.Start(context.Background())
+
+	grpcServer := grpcapi.NewServer(productService, stockStreamService, validator, []byte(appConfig.Secret.JWTSecret), appConfig.GRPC.DefaultTimeout)
+	go serveGRPC(grpcServer, appConfig.GRPC.Port)
+
 	e := echo.New()
+	e.HTTPErrorHandler = api.HTTPErrorHandler
+	e.Validator = validator
+	e.Use(middleware.RequestID())
 	e.Use(middleware.RateLimiterWithConfig(infrastructure.GetRateLimiter()))
 	e.Use(middleware.Logger())
 	e.Use(middleware.Recover())
-	e.Use(middleware.ContextTimeout(10 * time.Second))
-	e.Use(echojwt.JWT([]byte(appConfig.Secret.JWTSecret)))
+	e.Use(middleware.ContextTimeoutWithConfig(middleware.ContextTimeoutConfig{
+		// Bulk import and export stream large files and stock streams stay open, so they are bounded
+		// by the client instead.
+		Skipper: func(c echo.Context) bool {
+			path := apiPath(c)
+			return path == "/products/import" || path == "/products/export" || strings.HasPrefix(path, "/products/stock/")
+		},
+		Timeout: 10 * time.Second,
+	}))
+	e.Use(echojwt.WithConfig(echojwt.Config{
+		SigningKey: []byte(appConfig.Secret.JWTSecret),
+		// Product images are embedded in public sale pages and the API document is public.
+		Skipper: func(c echo.Context) bool {
+			return strings.HasPrefix(c.Request().URL.Path, "/media/") || apiPath(c) == "/openapi.json"
+		},
+	}))
+	e.Use(api.Idempotency(idempotencyService, routes.V1))
+	e.Static("/media", appConfig.Media.StorageDir)
 
-	routes.SetupRoutes(e, productHandler)
+	routes.SetupRoutes(e, v1, initDeprecation(appConfig))
+	if err := api.VerifyRoutes(e.Routes(), routes.V1); err != nil {
+		log.Logger.Fatal().Err(err).Msg("Routes and API document have diverged")
+	}
 
 	e.Logger.Fatal(e.Start(":" + appConfig.App.Port))
 }
+
+// apiPath returns the request path without the version prefix, so that path based middleware
+// settings apply to the versioned routes and their deprecated aliases alike.
+func apiPath(c echo.Context) string {
+	return strings.TrimPrefix(c.Request().URL.Path, routes.V1)
+}
+
+// initDeprecation reads when the unversioned routes were deprecated and when they are removed.
+func initDeprecation(appConfig config.Config) routes.Deprecation {
+	var deprecation routes.Deprecation
+	var err error
+	deprecation.DeprecatedAt, err = time.Parse(time.RFC3339, appConfig.API.LegacyDeprecatedAt)
+	if err != nil {
+		log.Logger.Fatal().Err(err).Msg("Invalid api.legacy_deprecated_at")
+	}
+	if appConfig.API.LegacySunset != "" {
+		deprecation.Sunset, err = time.Parse(time.RFC3339, appConfig.API.LegacySunset)
+		if err != nil {
+			log.Logger.Fatal().Err(err).Msg("Invalid api.legacy_sunset")
+		}
+	}
+	return deprecation
+}
+
+// serveGRPC serves the gRPC inventory API on port.
+func serveGRPC(server *grpc.Server, port string) {
+	listener, err := net.Listen("tcp", ":"+port)
+	if err != nil {
+		log.Logger.Fatal().Err(err).Str("port", port).Msg("Failed to listen for gRPC")
+	}
+	if err := server.Serve(listener); err != nil {
+		log.Logger.Fatal().Err(err).Msg("gRPC server stopped")
+	}
+}
+
+// initOrderHashKeyring builds the keyring used to verify the HMAC of order messages.
+func initOrderHashKeyring(appConfig config.Config) *orderhash.Keyring {
+	keys := make(map[string][]byte, len(appConfig.OrderHash.Keys))
+	for _, key := range appConfig.OrderHash.Keys {
+		keys[key.ID] = []byte(key.Secret)
+	}
+	keyring, err := orderhash.NewKeyring(appConfig.OrderHash.CurrentKeyID, keys)
+	if err != nil {
+		log.Logger.Fatal().Err(err).Msg("Invalid order hash configuration")
+	}
+	return keyring
+}
+
+// initCacheRepository returns the Redis cache, behind an in-process cache if enabled.
+func initCacheRepository(appConfig config.Config, redisClient *redis.Client) repository.CacheRepository {
+	cacheRepo := repository.NewCacheRepository(redisClient)
+	localCache := appConfig.LocalCache
+	if !localCache.Enabled {
+		return cacheRepo
+	}
+	lruCache := repository.NewLRUCacheRepository(cacheRepo, redisClient, repository.LRUCacheOptions{
+		MaxEntries: localCache.MaxEntries,
+		MaxBytes:   localCache.MaxBytes,
+		TTL:        localCache.TTL,
+		Prefixes:   localCache.Prefixes,
+		Channel:    localCache.Channel,
+	})
+	go lruCache.Start(context.Background(), localCache.StatsInterval)
+	return lruCache
+}
+
+// initProductIDFilter starts the Bloom filter of product IDs, if enabled. It is built in the
+// background, and lets every ID through until then.
+func initProductIDFilter(appConfig config.Config, redisClient *redis.Client, db *gorm.DB) repository.ProductIDFilter {
+	idFilter := appConfig.ProductCache.IDFilter
+	if !idFilter.Enabled {
+		return nil
+	}
+	filter := repository.NewProductIDFilter(redisClient, db, idFilter.Channel, idFilter.ExpectedProducts, idFilter.FalsePositiveRate)
+	go filter.Start(context.Background(), idFilter.RebuildInterval)
+	return filter
+}
+
+// initSearchRepository builds the search backend selected in the configuration.
+// The in-memory index is populated from the database before the server starts.
+func initSearchRepository(appConfig config.Config, db *gorm.DB, productRepo repository.ProductRepository) repository.SearchRepository {
+	switch appConfig.Search.Engine {
+	case "memory":
+		ctx := context.Background()
+		searchRepo := repository.NewMemorySearchRepository()
+		products, err := productRepo.GetProducts(ctx, entity.ProductFilter{ActiveOnly: true})
+		if err != nil {
+			log.Logger.Fatal().Err(err).Msg("Failed to load products for search index")
+		}
+		for i := range products {
+			_ = searchRepo.IndexProduct(ctx, &products[i])
+		}
+		log.Logger.Info().Int("products", len(products)).Msg("In-memory search index built")
+		return searchRepo
+	default:
+		searchRepo := repository.NewMySQLSearchRepository(db)
+		// The vocabulary used to correct typos is filled in the background; until then misspelled
+		// queries find nothing, as before.
+		go func() {
+			ctx := context.Background()
+			products, err := productRepo.GetProducts(ctx, entity.ProductFilter{ActiveOnly: true})
+			if err != nil {
+				log.Logger.Error().Err(err).Msg("Failed to load products for search vocabulary")
+				return
+			}
+			for i := range products {
+				_ = searchRepo.IndexProduct(ctx, &products[i])
+			}
+			log.Logger.Info().Int("products", len(products)).Msg("Search vocabulary built")
+		}()
+		return searchRepo
+	}
+}
@@ -45,10 +45,33 @@ func LoadConfig(opts ...Option) Config {
 	}
 
 	log.Logger.Info().Msg("Configuration loaded successfully")
-	log.Logger.Info().Interface("config", cfg).Msg("Loaded configuration details")
+	log.Logger.Info().Interface("config", cfg.Redacted()).Msg("Loaded configuration details")
 	return cfg
 }
 
+// redacted replaces a secret in logged configuration.
+const redacted = "[REDACTED]"
+
+// Redacted returns a copy of the configuration with every password, secret and key blanked out,
+// safe to log.
+func (c Config) Redacted() Config {
+	if c.DB.Password != "" {
+		c.DB.Password = redacted
+	}
+	if c.Redis.Password != "" {
+		c.Redis.Password = redacted
+	}
+	if c.Secret.JWTSecret != "" {
+		c.Secret.JWTSecret = redacted
+	}
+	keys := make([]OrderHashKey, len(c.OrderHash.Keys))
+	for i, key := range c.OrderHash.Keys {
+		keys[i] = OrderHashKey{ID: key.ID, Secret: redacted}
+	}
+	c.OrderHash.Keys = keys
+	return c
+}
+
 func getDefaultConfigFolder() []string {
 	return []string{"./files/config"}
 }
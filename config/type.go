@@ -1,11 +1,24 @@
 package config
 
+import "time"
+
 type Config struct {
-	App    App           `yaml:"app" validate:"required"`
-	DB     DB            `yaml:"db" validate:"required"`
-	Redis  Redis         `yaml:"redis" validate:"required"`
-	Secret SecreteConfig `yaml:"secret" validate:"required"`
-	Kafka  Kafka         `yaml:"kafka" validate:"required"`
+	App          App           `yaml:"app" validate:"required"`
+	DB           DB            `yaml:"db" validate:"required"`
+	Redis        Redis         `yaml:"redis" validate:"required"`
+	Secret       SecreteConfig `yaml:"secret" validate:"required"`
+	Kafka        Kafka         `yaml:"kafka" validate:"required"`
+	Search       Search        `yaml:"search"`
+	Media        Media         `yaml:"media" validate:"required"`
+	Archive      Archive       `yaml:"archive" validate:"required"`
+	Schedule     Schedule      `yaml:"schedule" validate:"required"`
+	Pricing      Pricing       `yaml:"pricing"`
+	OrderHash    OrderHash     `yaml:"order_hash" mapstructure:"order_hash" validate:"required"`
+	StockStream  StockStream   `yaml:"stock_stream" mapstructure:"stock_stream" validate:"required"`
+	GRPC         GRPC          `yaml:"grpc" validate:"required"`
+	API          API           `yaml:"api" validate:"required"`
+	ProductCache ProductCache  `yaml:"product_cache" mapstructure:"product_cache"`
+	LocalCache   LocalCache    `yaml:"local_cache" mapstructure:"local_cache"`
 }
 
 type App struct {
@@ -34,4 +47,132 @@ type Kafka struct {
 	Brokers []string `mapstructure:"brokers" validate:"required"`
 	Topic   string   `mapstructure:"topic" validate:"required"`
 	GroupID string   `mapstructure:"group_id" validate:"required"`
+	// DeadLetterTopic receives order messages that were rejected, with the reason in a header.
+	DeadLetterTopic string `mapstructure:"dead_letter_topic" validate:"required"`
+	// LegacyPriceCurrency is the currency of order prices still sent as bare numbers instead of
+	// {"amount", "currency"} objects. Empty rejects such orders; clear it once every producer has
+	// switched.
+	LegacyPriceCurrency string `mapstructure:"legacy_price_currency"`
+	// LineReleaseBeforeOrderID is the ID of the first order reserved as a whole. Cancelled orders
+	// with lower IDs and no reservation were reserved line by line, and are released that way.
+	// Zero disables the fallback.
+	LineReleaseBeforeOrderID int64 `mapstructure:"line_release_before_order_id"`
+}
+
+type Search struct {
+	// Engine selects the search backend: "mysql" (default) or "memory".
+	Engine string `mapstructure:"engine"`
+}
+
+type Media struct {
+	StorageDir     string `mapstructure:"storage_dir" validate:"required"`
+	BaseURL        string `mapstructure:"base_url" validate:"required"`
+	MaxUploadBytes int64  `mapstructure:"max_upload_bytes" validate:"required"`
+}
+
+type Archive struct {
+	// Retention is how long soft-deleted products are kept before they are archived.
+	Retention time.Duration `mapstructure:"retention" validate:"required"`
+	Interval  time.Duration `mapstructure:"interval" validate:"required"`
+	BatchSize int           `mapstructure:"batch_size" validate:"required"`
+}
+
+type Schedule struct {
+	// Interval is how often scheduled publish and unpublish times and scheduled prices are applied.
+	Interval time.Duration `mapstructure:"interval" validate:"required"`
+}
+
+type Pricing struct {
+	// Tolerance is the largest accepted difference, in minor units, between the unit price of an
+	// order line and the price computed by the service. Zero requires an exact match.
+	Tolerance int64 `mapstructure:"tolerance"`
+}
+
+// OrderHash holds the shared HMAC keys used to verify order messages. Keys are listed rather than
+// mapped because configuration map keys are case-folded.
+type OrderHash struct {
+	// CurrentKeyID is the key new hashes are signed with; every listed key is accepted for verification.
+	CurrentKeyID string         `mapstructure:"current_key_id" validate:"required"`
+	Keys         []OrderHashKey `mapstructure:"keys" validate:"required"`
+}
+
+type OrderHashKey struct {
+	ID     string `mapstructure:"id" validate:"required"`
+	Secret string `mapstructure:"secret" validate:"required"`
+}
+
+type StockStream struct {
+	// Channel is the Redis pub/sub channel that fans stock changes out to every instance.
+	Channel string `mapstructure:"channel" validate:"required"`
+	// CoalesceWindow is how long changes are collected before they are pushed to a client, so a
+	// burst of reservations becomes one update per product. Zero pushes every change at once.
+	CoalesceWindow time.Duration `mapstructure:"coalesce_window"`
+	// Heartbeat is how often an idle stream is sent a keep-alive.
+	Heartbeat time.Duration `mapstructure:"heartbeat" validate:"required"`
+}
+
+type GRPC struct {
+	Port string `mapstructure:"port" validate:"required"`
+	// DefaultTimeout bounds unary calls that arrive without a deadline.
+	DefaultTimeout time.Duration `mapstructure:"default_timeout" validate:"required"`
+}
+
+// API configures the versions of the HTTP API.
+type API struct {
+	// LegacyDeprecatedAt is when the unversioned aliases of the /v1 routes were deprecated, as an
+	// RFC 3339 time.
+	LegacyDeprecatedAt string `mapstructure:"legacy_deprecated_at" validate:"required"`
+	// LegacySunset is when the unversioned aliases are removed, as an RFC 3339 time. Empty leaves
+	// the removal date unannounced.
+	LegacySunset string `mapstructure:"legacy_sunset"`
+	// IdempotencyTTL is how long the response of a request with an Idempotency-Key is replayed to
+	// retries with the same key.
+	IdempotencyTTL time.Duration `mapstructure:"idempotency_ttl" validate:"required"`
+}
+
+type ProductCache struct {
+	// LockTTL, when set, lets one instance at a time load a product missing from the cache while
+	// the others wait for it, for at most this long.
+	LockTTL time.Duration `mapstructure:"lock_ttl"`
+	// EarlyRefreshBeta makes popular products be refreshed before their cache entry expires; larger
+	// values refresh earlier. Zero disables early refresh.
+	EarlyRefreshBeta float64 `mapstructure:"early_refresh_beta"`
+	// NotFoundTTL, when set, caches that a product does not exist for this long. Zero disables
+	// negative caching.
+	NotFoundTTL time.Duration `mapstructure:"not_found_ttl"`
+	IDFilter    IDFilter      `mapstructure:"id_filter"`
+}
+
+// LocalCache configures the in-process cache in front of Redis.
+type LocalCache struct {
+	Enabled bool `mapstructure:"enabled"`
+	// MaxEntries bounds the number of keys held in memory.
+	MaxEntries int `mapstructure:"max_entries" validate:"required_if=Enabled true"`
+	// MaxBytes, when set, bounds the total size of the keys and values held in memory.
+	MaxBytes int `mapstructure:"max_bytes"`
+	// TTL bounds how long a key is served from memory, and so how stale a value can be when an
+	// invalidation is lost.
+	TTL time.Duration `mapstructure:"ttl" validate:"required_if=Enabled true"`
+	// Prefixes selects the keys held in memory.
+	Prefixes []string `mapstructure:"prefixes" validate:"required_if=Enabled true"`
+	// Channel is the Redis pub/sub channel that fans invalidations out to every instance.
+	Channel string `mapstructure:"channel" validate:"required_if=Enabled true"`
+	// StatsInterval is how often the hit and miss counters are logged. Zero never logs them.
+	StatsInterval time.Duration `mapstructure:"stats_interval"`
+}
+
+// IDFilter configures the Bloom filter of product IDs that rejects unknown IDs before the cache.
+type IDFilter struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Channel is the Redis pub/sub channel that fans created and restored products out to every
+	// instance.
+	Channel string `mapstructure:"channel" validate:"required_if=Enabled true"`
+	// ExpectedProducts is the number of products the filter is sized for; it grows to the catalogue
+	// on every rebuild.
+	ExpectedProducts int `mapstructure:"expected_products"`
+	// FalsePositiveRate is the share of unknown IDs let through to the cache.
+	FalsePositiveRate float64 `mapstructure:"false_positive_rate"`
+	// RebuildInterval is how often the filter is rebuilt from the database, which repairs missed
+	// changes and sheds deleted products.
+	RebuildInterval time.Duration `mapstructure:"rebuild_interval" validate:"required_if=Enabled true"`
 }
@@ -1,9 +1,158 @@
+CREATE TABLE `categories`
+(
+    `id`        int(11) NOT NULL AUTO_INCREMENT,
+    `parent_id` int(11) DEFAULT NULL,
+    `name`      varchar(255) NOT NULL,
+    PRIMARY KEY (`id`),
+    KEY `idx_categories_parent_id` (`parent_id`),
+    CONSTRAINT `fk_categories_parent` FOREIGN KEY (`parent_id`) REFERENCES `categories` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
 CREATE TABLE `products`
 (
     `id`          int(11) NOT NULL AUTO_INCREMENT,
+    `external_sku` varchar(64) DEFAULT NULL,
     `name`        varchar(255) NOT NULL,
     `description` text         NOT NULL,
-    `price` double NOT NULL,
+    `price_amount`   bigint(20) NOT NULL COMMENT 'In minor units of price_currency',
+    `price_currency` char(3)    NOT NULL,
     `stock`       int(11) NOT NULL,
+    `category_id` int(11) DEFAULT NULL,
+    `status`      varchar(16)  NOT NULL DEFAULT 'draft',
+    `publish_at`  datetime(3) DEFAULT NULL,
+    `unpublish_at` datetime(3) DEFAULT NULL,
+    `deleted_at`  datetime(3) DEFAULT NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_products_external_sku` (`external_sku`),
+    KEY `idx_products_category_id` (`category_id`),
+    KEY `idx_products_deleted_at` (`deleted_at`),
+    KEY `idx_products_status_publish_at` (`status`, `publish_at`),
+    KEY `idx_products_status_unpublish_at` (`status`, `unpublish_at`),
+    FULLTEXT KEY `ft_products_name_description` (`name`, `description`),
+    CONSTRAINT `fk_products_category` FOREIGN KEY (`category_id`) REFERENCES `categories` (`id`) ON DELETE SET NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE `tags`
+(
+    `id`   int(11) NOT NULL AUTO_INCREMENT,
+    `name` varchar(100) NOT NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_tags_name` (`name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE `product_tags`
+(
+    `product_id` int(11) NOT NULL,
+    `tag_id`     int(11) NOT NULL,
+    PRIMARY KEY (`product_id`, `tag_id`),
+    KEY `idx_product_tags_tag_id` (`tag_id`),
+    CONSTRAINT `fk_product_tags_product` FOREIGN KEY (`product_id`) REFERENCES `products` (`id`) ON DELETE CASCADE,
+    CONSTRAINT `fk_product_tags_tag` FOREIGN KEY (`tag_id`) REFERENCES `tags` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE `product_variants`
+(
+    `id`         int(11) NOT NULL AUTO_INCREMENT,
+    `product_id` int(11) NOT NULL,
+    `sku`        varchar(64) NOT NULL,
+    `attributes` json        NOT NULL,
+    `price_amount`   bigint(20) DEFAULT NULL,
+    `price_currency` char(3)    DEFAULT NULL,
+    `stock`      int(11) NOT NULL DEFAULT 0,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_product_variants_sku` (`sku`),
+    KEY `idx_product_variants_product_id` (`product_id`),
+    CONSTRAINT `fk_product_variants_product` FOREIGN KEY (`product_id`) REFERENCES `products` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE `product_media`
+(
+    `id`          int(11) NOT NULL AUTO_INCREMENT,
+    `product_id`   int(11) NOT NULL,
+    `url`          varchar(1024) NOT NULL,
+    `storage_key`  varchar(255)  NOT NULL,
+    `content_type` varchar(64)   NOT NULL,
+    `size`         bigint(20) NOT NULL,
+    `position`     int(11) NOT NULL,
+    PRIMARY KEY (`id`),
+    KEY `idx_product_media_product_position` (`product_id`, `position`),
+    CONSTRAINT `fk_product_media_product` FOREIGN KEY (`product_id`) REFERENCES `products` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE `products_archive`
+(
+    `id`           int(11) NOT NULL,
+    `external_sku` varchar(64) DEFAULT NULL,
+    `name`         varchar(255) NOT NULL,
+    `description`  text         NOT NULL,
+    `price_amount`   bigint(20) NOT NULL,
+    `price_currency` char(3)    NOT NULL,
+    `stock`        int(11) NOT NULL,
+    `category_id`  int(11) DEFAULT NULL,
+    `status`       varchar(16)  NOT NULL,
+    `deleted_at`   datetime(3) NOT NULL,
+    `related`      json        DEFAULT NULL COMMENT 'Variants, media, tags and currency prices at archival',
+    `archived_at`  datetime(3) NOT NULL,
+    PRIMARY KEY (`id`),
+    KEY `idx_products_archive_external_sku` (`external_sku`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE `product_prices`
+(
+    `id`             bigint(20) NOT NULL AUTO_INCREMENT,
+    `product_id`     int(11)     NOT NULL,
+    `price_amount`   bigint(20) NOT NULL COMMENT 'In minor units of price_currency',
+    `price_currency` char(3)     NOT NULL,
+    `effective_from` datetime(3) NOT NULL,
+    `effective_to`   datetime(3) DEFAULT NULL,
+    `applied_at`     datetime(3) DEFAULT NULL,
+    `created_at`     datetime(3) NOT NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_product_prices_product_from` (`product_id`, `effective_from`),
+    KEY `idx_product_prices_pending` (`applied_at`, `effective_from`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE `exchange_rates`
+(
+    `base_currency`  char(3)        NOT NULL,
+    `quote_currency` char(3)        NOT NULL,
+    `rate`           decimal(20, 10) NOT NULL COMMENT 'Major units of quote_currency per major unit of base_currency',
+    `updated_at`     datetime(3)    NOT NULL,
+    PRIMARY KEY (`base_currency`, `quote_currency`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE `product_currency_prices`
+(
+    `product_id`     int(11)     NOT NULL,
+    `price_amount`   bigint(20) NOT NULL COMMENT 'In minor units of price_currency',
+    `price_currency` char(3)     NOT NULL,
+    `updated_at`     datetime(3) NOT NULL,
+    PRIMARY KEY (`product_id`, `price_currency`),
+    CONSTRAINT `fk_product_currency_prices_product` FOREIGN KEY (`product_id`) REFERENCES `products` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE `stock_reservations`
+(
+    `id`          char(36)    NOT NULL,
+    `status`      varchar(16) NOT NULL,
+    `created_at`  datetime(3) NOT NULL,
+    `released_at` datetime(3) DEFAULT NULL,
+    `confirmed_at` datetime(3) DEFAULT NULL,
     PRIMARY KEY (`id`)
-) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
\ No newline at end of file
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE `stock_reservation_lines`
+(
+    `reservation_id` char(36) NOT NULL,
+    `product_id`     int(11)  NOT NULL,
+    `variant_id`     int(11)  NOT NULL DEFAULT 0 COMMENT 'Zero for products without variants',
+    `quantity`       int(11)  NOT NULL,
+    PRIMARY KEY (`reservation_id`, `product_id`, `variant_id`),
+    CONSTRAINT `fk_stock_reservation_lines_reservation` FOREIGN KEY (`reservation_id`) REFERENCES `stock_reservations` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE `search_terms`
+(
+    `term` varchar(64) NOT NULL COMMENT 'Lower-cased word of an indexed product, used to correct typos',
+    PRIMARY KEY (`term`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
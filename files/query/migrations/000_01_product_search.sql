@@ -0,0 +1,4 @@
+-- Adds the full-text index used by MySQL product search. Migrations are applied in file name
+-- order; the 000_ files bring a database created before them up to the schema that 001 expects.
+ALTER TABLE `products`
+    ADD FULLTEXT KEY `ft_products_name_description` (`name`, `description`);
@@ -0,0 +1,33 @@
+-- Adds the category tree and product tags. Existing products start without a category or tags.
+CREATE TABLE `categories`
+(
+    `id`        int(11) NOT NULL AUTO_INCREMENT,
+    `parent_id` int(11) DEFAULT NULL,
+    `name`      varchar(255) NOT NULL,
+    PRIMARY KEY (`id`),
+    KEY `idx_categories_parent_id` (`parent_id`),
+    CONSTRAINT `fk_categories_parent` FOREIGN KEY (`parent_id`) REFERENCES `categories` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+ALTER TABLE `products`
+    ADD COLUMN `category_id` int(11) DEFAULT NULL AFTER `stock`,
+    ADD KEY `idx_products_category_id` (`category_id`),
+    ADD CONSTRAINT `fk_products_category` FOREIGN KEY (`category_id`) REFERENCES `categories` (`id`) ON DELETE SET NULL;
+
+CREATE TABLE `tags`
+(
+    `id`   int(11) NOT NULL AUTO_INCREMENT,
+    `name` varchar(100) NOT NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_tags_name` (`name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE `product_tags`
+(
+    `product_id` int(11) NOT NULL,
+    `tag_id`     int(11) NOT NULL,
+    PRIMARY KEY (`product_id`, `tag_id`),
+    KEY `idx_product_tags_tag_id` (`tag_id`),
+    CONSTRAINT `fk_product_tags_product` FOREIGN KEY (`product_id`) REFERENCES `products` (`id`) ON DELETE CASCADE,
+    CONSTRAINT `fk_product_tags_tag` FOREIGN KEY (`tag_id`) REFERENCES `tags` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,14 @@
+-- Adds product variants. Existing products have none, so their stock stays on the product.
+CREATE TABLE `product_variants`
+(
+    `id`         int(11) NOT NULL AUTO_INCREMENT,
+    `product_id` int(11) NOT NULL,
+    `sku`        varchar(64) NOT NULL,
+    `attributes` json        NOT NULL,
+    `price` double DEFAULT NULL,
+    `stock`      int(11) NOT NULL DEFAULT 0,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_product_variants_sku` (`sku`),
+    KEY `idx_product_variants_product_id` (`product_id`),
+    CONSTRAINT `fk_product_variants_product` FOREIGN KEY (`product_id`) REFERENCES `products` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,14 @@
+-- Adds the images of products.
+CREATE TABLE `product_media`
+(
+    `id`           int(11) NOT NULL AUTO_INCREMENT,
+    `product_id`   int(11) NOT NULL,
+    `url`          varchar(1024) NOT NULL,
+    `storage_key`  varchar(255)  NOT NULL,
+    `content_type` varchar(64)   NOT NULL,
+    `size`         bigint(20) NOT NULL,
+    `position`     int(11) NOT NULL,
+    PRIMARY KEY (`id`),
+    KEY `idx_product_media_product_position` (`product_id`, `position`),
+    CONSTRAINT `fk_product_media_product` FOREIGN KEY (`product_id`) REFERENCES `products` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,5 @@
+-- Adds the merchandiser SKU that bulk imports upsert by. Existing products have none until they
+-- are imported or updated with one; NULLs do not collide in the unique key.
+ALTER TABLE `products`
+    ADD COLUMN `external_sku` varchar(64) DEFAULT NULL AFTER `id`,
+    ADD UNIQUE KEY `uk_products_external_sku` (`external_sku`);
@@ -0,0 +1,20 @@
+-- Adds soft deletion of products and the archive that purged products are moved to. Existing
+-- products are not deleted.
+ALTER TABLE `products`
+    ADD COLUMN `deleted_at` datetime(3) DEFAULT NULL AFTER `category_id`,
+    ADD KEY `idx_products_deleted_at` (`deleted_at`);
+
+CREATE TABLE `products_archive`
+(
+    `id`           int(11) NOT NULL,
+    `external_sku` varchar(64) DEFAULT NULL,
+    `name`         varchar(255) NOT NULL,
+    `description`  text         NOT NULL,
+    `price` double NOT NULL,
+    `stock`        int(11) NOT NULL,
+    `category_id`  int(11) DEFAULT NULL,
+    `deleted_at`   datetime(3) NOT NULL,
+    `archived_at`  datetime(3) NOT NULL,
+    PRIMARY KEY (`id`),
+    KEY `idx_products_archive_external_sku` (`external_sku`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,18 @@
+-- Adds the product lifecycle status and publishing schedule. Every existing product was on sale,
+-- so it becomes active; only products created afterwards start as drafts.
+ALTER TABLE `products`
+    ADD COLUMN `status`       varchar(16) NOT NULL DEFAULT 'active' AFTER `category_id`,
+    ADD COLUMN `publish_at`   datetime(3) DEFAULT NULL AFTER `status`,
+    ADD COLUMN `unpublish_at` datetime(3) DEFAULT NULL AFTER `publish_at`,
+    ADD KEY `idx_products_status_publish_at` (`status`, `publish_at`),
+    ADD KEY `idx_products_status_unpublish_at` (`status`, `unpublish_at`);
+
+ALTER TABLE `products`
+    ALTER COLUMN `status` SET DEFAULT 'draft';
+
+-- Archived products were on sale when they were deleted.
+ALTER TABLE `products_archive`
+    ADD COLUMN `status` varchar(16) NOT NULL DEFAULT 'active' AFTER `category_id`;
+
+ALTER TABLE `products_archive`
+    ALTER COLUMN `status` DROP DEFAULT;
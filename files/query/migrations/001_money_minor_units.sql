@@ -0,0 +1,44 @@
+-- Moves product and variant prices from DOUBLE to integer minor units with a currency code.
+-- Existing prices carry no currency, so set @currency and @exponent (the number of minor-unit
+-- digits of that currency, e.g. 2 for IDR and USD, 0 for JPY) before running it.
+-- Prices are rounded half away from zero, like money.RoundHalfUp.
+SET @currency = 'IDR';
+SET @exponent = 2;
+
+ALTER TABLE `products`
+    ADD COLUMN `price_amount`   bigint(20) DEFAULT NULL COMMENT 'In minor units of price_currency' AFTER `price`,
+    ADD COLUMN `price_currency` char(3)    DEFAULT NULL AFTER `price_amount`;
+
+UPDATE `products`
+SET `price_amount`   = ROUND(CAST(`price` AS DECIMAL(30, 6)) * CAST(POW(10, @exponent) AS UNSIGNED)),
+    `price_currency` = @currency;
+
+ALTER TABLE `products`
+    MODIFY `price_amount`   bigint(20) NOT NULL COMMENT 'In minor units of price_currency',
+    MODIFY `price_currency` char(3)    NOT NULL,
+    DROP COLUMN `price`;
+
+ALTER TABLE `product_variants`
+    ADD COLUMN `price_amount`   bigint(20) DEFAULT NULL AFTER `price`,
+    ADD COLUMN `price_currency` char(3)    DEFAULT NULL AFTER `price_amount`;
+
+UPDATE `product_variants`
+SET `price_amount`   = ROUND(CAST(`price` AS DECIMAL(30, 6)) * CAST(POW(10, @exponent) AS UNSIGNED)),
+    `price_currency` = @currency
+WHERE `price` IS NOT NULL;
+
+ALTER TABLE `product_variants`
+    DROP COLUMN `price`;
+
+ALTER TABLE `products_archive`
+    ADD COLUMN `price_amount`   bigint(20) DEFAULT NULL AFTER `price`,
+    ADD COLUMN `price_currency` char(3)    DEFAULT NULL AFTER `price_amount`;
+
+UPDATE `products_archive`
+SET `price_amount`   = ROUND(CAST(`price` AS DECIMAL(30, 6)) * CAST(POW(10, @exponent) AS UNSIGNED)),
+    `price_currency` = @currency;
+
+ALTER TABLE `products_archive`
+    MODIFY `price_amount`   bigint(20) NOT NULL,
+    MODIFY `price_currency` char(3)    NOT NULL,
+    DROP COLUMN `price`;
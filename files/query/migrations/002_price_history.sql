@@ -0,0 +1,19 @@
+-- Adds the product price history and starts it with the current price of every product.
+CREATE TABLE `product_prices`
+(
+    `id`             bigint(20) NOT NULL AUTO_INCREMENT,
+    `product_id`     int(11)     NOT NULL,
+    `price_amount`   bigint(20) NOT NULL COMMENT 'In minor units of price_currency',
+    `price_currency` char(3)     NOT NULL,
+    `effective_from` datetime(3) NOT NULL,
+    `effective_to`   datetime(3) DEFAULT NULL,
+    `applied_at`     datetime(3) DEFAULT NULL,
+    `created_at`     datetime(3) NOT NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_product_prices_product_from` (`product_id`, `effective_from`),
+    KEY `idx_product_prices_pending` (`applied_at`, `effective_from`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+INSERT INTO `product_prices` (`product_id`, `price_amount`, `price_currency`, `effective_from`, `applied_at`, `created_at`)
+SELECT `id`, `price_amount`, `price_currency`, NOW(3), NOW(3), NOW(3)
+FROM `products`;
@@ -0,0 +1,19 @@
+-- Adds exchange rates and per-currency product price lists.
+CREATE TABLE `exchange_rates`
+(
+    `base_currency`  char(3)        NOT NULL,
+    `quote_currency` char(3)        NOT NULL,
+    `rate`           decimal(20, 10) NOT NULL COMMENT 'Major units of quote_currency per major unit of base_currency',
+    `updated_at`     datetime(3)    NOT NULL,
+    PRIMARY KEY (`base_currency`, `quote_currency`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE `product_currency_prices`
+(
+    `product_id`     int(11)     NOT NULL,
+    `price_amount`   bigint(20) NOT NULL COMMENT 'In minor units of price_currency',
+    `price_currency` char(3)     NOT NULL,
+    `updated_at`     datetime(3) NOT NULL,
+    PRIMARY KEY (`product_id`, `price_currency`),
+    CONSTRAINT `fk_product_currency_prices_product` FOREIGN KEY (`product_id`) REFERENCES `products` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
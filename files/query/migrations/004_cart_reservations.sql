@@ -0,0 +1,19 @@
+-- Adds cart reservations, which hold the stock of several lines under one ID.
+CREATE TABLE `stock_reservations`
+(
+    `id`          char(36)    NOT NULL,
+    `status`      varchar(16) NOT NULL,
+    `created_at`  datetime(3) NOT NULL,
+    `released_at` datetime(3) DEFAULT NULL,
+    PRIMARY KEY (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE `stock_reservation_lines`
+(
+    `reservation_id` char(36) NOT NULL,
+    `product_id`     int(11)  NOT NULL,
+    `variant_id`     int(11)  NOT NULL DEFAULT 0 COMMENT 'Zero for products without variants',
+    `quantity`       int(11)  NOT NULL,
+    PRIMARY KEY (`reservation_id`, `product_id`, `variant_id`),
+    CONSTRAINT `fk_stock_reservation_lines_reservation` FOREIGN KEY (`reservation_id`) REFERENCES `stock_reservations` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
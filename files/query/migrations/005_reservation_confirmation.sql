@@ -0,0 +1,3 @@
+-- Adds confirmation of cart reservations once their order is placed.
+ALTER TABLE `stock_reservations`
+    ADD COLUMN `confirmed_at` datetime(3) DEFAULT NULL AFTER `released_at`;
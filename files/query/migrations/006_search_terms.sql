@@ -0,0 +1,7 @@
+-- Adds the vocabulary of indexed words used to correct typos in MySQL search queries. It is filled
+-- from the active products at startup and as products are indexed.
+CREATE TABLE `search_terms`
+(
+    `term` varchar(64) NOT NULL,
+    PRIMARY KEY (`term`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
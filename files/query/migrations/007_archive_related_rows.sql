@@ -0,0 +1,4 @@
+-- Keeps the variants, media, tags and currency prices of archived products, which are deleted with
+-- the product.
+ALTER TABLE `products_archive`
+    ADD COLUMN `related` json DEFAULT NULL AFTER `deleted_at`;
@@ -0,0 +1,17 @@
+package storage
+
+import (
+	"context"
+	"io"
+)
+
+// Storage persists binary objects such as product images and exposes them through URLs.
+// LocalStorage is the default implementation; an object store (S3, GCS, ...) can be plugged
+// in by implementing the same interface.
+type Storage interface {
+	// Save writes content under key, replacing any existing object, and returns its public URL.
+	Save(ctx context.Context, key string, content io.Reader, contentType string) (string, error)
+
+	// Delete removes the object stored under key. Deleting a missing object is not an error.
+	Delete(ctx context.Context, key string) error
+}
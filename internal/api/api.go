@@ -5,6 +5,7 @@ import (
 	"product-catalog-service/internal/entity"
 	"product-catalog-service/internal/service"
 	"strconv"
+	"strings"
 
 	_ "github.com/golang-jwt/jwt/v5"
 	_ "github.com/labstack/echo-jwt/v4"
@@ -15,12 +16,27 @@ import (
 
 type ProductHandler interface {
 	ReleaseProductStock(c echo.Context) error
+	AdjustProductStock(c echo.Context) error
+	ReserveCart(c echo.Context) error
+	GetReservation(c echo.Context) error
+	ReleaseReservation(c echo.Context) error
+	ConfirmReservation(c echo.Context) error
 	GetProductStock(c echo.Context) error
+	GetProductStocks(c echo.Context) error
 	ReserveProductStock(c echo.Context) error
 	GetAllProducts(c echo.Context) error
 	CreateProduct(c echo.Context) error
+	SearchProducts(c echo.Context) error
+	DeleteProduct(c echo.Context) error
+	RestoreProduct(c echo.Context) error
+	ChangeProductStatus(c echo.Context) error
 }
 
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
 type productHandler struct {
 	ProductService service.ProductService
 }
@@ -38,18 +54,37 @@ func (ph *productHandler) GetProductStock(c echo.Context) error {
 	productIDStr := c.Param("id")
 	productID, err := strconv.ParseInt(productIDStr, 10, 64)
 	if err != nil {
-		return c.JSON(400, map[string]string{"error": "Invalid product ID"})
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid product ID")
 
 	}
 	productStock, err := ph.ProductService.GetProductStock(ctx, productID)
 	if err != nil {
-		return c.JSON(500, map[string]string{"error": "Failed to retrieve product stock"})
+		return err
 	}
 
 	return c.JSON(200, map[string]int{"stock": productStock})
 }
 
-// ReserveProductStock reserves a specified quantity of stock for a product.
+// GetProductStocks retrieves the stock of many products in one call, e.g. for a sale page.
+// products/stock:batch
+func (ph *productHandler) GetProductStocks(c echo.Context) error {
+	var request entity.StockBatchRequest
+	if err := c.Bind(&request); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request format")
+	}
+	if err := c.Validate(&request); err != nil {
+		return err
+	}
+
+	batch, err := ph.ProductService.GetProductStocks(c.Request().Context(), request.ProductIDs)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(200, batch)
+}
+
+// ReserveProductStock reserves a specified quantity of stock for a product, or for one of its variants when variant_id is set.
 // product/reserve
 func (ph *productHandler) ReserveProductStock(c echo.Context) error {
 	var request entity.StockReservation
@@ -57,20 +92,28 @@ func (ph *productHandler) ReserveProductStock(c echo.Context) error {
 	ctx := c.Request().Context()
 	err := c.Bind(&request)
 	if err != nil {
-		return c.JSON(400, map[string]string{"error": "Invalid request format"})
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request format")
+	}
+	if err := c.Validate(&request); err != nil {
+		return err
 	}
 
-	isSuccess, err := ph.ProductService.ReserveProductStock(ctx, request.ProductID, request.Quantity)
+	var isSuccess bool
+	if request.VariantID != 0 {
+		isSuccess, err = ph.ProductService.ReserveVariantStock(ctx, request.ProductID, request.VariantID, request.Quantity)
+	} else {
+		isSuccess, err = ph.ProductService.ReserveProductStock(ctx, request.ProductID, request.Quantity)
+	}
 	if err != nil {
-		return c.JSON(500, map[string]string{"error": "Failed to reserve product stock"})
+		return err
 	} else if !isSuccess {
-		return c.JSON(400, map[string]string{"error": "Insufficient stock available"})
+		return service.ErrInsufficientStock
 	}
 
 	return c.JSON(200, map[string]string{"message": "Product stock reserved successfully"})
 }
 
-// ReleaseProductStock releases a specified quantity of stock for a product.
+// ReleaseProductStock releases a specified quantity of stock for a product, or for one of its variants when variant_id is set.
 // product/release
 func (ph *productHandler) ReleaseProductStock(c echo.Context) error {
 	var request entity.StockReservation
@@ -78,25 +121,124 @@ func (ph *productHandler) ReleaseProductStock(c echo.Context) error {
 
 	err := c.Bind(&request)
 	if err != nil {
-		return c.JSON(400, map[string]string{"error": "Invalid request format"})
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request format")
+	}
+	if err := c.Validate(&request); err != nil {
+		return err
 	}
 
-	isSuccess, err := ph.ProductService.ReleaseProductStock(ctx, request.ProductID, request.Quantity)
+	var isSuccess bool
+	if request.VariantID != 0 {
+		isSuccess, err = ph.ProductService.ReleaseVariantStock(ctx, request.ProductID, request.VariantID, request.Quantity)
+	} else {
+		isSuccess, err = ph.ProductService.ReleaseProductStock(ctx, request.ProductID, request.Quantity)
+	}
 	if err != nil {
-		return c.JSON(500, map[string]string{"error": "Failed to release product stock"})
+		return err
 	} else if !isSuccess {
-		return c.JSON(400, map[string]string{"error": "Failed to release product stock"})
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to release product stock")
 	}
 
 	return c.JSON(200, map[string]string{"message": "Product stock released successfully"})
 }
 
+// ReserveCart reserves every line of a cart under one reservation ID, or none of them. A rejected
+// cart is reported with the availability of every line.
+// cart/reserve
+func (ph *productHandler) ReserveCart(c echo.Context) error {
+	var request entity.CartReservationRequest
+	if err := c.Bind(&request); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request format")
+	}
+	if err := c.Validate(&request); err != nil {
+		return err
+	}
+
+	reservation, availability, err := ph.ProductService.ReserveCart(c.Request().Context(), request.Lines)
+	if err != nil {
+		if availability != nil {
+			return withProblemDetails(err, map[string]interface{}{"lines": availability})
+		}
+		return err
+	}
+
+	return c.JSON(200, reservation)
+}
+
+// GetReservation retrieves a cart reservation by its ID.
+// cart/reservation/{id}
+func (ph *productHandler) GetReservation(c echo.Context) error {
+	reservation, err := ph.ProductService.GetReservation(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(200, reservation)
+}
+
+// ReleaseReservation returns the stock of every line of a cart reservation.
+// cart/reservation/{id}/release
+func (ph *productHandler) ReleaseReservation(c echo.Context) error {
+	reservation, err := ph.ProductService.ReleaseReservation(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(200, reservation)
+}
+
+// ConfirmReservation makes a cart reservation final once its order is placed.
+// cart/reservation/{id}/confirm
+func (ph *productHandler) ConfirmReservation(c echo.Context) error {
+	reservation, err := ph.ProductService.ConfirmReservation(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(200, reservation)
+}
+
+// AdjustProductStock corrects the stock of a product by a delta, e.g. after a stock take.
+// admin/product/{id}/stock
+func (ph *productHandler) AdjustProductStock(c echo.Context) error {
+	productID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid product ID")
+	}
+
+	var request entity.StockAdjustment
+	if err := c.Bind(&request); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request format")
+	}
+	if err := c.Validate(&request); err != nil {
+		return err
+	}
+
+	stock, err := ph.ProductService.AdjustProductStock(c.Request().Context(), productID, request.Delta)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(200, map[string]int{"stock": stock})
+}
+
+// GetAllProducts lists products, optionally filtered by category subtree and tag, with prices in the requested currency.
+// products?category_id={id}&tag={tag}&currency={currency}
 func (ph *productHandler) GetAllProducts(c echo.Context) error {
 	ctx := c.Request().Context()
 
-	products, err := ph.ProductService.GetAllProducts(ctx)
+	filter := entity.ProductFilter{Tag: c.QueryParam("tag")}
+	if categoryIDStr := c.QueryParam("category_id"); categoryIDStr != "" {
+		categoryID, err := strconv.ParseInt(categoryIDStr, 10, 64)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid category ID")
+		}
+		filter.CategoryID = categoryID
+	}
+
+	products, err := ph.ProductService.GetAllProducts(ctx, filter, c.QueryParam("currency"))
 	if err != nil {
-		return c.JSON(500, map[string]string{"error": "Failed to retrieve products"})
+		return err
 	}
 
 	return c.JSON(200, products)
@@ -108,13 +250,97 @@ func (ph *productHandler) CreateProduct(c echo.Context) error {
 
 	err := c.Bind(&product)
 	if err != nil {
-		return c.JSON(400, map[string]string{"error": "Invalid product data"})
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid product data")
+	}
+	if err := c.Validate(&product); err != nil {
+		return err
 	}
 
 	err = ph.ProductService.CreateProduct(ctx, &product)
 	if err != nil {
-		return c.JSON(500, map[string]string{"error": "Failed to create product"})
+		return err
 	}
 
 	return c.JSON(http.StatusCreated, "Product created successfully")
 }
+
+// SearchProducts performs a full-text search over product names and descriptions.
+// products/search?q={query}&limit={limit}&currency={currency}
+func (ph *productHandler) SearchProducts(c echo.Context) error {
+	ctx := c.Request().Context()
+	query := strings.TrimSpace(c.QueryParam("q"))
+	if query == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Search query is required")
+	}
+
+	limit := defaultSearchLimit
+	if limitStr := c.QueryParam("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed <= 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid limit")
+		}
+		limit = min(parsed, maxSearchLimit)
+	}
+
+	results, err := ph.ProductService.SearchProducts(ctx, query, limit, c.QueryParam("currency"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(200, results)
+}
+
+// DeleteProduct soft-deletes a product; it can be restored until the archive job purges it.
+// product/{id}
+func (ph *productHandler) DeleteProduct(c echo.Context) error {
+	productID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid product ID")
+	}
+
+	if err := ph.ProductService.DeleteProduct(c.Request().Context(), productID); err != nil {
+		return err
+	}
+
+	return c.JSON(200, map[string]string{"message": "Product deleted successfully"})
+}
+
+// RestoreProduct brings a soft-deleted product back.
+// admin/product/{id}/restore
+func (ph *productHandler) RestoreProduct(c echo.Context) error {
+	productID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid product ID")
+	}
+
+	product, err := ph.ProductService.RestoreProduct(c.Request().Context(), productID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(200, product)
+}
+
+// ChangeProductStatus moves a product through its lifecycle: draft, scheduled, active and archived.
+// product/{id}/status
+func (ph *productHandler) ChangeProductStatus(c echo.Context) error {
+	productID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid product ID")
+	}
+
+	var change entity.ProductStatusChange
+	if err := c.Bind(&change); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+	if err := c.Validate(&change); err != nil {
+		return err
+	}
+
+	product, err := ph.ProductService.ChangeProductStatus(c.Request().Context(), productID, change)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(200, product)
+}
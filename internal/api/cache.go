@@ -0,0 +1,28 @@
+package api
+
+import (
+	"product-catalog-service/internal/service"
+
+	"github.com/labstack/echo/v4"
+)
+
+type CacheHandler interface {
+	GetCacheStats(c echo.Context) error
+}
+
+type cacheHandler struct {
+	CacheService service.CacheService
+}
+
+func NewCacheHandler(cacheService service.CacheService) CacheHandler {
+	return &cacheHandler{
+		CacheService: cacheService,
+	}
+}
+
+// GetCacheStats returns the counters of the in-process cache of the instance that serves the
+// request.
+// admin/cache/stats
+func (ch *cacheHandler) GetCacheStats(c echo.Context) error {
+	return c.JSON(200, ch.CacheService.Stats())
+}
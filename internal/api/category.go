@@ -0,0 +1,212 @@
+package api
+
+import (
+	"net/http"
+	"product-catalog-service/internal/entity"
+	"product-catalog-service/internal/service"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type CategoryHandler interface {
+	GetCategories(c echo.Context) error
+	GetCategory(c echo.Context) error
+	CreateCategory(c echo.Context) error
+	UpdateCategory(c echo.Context) error
+	DeleteCategory(c echo.Context) error
+	GetTags(c echo.Context) error
+	CreateTag(c echo.Context) error
+	DeleteTag(c echo.Context) error
+	AssignProductCategory(c echo.Context) error
+	SetProductTags(c echo.Context) error
+}
+
+type categoryHandler struct {
+	CategoryService service.CategoryService
+}
+
+func NewCategoryHandler(categoryService service.CategoryService) CategoryHandler {
+	return &categoryHandler{
+		CategoryService: categoryService,
+	}
+}
+
+// GetCategories lists every category; clients build the tree from parent_id.
+// categories
+func (ch *categoryHandler) GetCategories(c echo.Context) error {
+	categories, err := ch.CategoryService.GetCategories(c.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(200, categories)
+}
+
+// GetCategory retrieves a single category by its ID.
+// category/{id}
+func (ch *categoryHandler) GetCategory(c echo.Context) error {
+	categoryID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid category ID")
+	}
+
+	category, err := ch.CategoryService.GetCategory(c.Request().Context(), categoryID)
+	if err != nil {
+		return err
+	}
+	if category == nil {
+		return service.ErrCategoryNotFound
+	}
+
+	return c.JSON(200, category)
+}
+
+// CreateCategory creates a root category, or a child category when parent_id is set.
+// category
+func (ch *categoryHandler) CreateCategory(c echo.Context) error {
+	var category entity.Category
+	if err := c.Bind(&category); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid category data")
+	}
+	if err := c.Validate(&category); err != nil {
+		return err
+	}
+	category.ID = 0
+
+	if err := ch.CategoryService.CreateCategory(c.Request().Context(), &category); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, category)
+}
+
+// UpdateCategory renames a category or moves it under another parent.
+// category/{id}
+func (ch *categoryHandler) UpdateCategory(c echo.Context) error {
+	categoryID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid category ID")
+	}
+
+	var category entity.Category
+	if err := c.Bind(&category); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid category data")
+	}
+	if err := c.Validate(&category); err != nil {
+		return err
+	}
+	category.ID = categoryID
+
+	if err := ch.CategoryService.UpdateCategory(c.Request().Context(), &category); err != nil {
+		return err
+	}
+
+	return c.JSON(200, category)
+}
+
+// DeleteCategory deletes a category that has no child categories.
+// category/{id}
+func (ch *categoryHandler) DeleteCategory(c echo.Context) error {
+	categoryID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid category ID")
+	}
+
+	if err := ch.CategoryService.DeleteCategory(c.Request().Context(), categoryID); err != nil {
+		return err
+	}
+
+	return c.JSON(200, map[string]string{"message": "Category deleted successfully"})
+}
+
+// GetTags lists every tag.
+// tags
+func (ch *categoryHandler) GetTags(c echo.Context) error {
+	tags, err := ch.CategoryService.GetTags(c.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(200, tags)
+}
+
+// CreateTag creates a new tag.
+// tag
+func (ch *categoryHandler) CreateTag(c echo.Context) error {
+	var tag entity.Tag
+	if err := c.Bind(&tag); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid tag data")
+	}
+	if err := c.Validate(&tag); err != nil {
+		return err
+	}
+	tag.ID = 0
+
+	if err := ch.CategoryService.CreateTag(c.Request().Context(), &tag); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, tag)
+}
+
+// DeleteTag deletes a tag and detaches it from every product.
+// tag/{id}
+func (ch *categoryHandler) DeleteTag(c echo.Context) error {
+	tagID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid tag ID")
+	}
+
+	if err := ch.CategoryService.DeleteTag(c.Request().Context(), tagID); err != nil {
+		return err
+	}
+
+	return c.JSON(200, map[string]string{"message": "Tag deleted successfully"})
+}
+
+// AssignProductCategory moves a product into a category, or out of any category when category_id is null.
+// product/{id}/category
+func (ch *categoryHandler) AssignProductCategory(c echo.Context) error {
+	productID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid product ID")
+	}
+
+	var request entity.CategoryAssignment
+	if err := c.Bind(&request); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request format")
+	}
+	if err := c.Validate(&request); err != nil {
+		return err
+	}
+
+	if err := ch.CategoryService.AssignProductCategory(c.Request().Context(), productID, request.CategoryID); err != nil {
+		return err
+	}
+
+	return c.JSON(200, map[string]string{"message": "Product category assigned successfully"})
+}
+
+// SetProductTags replaces the tags of a product.
+// product/{id}/tags
+func (ch *categoryHandler) SetProductTags(c echo.Context) error {
+	productID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid product ID")
+	}
+
+	var request entity.TagAssignment
+	if err := c.Bind(&request); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request format")
+	}
+	if err := c.Validate(&request); err != nil {
+		return err
+	}
+
+	if err := ch.CategoryService.SetProductTags(c.Request().Context(), productID, request.Tags); err != nil {
+		return err
+	}
+
+	return c.JSON(200, map[string]string{"message": "Product tags updated successfully"})
+}
@@ -0,0 +1,126 @@
+package api
+
+import (
+	"net/http"
+	"product-catalog-service/internal/entity"
+	"product-catalog-service/internal/service"
+	"product-catalog-service/pkg/money"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type CurrencyHandler interface {
+	GetExchangeRates(c echo.Context) error
+	SetExchangeRate(c echo.Context) error
+	DeleteExchangeRate(c echo.Context) error
+	GetCurrencyPrices(c echo.Context) error
+	SetCurrencyPrice(c echo.Context) error
+	DeleteCurrencyPrice(c echo.Context) error
+}
+
+type currencyHandler struct {
+	CurrencyService service.CurrencyService
+}
+
+func NewCurrencyHandler(currencyService service.CurrencyService) CurrencyHandler {
+	return &currencyHandler{
+		CurrencyService: currencyService,
+	}
+}
+
+// GetExchangeRates lists the configured exchange rates.
+// exchange-rates
+func (ch *currencyHandler) GetExchangeRates(c echo.Context) error {
+	rates, err := ch.CurrencyService.GetExchangeRates(c.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(200, rates)
+}
+
+// SetExchangeRate creates or replaces the rate from base_currency to quote_currency.
+// exchange-rate
+func (ch *currencyHandler) SetExchangeRate(c echo.Context) error {
+	var rate entity.ExchangeRate
+	if err := c.Bind(&rate); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid exchange rate data")
+	}
+	if err := c.Validate(&rate); err != nil {
+		return err
+	}
+
+	if err := ch.CurrencyService.SetExchangeRate(c.Request().Context(), &rate); err != nil {
+		return err
+	}
+
+	return c.JSON(200, rate)
+}
+
+// DeleteExchangeRate removes the rate from base to quote.
+// exchange-rate/{base}/{quote}
+func (ch *currencyHandler) DeleteExchangeRate(c echo.Context) error {
+	err := ch.CurrencyService.DeleteExchangeRate(c.Request().Context(), c.Param("base"), c.Param("quote"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(200, map[string]string{"message": "Exchange rate deleted successfully"})
+}
+
+// GetCurrencyPrices lists the explicit prices of a product in other currencies.
+// product/{id}/currency-prices
+func (ch *currencyHandler) GetCurrencyPrices(c echo.Context) error {
+	productID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid product ID")
+	}
+
+	prices, err := ch.CurrencyService.GetCurrencyPrices(c.Request().Context(), productID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(200, prices)
+}
+
+// SetCurrencyPrice sets the price of a product in a currency other than its own.
+// product/{id}/currency-price
+func (ch *currencyHandler) SetCurrencyPrice(c echo.Context) error {
+	productID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid product ID")
+	}
+
+	var price money.Money
+	if err := c.Bind(&price); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid price data")
+	}
+	if err := c.Validate(&price); err != nil {
+		return err
+	}
+
+	currencyPrice, err := ch.CurrencyService.SetCurrencyPrice(c.Request().Context(), productID, price)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(200, currencyPrice)
+}
+
+// DeleteCurrencyPrice removes the price of a product in a currency, which falls back to conversion.
+// product/{id}/currency-price/{currency}
+func (ch *currencyHandler) DeleteCurrencyPrice(c echo.Context) error {
+	productID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid product ID")
+	}
+
+	err = ch.CurrencyService.DeleteCurrencyPrice(c.Request().Context(), productID, c.Param("currency"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(200, map[string]string{"message": "Currency price deleted successfully"})
+}
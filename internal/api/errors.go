@@ -0,0 +1,147 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"product-catalog-service/infrastructure/log"
+	"product-catalog-service/internal/apperror"
+	"product-catalog-service/internal/validation"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// MIMEApplicationProblemJSON is the media type of error responses (RFC 9457).
+const MIMEApplicationProblemJSON = "application/problem+json"
+
+// Problem is the body of every error response. Code is stable and meant for clients to match on;
+// Detail is a human-readable message that may change. Validation failures list the broken rules
+// of each field in an "errors" member.
+type Problem struct {
+	Type      string `json:"type"`
+	Title     string `json:"title"`
+	Status    int    `json:"status"`
+	Code      string `json:"code"`
+	Detail    string `json:"detail"`
+	RequestID string `json:"request_id,omitempty"`
+	// Extensions are additional members merged into the body, e.g. the partial report of a failed import.
+	Extensions map[string]interface{} `json:"-"`
+}
+
+// MarshalJSON writes the extension members next to the standard ones.
+func (p Problem) MarshalJSON() ([]byte, error) {
+	type problem Problem
+	body, err := json.Marshal(problem(p))
+	if err != nil || len(p.Extensions) == 0 {
+		return body, err
+	}
+
+	members := make(map[string]interface{}, len(p.Extensions)+6)
+	for name, value := range p.Extensions {
+		members[name] = value
+	}
+	if err := json.Unmarshal(body, &members); err != nil {
+		return nil, err
+	}
+	return json.Marshal(members)
+}
+
+// problemDetailsError attaches extension members to the problem body of an error.
+type problemDetailsError struct {
+	error
+	extensions map[string]interface{}
+}
+
+func (e *problemDetailsError) Unwrap() error {
+	return e.error
+}
+
+// withProblemDetails returns err with extension members for its problem body.
+func withProblemDetails(err error, extensions map[string]interface{}) error {
+	return &problemDetailsError{error: err, extensions: extensions}
+}
+
+// HTTPErrorHandler writes every error returned by a handler or middleware as a problem+json body.
+// Domain errors are mapped by kind, echo.HTTPError keeps its status, and anything else is logged
+// and reported as an opaque 500 so that internal details do not leak to clients.
+func HTTPErrorHandler(err error, c echo.Context) {
+	if c.Response().Committed {
+		return
+	}
+
+	problem := newProblem(err)
+	problem.RequestID = c.Response().Header().Get(echo.HeaderXRequestID)
+	if problem.Status >= http.StatusInternalServerError {
+		log.Logger.Error().Err(err).Str("requestID", problem.RequestID).
+			Str("method", c.Request().Method).Str("path", c.Path()).Msg("Request failed")
+	}
+
+	if c.Request().Method == http.MethodHead {
+		err = c.NoContent(problem.Status)
+	} else {
+		var body []byte
+		if body, err = json.Marshal(problem); err == nil {
+			err = c.Blob(problem.Status, MIMEApplicationProblemJSON, body)
+		}
+	}
+	if err != nil {
+		log.Logger.Error().Err(err).Str("requestID", problem.RequestID).Msg("Failed to write error response")
+	}
+}
+
+// newProblem builds the problem body of an error.
+func newProblem(err error) Problem {
+	var problem Problem
+
+	var appErr *apperror.Error
+	var httpErr *echo.HTTPError
+	switch {
+	case errors.As(err, &appErr):
+		problem = Problem{Status: statusOf(appErr.Kind), Code: appErr.Code, Detail: err.Error()}
+	case errors.As(err, &httpErr):
+		problem = Problem{Status: httpErr.Code, Code: codeOf(httpErr.Code), Detail: fmt.Sprint(httpErr.Message)}
+	default:
+		problem = Problem{Status: http.StatusInternalServerError, Code: "internal_error", Detail: "Internal server error"}
+	}
+	problem.Type = "about:blank"
+	problem.Title = http.StatusText(problem.Status)
+
+	var detailsErr *problemDetailsError
+	if errors.As(err, &detailsErr) {
+		problem.Extensions = detailsErr.extensions
+	}
+	var validationErr *validation.Error
+	if errors.As(err, &validationErr) {
+		problem.Extensions = map[string]interface{}{"errors": validationErr.Fields}
+	}
+	return problem
+}
+
+// statusOf maps the kind of a domain error to an HTTP status.
+func statusOf(kind apperror.Kind) int {
+	switch kind {
+	case apperror.KindInvalid:
+		return http.StatusUnprocessableEntity
+	case apperror.KindNotFound:
+		return http.StatusNotFound
+	case apperror.KindConflict:
+		return http.StatusConflict
+	case apperror.KindUnsupported:
+		return http.StatusUnsupportedMediaType
+	case apperror.KindTooLarge:
+		return http.StatusRequestEntityTooLarge
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// codeOf derives an error code from an HTTP status, e.g. "bad_request" for 400.
+func codeOf(status int) string {
+	text := http.StatusText(status)
+	if text == "" {
+		return "error"
+	}
+	return strings.ReplaceAll(strings.ToLower(text), " ", "_")
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"product-catalog-service/infrastructure/log"
+	"product-catalog-service/internal/entity"
+	"product-catalog-service/internal/service"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	headerExportSnapshotAt = "X-Export-Snapshot-At"
+	headerExportChecksum   = "X-Export-Checksum"
+	headerExportRows       = "X-Export-Rows"
+)
+
+// exportContentTypes maps export formats to their response content type.
+var exportContentTypes = map[entity.ExportFormat]string{
+	entity.ExportFormatCSV:     "text/csv",
+	entity.ExportFormatJSONL:   "application/x-ndjson",
+	entity.ExportFormatParquet: "application/vnd.apache.parquet",
+}
+
+type ExportHandler interface {
+	ExportProducts(c echo.Context) error
+}
+
+type exportHandler struct {
+	ExportService service.ExportService
+}
+
+func NewExportHandler(exportService service.ExportService) ExportHandler {
+	return &exportHandler{
+		ExportService: exportService,
+	}
+}
+
+// ExportProducts streams the catalogue with current stock as of a consistent snapshot.
+// The snapshot time is sent in the X-Export-Snapshot-At header; the SHA-256 checksum of the body and
+// the row count follow as HTTP trailers once the export has completed.
+// products/export?format={csv|jsonl|parquet}
+func (eh *exportHandler) ExportProducts(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	opts := entity.ExportOptions{Format: entity.ExportFormat(c.QueryParam("format"))}
+	if opts.Format == "" {
+		opts.Format = entity.ExportFormatCSV
+	}
+	contentType, ok := exportContentTypes[opts.Format]
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, "Format must be csv, jsonl or parquet")
+	}
+
+	res := c.Response()
+	started := false
+	begin := func(snapshotAt time.Time) {
+		started = true
+		res.Header().Set(echo.HeaderContentType, contentType)
+		res.Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="products-%s.%s"`, snapshotAt.Format("20060102T150405Z"), opts.Format))
+		res.Header().Set(headerExportSnapshotAt, snapshotAt.Format(time.RFC3339Nano))
+		res.Header().Set("Trailer", headerExportChecksum+", "+headerExportRows)
+		res.WriteHeader(http.StatusOK)
+	}
+
+	summary, err := eh.ExportService.ExportProducts(ctx, res, opts, begin)
+	if err != nil {
+		if started {
+			// The body is already partially sent; omitting the checksum trailer tells the client it is incomplete.
+			log.Logger.Error().Err(err).Msg("Product export aborted after streaming started")
+			return nil
+		}
+		return err
+	}
+
+	res.Header().Set(headerExportChecksum, "sha256="+summary.Checksum)
+	res.Header().Set(headerExportRows, strconv.Itoa(summary.Rows))
+	return nil
+}
@@ -0,0 +1,131 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"mime"
+	"net/http"
+	"product-catalog-service/internal/entity"
+	"product-catalog-service/internal/service"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// HeaderIdempotencyKey carries the key that makes a POST request safe to retry.
+	HeaderIdempotencyKey = "Idempotency-Key"
+	// HeaderIdempotentReplayed is set on a response replayed for a retried request.
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+
+	// maxIdempotentBodyBytes bounds the request and response bodies read and kept for a key.
+	maxIdempotentBodyBytes = 1 << 20
+)
+
+// Idempotency makes POST requests with an Idempotency-Key header safe to retry. The first request
+// with a key is handled and its response kept; a retry with the same key and body gets that
+// response back, a retry while the first is still being handled is rejected with a conflict, and
+// reusing the key for another request is rejected as invalid. Keys are scoped to the bearer token.
+// The path is compared without versionPrefix, so a retry may move between a deprecated alias and
+// the versioned route.
+//
+// Server errors are not kept, so that the retry is handled again. Only requests with a JSON body or
+// no body are covered; uploads and imports stream bodies too large to fingerprint.
+func Idempotency(idempotencyService service.IdempotencyService, versionPrefix string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			if req.Method != http.MethodPost || len(req.Header.Values(HeaderIdempotencyKey)) == 0 || !hasJSONBody(req) {
+				return next(c)
+			}
+			key := req.Header.Get(HeaderIdempotencyKey)
+
+			body, err := io.ReadAll(io.LimitReader(req.Body, maxIdempotentBodyBytes+1))
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, "failed to read request body")
+			}
+			if len(body) > maxIdempotentBodyBytes {
+				return echo.NewHTTPError(http.StatusRequestEntityTooLarge, "request body too large for an idempotent request")
+			}
+			req.Body = io.NopCloser(bytes.NewReader(body))
+
+			scope := fingerprint([]byte(req.Header.Get(echo.HeaderAuthorization)))
+			target := req.URL.RequestURI()
+			if unversioned, ok := strings.CutPrefix(target, versionPrefix); ok && strings.HasPrefix(unversioned, "/") {
+				target = unversioned
+			}
+			requestFingerprint := fingerprint([]byte(req.Method+" "+target+"\n"), body)
+			ctx := req.Context()
+			replay, err := idempotencyService.Begin(ctx, scope, key, requestFingerprint)
+			if err != nil {
+				return err
+			}
+			if replay != nil {
+				c.Response().Header().Set(HeaderIdempotentReplayed, "true")
+				if replay.ContentType == "" {
+					return c.NoContent(replay.StatusCode)
+				}
+				return c.Blob(replay.StatusCode, replay.ContentType, replay.Body)
+			}
+
+			recorder := &responseRecorder{ResponseWriter: c.Response().Writer}
+			c.Response().Writer = recorder
+			// Errors are written here rather than returned, so that their response is recorded too.
+			if err := next(c); err != nil {
+				c.Error(err)
+			}
+
+			// The request may have timed out, but its outcome must still be kept.
+			ctx = context.WithoutCancel(ctx)
+			status := c.Response().Status
+			if status >= http.StatusInternalServerError || recorder.truncated {
+				idempotencyService.Abandon(ctx, scope, key)
+				return nil
+			}
+			idempotencyService.Complete(ctx, scope, key, entity.IdempotencyRecord{
+				Fingerprint: requestFingerprint,
+				StatusCode:  status,
+				ContentType: c.Response().Header().Get(echo.HeaderContentType),
+				Body:        recorder.body.Bytes(),
+			})
+			return nil
+		}
+	}
+}
+
+// hasJSONBody reports whether the request body is JSON or empty.
+func hasJSONBody(req *http.Request) bool {
+	if req.ContentLength == 0 {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(req.Header.Get(echo.HeaderContentType))
+	return err == nil && mediaType == echo.MIMEApplicationJSON
+}
+
+// fingerprint returns the hex SHA-256 of parts.
+func fingerprint(parts ...[]byte) string {
+	hash := sha256.New()
+	for _, part := range parts {
+		hash.Write(part)
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// responseRecorder keeps a copy of the response body while it is written.
+type responseRecorder struct {
+	http.ResponseWriter
+	body      bytes.Buffer
+	truncated bool
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	if r.body.Len()+len(b) > maxIdempotentBodyBytes {
+		r.truncated = true
+	} else {
+		r.body.Write(b)
+	}
+	return r.ResponseWriter.Write(b)
+}
@@ -0,0 +1,77 @@
+package api
+
+import (
+	"mime"
+	"net/http"
+	"product-catalog-service/infrastructure/log"
+	"product-catalog-service/internal/entity"
+	"product-catalog-service/internal/service"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type ImportHandler interface {
+	ImportProducts(c echo.Context) error
+}
+
+type importHandler struct {
+	ImportService service.ImportService
+}
+
+func NewImportHandler(importService service.ImportService) ImportHandler {
+	return &importHandler{
+		ImportService: importService,
+	}
+}
+
+// ImportProducts upserts products from a CSV or JSON Lines request body and returns a per-row report.
+// The format is taken from the format query parameter, or else from the Content-Type header.
+// products/import?format={csv|jsonl}&dry_run={bool}&batch_size={n}
+func (ih *importHandler) ImportProducts(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	opts := entity.ImportOptions{Format: entity.ImportFormat(c.QueryParam("format"))}
+	if opts.Format == "" {
+		opts.Format = importFormatFromContentType(c.Request().Header.Get(echo.HeaderContentType))
+	}
+	if dryRun := c.QueryParam("dry_run"); dryRun != "" {
+		parsed, err := strconv.ParseBool(dryRun)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid dry_run flag")
+		}
+		opts.DryRun = parsed
+	}
+	if batchSize := c.QueryParam("batch_size"); batchSize != "" {
+		parsed, err := strconv.Atoi(batchSize)
+		if err != nil || parsed <= 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid batch size")
+		}
+		opts.BatchSize = parsed
+	}
+
+	progress := func(p entity.ImportProgress) {
+		log.Logger.Info().Int("rowsRead", p.RowsRead).Int("rowsInvalid", p.RowsInvalid).Int("rowsUpserted", p.RowsUpserted).Msg("Product import progress")
+	}
+
+	report, err := ih.ImportService.ImportProducts(ctx, c.Request().Body, opts, progress)
+	if err != nil {
+		// Batches before the failure are committed, so the report tells the client where to resume.
+		return withProblemDetails(err, map[string]interface{}{"report": report})
+	}
+
+	return c.JSON(200, report)
+}
+
+// importFormatFromContentType maps a request Content-Type to an import format.
+func importFormatFromContentType(contentType string) entity.ImportFormat {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "text/csv":
+		return entity.ImportFormatCSV
+	case "application/x-ndjson", "application/jsonl", "application/x-jsonlines":
+		return entity.ImportFormatJSONL
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,111 @@
+package api
+
+import (
+	"net/http"
+	"product-catalog-service/internal/entity"
+	"product-catalog-service/internal/service"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type MediaHandler interface {
+	UploadMedia(c echo.Context) error
+	GetMedia(c echo.Context) error
+	DeleteMedia(c echo.Context) error
+	ReorderMedia(c echo.Context) error
+}
+
+type mediaHandler struct {
+	MediaService service.MediaService
+}
+
+func NewMediaHandler(mediaService service.MediaService) MediaHandler {
+	return &mediaHandler{
+		MediaService: mediaService,
+	}
+}
+
+// UploadMedia accepts a multipart image upload in the "file" field and appends it to the product.
+// product/{id}/media
+func (mh *mediaHandler) UploadMedia(c echo.Context) error {
+	productID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid product ID")
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Missing file upload")
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid file upload")
+	}
+	defer file.Close()
+
+	media, err := mh.MediaService.UploadMedia(c.Request().Context(), productID, file)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, media)
+}
+
+// GetMedia lists the media of a product in display order.
+// product/{id}/media
+func (mh *mediaHandler) GetMedia(c echo.Context) error {
+	productID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid product ID")
+	}
+
+	media, err := mh.MediaService.GetMedia(c.Request().Context(), productID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(200, media)
+}
+
+// DeleteMedia removes an image from a product and from storage.
+// product/{id}/media/{mediaId}
+func (mh *mediaHandler) DeleteMedia(c echo.Context) error {
+	productID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid product ID")
+	}
+	mediaID, err := strconv.ParseInt(c.Param("mediaId"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid media ID")
+	}
+
+	if err := mh.MediaService.DeleteMedia(c.Request().Context(), productID, mediaID); err != nil {
+		return err
+	}
+
+	return c.JSON(200, map[string]string{"message": "Media deleted successfully"})
+}
+
+// ReorderMedia sets the display order of the media of a product.
+// product/{id}/media/order
+func (mh *mediaHandler) ReorderMedia(c echo.Context) error {
+	productID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid product ID")
+	}
+
+	var request entity.MediaOrder
+	if err := c.Bind(&request); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request format")
+	}
+	if err := c.Validate(&request); err != nil {
+		return err
+	}
+
+	if err := mh.MediaService.ReorderMedia(c.Request().Context(), productID, request.MediaIDs); err != nil {
+		return err
+	}
+
+	return c.JSON(200, map[string]string{"message": "Media reordered successfully"})
+}
@@ -0,0 +1,98 @@
+package api
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// openAPISpec is the OpenAPI 3 document of version 1 of the API, the routes under /v1 in
+// routes.SetupRoutes. It is the contract the client package in pkg/client is written against.
+//
+//go:embed openapi.json
+var openAPISpec []byte
+
+// GetOpenAPISpec serves the OpenAPI document of the service.
+// openapi.json
+func GetOpenAPISpec(c echo.Context) error {
+	return c.Blob(http.StatusOK, echo.MIMEApplicationJSONCharsetUTF8, openAPISpec)
+}
+
+// VerifyRoutes compares the routes registered under prefix, the server URL of the OpenAPI
+// document, with the operations of the document and reports every route that is not documented
+// and every operation that has no route, so handlers and the document cannot drift apart
+// unnoticed. Routes outside prefix, such as static files and deprecated aliases, are skipped.
+func VerifyRoutes(routes []*echo.Route, prefix string) error {
+	var spec struct {
+		Servers []struct {
+			URL string `json:"url"`
+		} `json:"servers"`
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(openAPISpec, &spec); err != nil {
+		return fmt.Errorf("invalid OpenAPI document: %w", err)
+	}
+	if len(spec.Servers) != 1 || spec.Servers[0].URL != prefix {
+		return fmt.Errorf("OpenAPI document must have the single server URL %q", prefix)
+	}
+
+	documented := make(map[string]bool)
+	for path, operations := range spec.Paths {
+		for method := range operations {
+			documented[strings.ToUpper(method)+" "+path] = true
+		}
+	}
+
+	registered := make(map[string]bool)
+	var undocumented []string
+	for _, route := range routes {
+		path, ok := strings.CutPrefix(route.Path, prefix)
+		if !ok || !strings.HasPrefix(path, "/") || strings.Contains(path, "*") {
+			continue
+		}
+		operation := route.Method + " " + openAPIPath(path)
+		registered[operation] = true
+		if !documented[operation] {
+			undocumented = append(undocumented, operation)
+		}
+	}
+	var unrouted []string
+	for operation := range documented {
+		if !registered[operation] {
+			unrouted = append(unrouted, operation)
+		}
+	}
+
+	if len(undocumented) == 0 && len(unrouted) == 0 {
+		return nil
+	}
+	sort.Strings(undocumented)
+	sort.Strings(unrouted)
+	var problems []string
+	if len(undocumented) > 0 {
+		problems = append(problems, "routes missing from the OpenAPI document: "+strings.Join(undocumented, ", "))
+	}
+	if len(unrouted) > 0 {
+		problems = append(problems, "documented operations without a route: "+strings.Join(unrouted, ", "))
+	}
+	return fmt.Errorf("OpenAPI document does not match the routes: %s", strings.Join(problems, "; "))
+}
+
+// openAPIPath converts an Echo route path to an OpenAPI path template, e.g. "/product/:id" to
+// "/product/{id}". Escaped colons such as in "/products/stock\\:batch" are literal.
+func openAPIPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") {
+			segments[i] = "{" + segment[1:] + "}"
+		} else {
+			segments[i] = strings.ReplaceAll(segment, `\:`, ":")
+		}
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"net/http"
+	"product-catalog-service/internal/entity"
+	"product-catalog-service/internal/service"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type PriceHandler interface {
+	ChangePrice(c echo.Context) error
+	GetPrice(c echo.Context) error
+	GetPriceHistory(c echo.Context) error
+}
+
+type priceHandler struct {
+	PriceService service.PriceService
+}
+
+func NewPriceHandler(priceService service.PriceService) PriceHandler {
+	return &priceHandler{
+		PriceService: priceService,
+	}
+}
+
+// ChangePrice changes the price of a product now, or at effective_from when it is in the future.
+// product/{id}/price
+func (ph *priceHandler) ChangePrice(c echo.Context) error {
+	productID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid product ID")
+	}
+
+	var req entity.PriceChangeRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid price data")
+	}
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+
+	change, err := ph.PriceService.ChangePrice(c.Request().Context(), productID, req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(200, change)
+}
+
+// GetPrice returns the price of a product at a point in time, now by default.
+// product/{id}/price?at={RFC 3339 timestamp}
+func (ph *priceHandler) GetPrice(c echo.Context) error {
+	productID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid product ID")
+	}
+
+	at := time.Now()
+	if v := c.QueryParam("at"); v != "" {
+		if at, err = time.Parse(time.RFC3339Nano, v); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid at parameter, expected an RFC 3339 timestamp")
+		}
+	}
+
+	change, err := ph.PriceService.GetPriceAt(c.Request().Context(), productID, at)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(200, change)
+}
+
+// GetPriceHistory lists every past, current and scheduled price of a product.
+// product/{id}/prices
+func (ph *priceHandler) GetPriceHistory(c echo.Context) error {
+	productID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid product ID")
+	}
+
+	history, err := ph.PriceService.GetPriceHistory(c.Request().Context(), productID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(200, history)
+}
@@ -0,0 +1,42 @@
+package api
+
+import (
+	"net/http"
+	"product-catalog-service/internal/entity"
+	"product-catalog-service/internal/service"
+
+	"github.com/labstack/echo/v4"
+)
+
+type PricingHandler interface {
+	QuotePrices(c echo.Context) error
+}
+
+type pricingHandler struct {
+	PricingService service.PricingService
+}
+
+func NewPricingHandler(pricingService service.PricingService) PricingHandler {
+	return &pricingHandler{
+		PricingService: pricingService,
+	}
+}
+
+// QuotePrices returns the authoritative unit and line prices for a set of order lines.
+// pricing/quote
+func (ph *pricingHandler) QuotePrices(c echo.Context) error {
+	var req entity.PriceQuoteRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid quote request")
+	}
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+
+	quote, err := ph.PricingService.QuotePrices(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(200, quote)
+}
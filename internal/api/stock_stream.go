@@ -0,0 +1,185 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"product-catalog-service/infrastructure/log"
+	"product-catalog-service/internal/entity"
+	"product-catalog-service/internal/service"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"golang.org/x/net/websocket"
+)
+
+// maxStreamProducts is the largest number of products a single stream can follow.
+const maxStreamProducts = 200
+
+type StockStreamHandler interface {
+	StreamStock(c echo.Context) error
+	StreamStockWebSocket(c echo.Context) error
+}
+
+type stockStreamHandler struct {
+	ProductService     service.ProductService
+	StockStreamService service.StockStreamService
+	// Heartbeat is how often an idle stream is sent a keep-alive, so proxies do not close it.
+	Heartbeat time.Duration
+}
+
+func NewStockStreamHandler(productService service.ProductService, stockStreamService service.StockStreamService, heartbeat time.Duration) StockStreamHandler {
+	return &stockStreamHandler{
+		ProductService:     productService,
+		StockStreamService: stockStreamService,
+		Heartbeat:          heartbeat,
+	}
+}
+
+// StreamStock pushes the stock of the given products as Server-Sent Events: a "snapshot" event
+// with the current stock, then a "stock" event for every batch of changes.
+// products/stock/stream?ids={id},{id}
+func (sh *stockStreamHandler) StreamStock(c echo.Context) error {
+	productIDs, err := parseStreamProductIDs(c)
+	if err != nil {
+		return err
+	}
+
+	// Subscribe before reading the snapshot so that no change falls between the two.
+	sub := sh.StockStreamService.Subscribe(productIDs)
+	defer sub.Close()
+	snapshot, err := sh.ProductService.GetProductStocks(c.Request().Context(), productIDs)
+	if err != nil {
+		return err
+	}
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set(echo.HeaderCacheControl, "no-cache")
+	res.Header().Set(echo.HeaderConnection, "keep-alive")
+	res.Header().Set("X-Accel-Buffering", "no") // Disable response buffering in nginx
+	res.WriteHeader(http.StatusOK)
+	if err := writeEvent(res, "snapshot", snapshot); err != nil {
+		return nil
+	}
+
+	heartbeat := time.NewTicker(sh.Heartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-c.Request().Context().Done():
+			return nil
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(res, ": heartbeat\n\n"); err != nil {
+				return nil
+			}
+			res.Flush()
+		case updates := <-sub.Updates():
+			if err := writeEvent(res, "stock", updates); err != nil {
+				log.Logger.Debug().Err(err).Msg("Stock stream client went away")
+				return nil
+			}
+		}
+	}
+}
+
+// StreamStockWebSocket pushes the stock of the given products over a WebSocket as JSON
+// StockStreamMessage values: a "snapshot" message, then "stock" messages for batches of changes.
+// products/stock/ws?ids={id},{id}
+func (sh *stockStreamHandler) StreamStockWebSocket(c echo.Context) error {
+	productIDs, err := parseStreamProductIDs(c)
+	if err != nil {
+		return err
+	}
+
+	sub := sh.StockStreamService.Subscribe(productIDs)
+	defer sub.Close()
+	snapshot, err := sh.ProductService.GetProductStocks(c.Request().Context(), productIDs)
+	if err != nil {
+		return err
+	}
+
+	server := websocket.Server{
+		// Requests are authenticated by the JWT middleware, which also covers non-browser clients
+		// that send no Origin header.
+		Handshake: func(*websocket.Config, *http.Request) error { return nil },
+		Handler: func(ws *websocket.Conn) {
+			defer ws.Close()
+			if err := websocket.JSON.Send(ws, entity.StockStreamMessage{Type: "snapshot", Snapshot: snapshot}); err != nil {
+				return
+			}
+
+			// The stream is one-way; reading only detects that the client has gone away.
+			closed := make(chan struct{})
+			go func() {
+				defer close(closed)
+				var discard string
+				for websocket.Message.Receive(ws, &discard) == nil {
+				}
+			}()
+
+			heartbeat := time.NewTicker(sh.Heartbeat)
+			defer heartbeat.Stop()
+			for {
+				var err error
+				select {
+				case <-closed:
+					return
+				case <-c.Request().Context().Done():
+					return
+				case <-heartbeat.C:
+					err = websocket.JSON.Send(ws, entity.StockStreamMessage{Type: "heartbeat"})
+				case updates := <-sub.Updates():
+					err = websocket.JSON.Send(ws, entity.StockStreamMessage{Type: "stock", Updates: updates})
+				}
+				if err != nil {
+					log.Logger.Debug().Err(err).Msg("Stock stream client went away")
+					return
+				}
+			}
+		},
+	}
+	server.ServeHTTP(c.Response(), c.Request())
+	return nil
+}
+
+// parseStreamProductIDs reads the product IDs of a stream, given as ids=1,2,3 or as repeated ids parameters.
+func parseStreamProductIDs(c echo.Context) ([]int64, error) {
+	var productIDs []int64
+	seen := make(map[int64]bool)
+	for _, param := range c.QueryParams()["ids"] {
+		for _, idStr := range strings.Split(param, ",") {
+			id, err := strconv.ParseInt(strings.TrimSpace(idStr), 10, 64)
+			if err != nil || id <= 0 {
+				return nil, echo.NewHTTPError(http.StatusBadRequest, "Invalid product ID")
+			}
+			if !seen[id] {
+				seen[id] = true
+				productIDs = append(productIDs, id)
+			}
+		}
+	}
+
+	if len(productIDs) == 0 {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "At least one product ID is required")
+	}
+	if len(productIDs) > maxStreamProducts {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("At most %d products can be streamed", maxStreamProducts))
+	}
+	return productIDs, nil
+}
+
+// writeEvent writes a Server-Sent Event with a JSON payload and flushes it to the client.
+func writeEvent(res *echo.Response, event string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(res, "event: %s\ndata: %s\n\n", event, payload); err != nil {
+		return err
+	}
+	res.Flush()
+	return nil
+}
@@ -0,0 +1,127 @@
+package api
+
+import (
+	"net/http"
+	"product-catalog-service/internal/entity"
+	"product-catalog-service/internal/service"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type VariantHandler interface {
+	GetVariants(c echo.Context) error
+	GetVariant(c echo.Context) error
+	CreateVariant(c echo.Context) error
+	UpdateVariant(c echo.Context) error
+	DeleteVariant(c echo.Context) error
+}
+
+type variantHandler struct {
+	VariantService service.VariantService
+}
+
+func NewVariantHandler(variantService service.VariantService) VariantHandler {
+	return &variantHandler{
+		VariantService: variantService,
+	}
+}
+
+// GetVariants lists the SKUs of a product.
+// product/{id}/variants
+func (vh *variantHandler) GetVariants(c echo.Context) error {
+	productID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid product ID")
+	}
+
+	variants, err := vh.VariantService.GetVariants(c.Request().Context(), productID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(200, variants)
+}
+
+// GetVariant retrieves a single SKU by its ID.
+// variant/{id}
+func (vh *variantHandler) GetVariant(c echo.Context) error {
+	variantID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid variant ID")
+	}
+
+	variant, err := vh.VariantService.GetVariant(c.Request().Context(), variantID)
+	if err != nil {
+		return err
+	}
+	if variant == nil {
+		return service.ErrVariantNotFound
+	}
+
+	return c.JSON(200, variant)
+}
+
+// CreateVariant adds a SKU to a product.
+// product/{id}/variant
+func (vh *variantHandler) CreateVariant(c echo.Context) error {
+	productID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid product ID")
+	}
+
+	var variant entity.Variant
+	if err := c.Bind(&variant); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid variant data")
+	}
+	if err := c.Validate(&variant); err != nil {
+		return err
+	}
+	variant.ID = 0
+	variant.ProductID = productID
+
+	if err := vh.VariantService.CreateVariant(c.Request().Context(), &variant); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, variant)
+}
+
+// UpdateVariant updates the SKU, attributes, price override and stock of a variant.
+// variant/{id}
+func (vh *variantHandler) UpdateVariant(c echo.Context) error {
+	variantID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid variant ID")
+	}
+
+	var variant entity.Variant
+	if err := c.Bind(&variant); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid variant data")
+	}
+	if err := c.Validate(&variant); err != nil {
+		return err
+	}
+	variant.ID = variantID
+
+	if err := vh.VariantService.UpdateVariant(c.Request().Context(), &variant); err != nil {
+		return err
+	}
+
+	return c.JSON(200, variant)
+}
+
+// DeleteVariant removes a SKU from its product.
+// variant/{id}
+func (vh *variantHandler) DeleteVariant(c echo.Context) error {
+	variantID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid variant ID")
+	}
+
+	if err := vh.VariantService.DeleteVariant(c.Request().Context(), variantID); err != nil {
+		return err
+	}
+
+	return c.JSON(200, map[string]string{"message": "Variant deleted successfully"})
+}
@@ -0,0 +1,59 @@
+// Package apperror defines the domain errors shared by the repository, service and API layers.
+// Each error carries a Kind, which the API maps to an HTTP status, and a stable Code that clients
+// can match on without parsing messages.
+package apperror
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Kind classifies a domain error independently of the transport.
+type Kind uint8
+
+const (
+	// KindInternal is any error that is not a domain error.
+	KindInternal Kind = iota
+	// KindInvalid is a well-formed request that breaks a business rule.
+	KindInvalid
+	// KindNotFound is a reference to a resource that does not exist.
+	KindNotFound
+	// KindConflict is a request that conflicts with the current state of a resource.
+	KindConflict
+	// KindUnsupported is a payload in a format that is not accepted.
+	KindUnsupported
+	// KindTooLarge is a payload over the accepted size.
+	KindTooLarge
+)
+
+// Error is a domain error. Errors are compared by identity, so declare them once as package
+// variables and wrap them with fmt.Errorf("%w: ...") to add detail.
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+}
+
+// New creates a domain error of the given kind.
+func New(kind Kind, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// KindOf returns the kind of the first domain error in the chain of err, or KindInternal.
+func KindOf(err error) Kind {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr.Kind
+	}
+	return KindInternal
+}
+
+// Wrap returns an error that matches target with errors.Is and keeps cause in its message,
+// for domain errors caused by an underlying error such as a parse failure.
+func Wrap(target *Error, cause error) error {
+	return fmt.Errorf("%w: %v", target, cause)
+}
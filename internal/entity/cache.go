@@ -0,0 +1,14 @@
+package entity
+
+// CacheStats are the counters of the in-process cache tier of one instance, since it started.
+// Hits and misses only count keys held in memory.
+type CacheStats struct {
+	Enabled       bool    `json:"enabled"`
+	Hits          uint64  `json:"hits"`
+	Misses        uint64  `json:"misses"`
+	HitRatio      float64 `json:"hit_ratio"`
+	Evictions     uint64  `json:"evictions"`
+	Invalidations uint64  `json:"invalidations"`
+	Entries       int     `json:"entries"`
+	Bytes         int     `json:"bytes"`
+}
@@ -0,0 +1,36 @@
+package entity
+
+// Category represents a node in the hierarchical product category tree.
+// Root categories have a nil ParentID.
+type Category struct {
+	ID       int64  `json:"id"`
+	ParentID *int64 `json:"parent_id"`
+	Name     string `json:"name" validate:"required,max=255"`
+}
+
+// Tag represents a free-form label that can be attached to products.
+type Tag struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name" validate:"required,max=100"`
+}
+
+// ProductFilter narrows down a product listing. Zero values disable a filter.
+type ProductFilter struct {
+	// CategoryID limits the listing to products in the category or any of its descendants.
+	CategoryID int64
+	// Tag limits the listing to products carrying the tag.
+	Tag string
+	// ActiveOnly limits the listing to products that are currently active.
+	ActiveOnly bool
+}
+
+// CategoryAssignment is the request body for assigning a product to a category.
+// A nil CategoryID removes the product from its category.
+type CategoryAssignment struct {
+	CategoryID *int64 `json:"category_id"`
+}
+
+// TagAssignment is the request body for replacing the tags of a product.
+type TagAssignment struct {
+	Tags []string `json:"tags"`
+}
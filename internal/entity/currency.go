@@ -0,0 +1,22 @@
+package entity
+
+import (
+	"product-catalog-service/pkg/money"
+	"time"
+)
+
+// ExchangeRate converts prices from a base currency into a quote currency.
+type ExchangeRate struct {
+	BaseCurrency  string     `json:"base_currency"`
+	QuoteCurrency string     `json:"quote_currency"`
+	Rate          money.Rate `json:"rate"` // Major units of QuoteCurrency per major unit of BaseCurrency
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// CurrencyPrice is an explicit price of a product in a currency other than its own. It takes
+// precedence over converting the product price with an exchange rate.
+type CurrencyPrice struct {
+	ProductID int64       `json:"product_id"`
+	Price     money.Money `json:"price" gorm:"embedded;embeddedPrefix:price_"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
@@ -0,0 +1,42 @@
+package entity
+
+import "time"
+
+// ExportFormat is the file format of a catalogue export.
+type ExportFormat string
+
+const (
+	ExportFormatCSV     ExportFormat = "csv"
+	ExportFormatJSONL   ExportFormat = "jsonl"
+	ExportFormatParquet ExportFormat = "parquet"
+)
+
+// ExportOptions controls a catalogue export.
+type ExportOptions struct {
+	Format ExportFormat
+	// BatchSize is the number of rows read from the database per keyset page.
+	BatchSize int
+}
+
+// ExportRow is a single product row of a catalogue export.
+type ExportRow struct {
+	ID          int64   `json:"id" parquet:"id"`
+	ExternalSKU *string `json:"external_sku" parquet:"external_sku,optional"`
+	Name        string  `json:"name" parquet:"name"`
+	Description string  `json:"description" parquet:"description"`
+	Price       string  `json:"price" parquet:"price"` // Decimal amount in major units, e.g. "12.34"
+	Currency    string  `json:"currency" parquet:"currency"`
+	Stock       int64   `json:"stock" parquet:"stock"`
+	CategoryID  *int64  `json:"category_id" parquet:"category_id,optional"`
+	Status      string  `json:"status" parquet:"status"`
+}
+
+// ExportSummary describes a finished catalogue export.
+type ExportSummary struct {
+	Format ExportFormat `json:"format"`
+	// SnapshotAt is the point in time the exported rows are consistent with.
+	SnapshotAt time.Time `json:"snapshot_at"`
+	Rows       int       `json:"rows"`
+	// Checksum is the hex-encoded SHA-256 of the exported bytes.
+	Checksum string `json:"checksum"`
+}
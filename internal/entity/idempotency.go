@@ -0,0 +1,13 @@
+package entity
+
+// IdempotencyRecord is what is kept for an idempotency key: the request it was first used with
+// and, once that request has completed, its response, which is replayed to retries.
+type IdempotencyRecord struct {
+	// Fingerprint identifies the request the key was first used with, so reusing the key for a
+	// different request can be rejected.
+	Fingerprint string `json:"fingerprint"`
+	Completed   bool   `json:"completed"`
+	StatusCode  int    `json:"status_code,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
@@ -0,0 +1,58 @@
+package entity
+
+import "encoding/json"
+
+// ImportFormat is the file format of a bulk product import.
+type ImportFormat string
+
+const (
+	ImportFormatCSV   ImportFormat = "csv"
+	ImportFormatJSONL ImportFormat = "jsonl"
+)
+
+// ImportOptions controls a bulk product import.
+type ImportOptions struct {
+	Format ImportFormat
+	// DryRun validates every row without writing anything.
+	DryRun bool
+	// BatchSize is the number of rows upserted per transaction.
+	BatchSize int
+}
+
+// ImportRow is a single product row of an import file. CSV files use the JSON names as headers.
+type ImportRow struct {
+	ExternalSKU string      `json:"external_sku"`
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	Price       json.Number `json:"price"` // Decimal amount in major units, e.g. "12.34"; parsed exactly
+	Currency    string      `json:"currency"`
+	Stock       int         `json:"stock"`
+	CategoryID  *int64      `json:"category_id"`
+}
+
+// ImportRowError describes why a row of an import file was rejected.
+// Rows are numbered from 1, not counting the CSV header.
+type ImportRowError struct {
+	Row         int      `json:"row"`
+	ExternalSKU string   `json:"external_sku,omitempty"`
+	Errors      []string `json:"errors"`
+}
+
+// ImportProgress is reported periodically while an import runs.
+type ImportProgress struct {
+	RowsRead     int `json:"rows_read"`
+	RowsInvalid  int `json:"rows_invalid"`
+	RowsUpserted int `json:"rows_upserted"`
+}
+
+// ImportReport summarises a finished bulk product import.
+type ImportReport struct {
+	DryRun       bool             `json:"dry_run"`
+	RowsRead     int              `json:"rows_read"`
+	RowsValid    int              `json:"rows_valid"`
+	RowsInvalid  int              `json:"rows_invalid"`
+	RowsUpserted int              `json:"rows_upserted"`
+	Errors       []ImportRowError `json:"errors"`
+	// ErrorsTruncated is set when more rows were rejected than are listed in Errors.
+	ErrorsTruncated bool `json:"errors_truncated,omitempty"`
+}
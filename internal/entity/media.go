@@ -0,0 +1,19 @@
+package entity
+
+// ProductMedia represents an image attached to a product. Media are ordered by Position,
+// starting at 1 for the primary image.
+type ProductMedia struct {
+	ID          int64  `json:"id"`
+	ProductID   int64  `json:"product_id"`
+	URL         string `json:"url"`
+	StorageKey  string `json:"-"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+	Position    int    `json:"position"`
+}
+
+// MediaOrder is the request body for reordering the media of a product.
+// It must list every media ID of the product in the desired order.
+type MediaOrder struct {
+	MediaIDs []int64 `json:"media_ids"`
+}
@@ -1,21 +1,24 @@
 package entity
 
+import "product-catalog-service/pkg/money"
+
 type Order struct {
 	ID              int64          `json:"id"`
 	UserID          int64          `json:"user_id"`
-	ProductRequests []OrderRequest `json:"product_requests"` // List of products in the order
+	ProductRequests []OrderRequest `json:"product_requests" validate:"required,min=1,dive"` // List of products in the order
 	Quantity        int            `json:"quantity"`
-	TotalPrice      float64        `json:"total_price"`
+	TotalPrice      money.Money    `json:"total_price"`
 	Status          string         `json:"status"` // e.g., "pending", "completed", "cancelled"
 	HashValue       string         `json:"hash_value"`
 }
 
 type OrderRequest struct {
-	ProductID  int64   `json:"product_id"`
-	Quantity   int64   `json:"quantity"`
-	MarkUp     float64 `json:"markup"`      // Percentage markup on the product price
-	Discount   float64 `json:"discount"`    // Percentage discount on the product price
-	FinalPrice float64 `json:"final_price"` // Final price after applying markup and discount
-	OrderID    int64   `json:"order_id"`
-	HashValue  string  `json:"hash_value"`
+	ProductID  int64         `json:"product_id" validate:"gt=0"`
+	VariantID  int64         `json:"variant_id,omitempty" validate:"gte=0"` // SKU of the product, if it has variants
+	Quantity   int64         `json:"quantity" validate:"gt=0"`
+	MarkUp     money.Percent `json:"markup" validate:"gte=0"`      // Percentage markup on the product price
+	Discount   money.Percent `json:"discount" validate:"percent"`  // Percentage discount on the product price
+	FinalPrice money.Money   `json:"final_price" validate:"money"` // Final price after applying markup and discount
+	OrderID    int64         `json:"order_id"`
+	HashValue  string        `json:"hash_value"`
 }
@@ -0,0 +1,24 @@
+package entity
+
+import (
+	"product-catalog-service/pkg/money"
+	"time"
+)
+
+// PriceChange is an entry of the price history of a product. The entries of a product cover
+// consecutive, non-overlapping [EffectiveFrom, EffectiveTo) ranges.
+type PriceChange struct {
+	ID            int64       `json:"id"`
+	ProductID     int64       `json:"product_id"`
+	Price         money.Money `json:"price" gorm:"embedded;embeddedPrefix:price_"`
+	EffectiveFrom time.Time   `json:"effective_from"`
+	EffectiveTo   *time.Time  `json:"effective_to"`         // Nil while the price is open-ended
+	AppliedAt     *time.Time  `json:"applied_at,omitempty"` // When the product took this price; nil while scheduled
+	CreatedAt     time.Time   `json:"created_at"`
+}
+
+// PriceChangeRequest is the request body for changing the price of a product now or at a future time.
+type PriceChangeRequest struct {
+	Price         money.Money `json:"price"`
+	EffectiveFrom *time.Time  `json:"effective_from"` // Defaults to now
+}
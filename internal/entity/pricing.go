@@ -0,0 +1,37 @@
+package entity
+
+import "product-catalog-service/pkg/money"
+
+// PriceQuoteLine asks for the price of one order line.
+type PriceQuoteLine struct {
+	ProductID int64         `json:"product_id" validate:"gt=0"`
+	VariantID int64         `json:"variant_id,omitempty" validate:"gte=0"`
+	Quantity  int64         `json:"quantity" validate:"gt=0"`
+	MarkUp    money.Percent `json:"markup" validate:"gte=0"`
+	Discount  money.Percent `json:"discount" validate:"percent"`
+}
+
+// PriceQuoteRequest is the request body of a price quote. Currency is optional and defaults to
+// the product currency.
+type PriceQuoteRequest struct {
+	Lines    []PriceQuoteLine `json:"lines" validate:"required,min=1,dive"`
+	Currency string           `json:"currency,omitempty" validate:"omitempty,len=3"`
+}
+
+// PricedLine is the authoritative price of one order line.
+// UnitPrice is BasePrice plus MarkUpAmount minus DiscountAmount; the discount applies to the
+// marked-up price. Each step is rounded to a whole minor unit with money.DefaultRounding.
+type PricedLine struct {
+	PriceQuoteLine
+	BasePrice      money.Money `json:"base_price"`
+	MarkUpAmount   money.Money `json:"markup_amount"`
+	DiscountAmount money.Money `json:"discount_amount"`
+	UnitPrice      money.Money `json:"unit_price"`
+	LineTotal      money.Money `json:"line_total"`
+}
+
+// PriceQuote is the authoritative price of a set of order lines.
+type PriceQuote struct {
+	Lines []PricedLine `json:"lines"`
+	Total money.Money  `json:"total"`
+}
@@ -1,15 +1,120 @@
 package entity
 
+import (
+	"product-catalog-service/pkg/money"
+	"time"
+
+	"gorm.io/gorm"
+)
+
 type Product struct {
-	ID          int64   `json:"id"`
-	Name        string  `json:"name"`
-	Description string  `json:"description"`
-	Price       float64 `json:"price"`
-	Stock       int     `json:"stock"`
+	ID          int64          `json:"id"`
+	ExternalSKU *string        `json:"external_sku,omitempty" validate:"omitempty,max=64"` // Merchandiser SKU used to upsert imports
+	Name        string         `json:"name" validate:"required,max=255"`
+	Description string         `json:"description"`
+	Price       money.Money    `json:"price" gorm:"embedded;embeddedPrefix:price_" validate:"money"`
+	Stock       int            `json:"stock" validate:"gte=0"`
+	CategoryID  *int64         `json:"category_id" validate:"omitempty,gt=0"`
+	Status      ProductStatus  `json:"status" validate:"omitempty,oneof=draft scheduled active archived"`
+	PublishAt   *time.Time     `json:"publish_at,omitempty"`   // When a scheduled product becomes active
+	UnpublishAt *time.Time     `json:"unpublish_at,omitempty"` // When an active product is archived
+	Tags        []string       `json:"tags,omitempty" gorm:"-"`
+	Images      []string       `json:"images,omitempty" gorm:"-"` // Image URLs ordered by position
+	DeletedAt   gorm.DeletedAt `json:"deleted_at,omitempty"`      // Set when the product is soft-deleted
+}
+
+// IsPurchasable reports whether the product can be listed publicly and reserved at the given time.
+func (p *Product) IsPurchasable(now time.Time) bool {
+	return p.Status == ProductStatusActive && (p.UnpublishAt == nil || now.Before(*p.UnpublishAt))
+}
+
+// ProductStatus is the lifecycle state of a product.
+type ProductStatus string
+
+const (
+	// ProductStatusDraft is being prepared and is invisible to shoppers.
+	ProductStatusDraft ProductStatus = "draft"
+	// ProductStatusScheduled becomes active automatically at its PublishAt time.
+	ProductStatusScheduled ProductStatus = "scheduled"
+	// ProductStatusActive is listed publicly and can be reserved.
+	ProductStatusActive ProductStatus = "active"
+	// ProductStatusArchived is no longer sold but kept for reference.
+	ProductStatusArchived ProductStatus = "archived"
+)
+
+// productStatusTransitions lists the statuses each status may move to.
+var productStatusTransitions = map[ProductStatus][]ProductStatus{
+	ProductStatusDraft:     {ProductStatusScheduled, ProductStatusActive, ProductStatusArchived},
+	ProductStatusScheduled: {ProductStatusDraft, ProductStatusActive, ProductStatusArchived},
+	ProductStatusActive:    {ProductStatusArchived},
+	ProductStatusArchived:  {ProductStatusDraft},
+}
+
+// IsValid reports whether s is a known status.
+func (s ProductStatus) IsValid() bool {
+	_, ok := productStatusTransitions[s]
+	return ok
+}
+
+// CanTransitionTo reports whether a product may move from s to next.
+func (s ProductStatus) CanTransitionTo(next ProductStatus) bool {
+	for _, allowed := range productStatusTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// ProductStatusChange is the request body for moving a product to another lifecycle status.
+type ProductStatusChange struct {
+	Status      ProductStatus `json:"status"`
+	PublishAt   *time.Time    `json:"publish_at"`   // Required when Status is scheduled
+	UnpublishAt *time.Time    `json:"unpublish_at"` // Optional end of the sale
 }
 
 // ProductStock represents the stock information for a product.
 type StockReservation struct {
-	ProductID int64 `json:"product_id"`
-	Quantity  int   `json:"quantity"`
+	ProductID int64 `json:"product_id" validate:"gt=0"`
+	VariantID int64 `json:"variant_id,omitempty" validate:"gte=0"` // Reserve a specific SKU of the product
+	Quantity  int   `json:"quantity" validate:"gt=0"`
+}
+
+// StockBatchRequest asks for the stock of several products at once.
+type StockBatchRequest struct {
+	ProductIDs []int64 `json:"product_ids" validate:"required,min=1,max=200,dive,gt=0"`
+}
+
+// StockBatch is the stock of several products. JSON object keys are the product IDs; IDs of
+// products that do not exist are listed in UnknownIDs instead.
+type StockBatch struct {
+	Stock      map[int64]int `json:"stock"`
+	UnknownIDs []int64       `json:"unknown_ids"`
+}
+
+// StockAdjustment is an admin correction of the stock of a product, e.g. after a stock take.
+type StockAdjustment struct {
+	Delta int `json:"delta" validate:"ne=0"`
+}
+
+// StockUpdate is the stock of a product after a change, pushed to stock stream subscribers.
+type StockUpdate struct {
+	ProductID int64     `json:"product_id"`
+	Stock     int       `json:"stock"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// StockStreamMessage is a message of the stock WebSocket. Type is "snapshot" for the first message,
+// which carries the current stock of every subscribed product, then "stock" for updates.
+type StockStreamMessage struct {
+	Type     string        `json:"type"`
+	Snapshot *StockBatch   `json:"snapshot,omitempty"`
+	Updates  []StockUpdate `json:"updates,omitempty"`
+}
+
+// ProductSearchResult represents a product matched by a full-text search.
+type ProductSearchResult struct {
+	Product    Product           `json:"product"`
+	Score      float64           `json:"score"`
+	Highlights map[string]string `json:"highlights,omitempty"`
 }
@@ -0,0 +1,63 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+)
+
+// ReservationStatus is the state of a cart reservation.
+type ReservationStatus string
+
+const (
+	ReservationReserved  ReservationStatus = "reserved"  // The stock of every line is held
+	ReservationReleased  ReservationStatus = "released"  // The stock has been returned
+	ReservationConfirmed ReservationStatus = "confirmed" // The order was placed; the stock is sold
+)
+
+// CartReservationRequest reserves the stock of every line of a cart at once: either all lines are
+// reserved or none is.
+type CartReservationRequest struct {
+	Lines []StockReservation `json:"lines" validate:"required,min=1,max=100,dive"`
+}
+
+// CartReservation is the stock held for a cart under a single reservation ID.
+type CartReservation struct {
+	ID          string            `json:"reservation_id"`
+	Status      ReservationStatus `json:"status"`
+	Lines       []ReservationLine `json:"lines" gorm:"-"`
+	CreatedAt   time.Time         `json:"created_at"`
+	ReleasedAt  *time.Time        `json:"released_at,omitempty"`
+	ConfirmedAt *time.Time        `json:"confirmed_at,omitempty"`
+}
+
+// ReservationLine is the stock of one product, or one variant of it, held by a cart reservation.
+type ReservationLine struct {
+	ReservationID string `json:"-"`
+	ProductID     int64  `json:"product_id"`
+	VariantID     int64  `json:"variant_id,omitempty"` // Zero for products without variants
+	Quantity      int    `json:"quantity"`
+}
+
+// Reasons a cart line cannot be reserved.
+const (
+	LineInsufficientStock  = "insufficient_stock"
+	LineProductNotFound    = "product_not_found"
+	LineProductUnavailable = "product_unavailable"
+	LineVariantRequired    = "variant_required"
+	LineVariantNotFound    = "variant_not_found"
+)
+
+// LineAvailability reports whether a line of a rejected cart reservation could be reserved.
+type LineAvailability struct {
+	ProductID int64  `json:"product_id"`
+	VariantID int64  `json:"variant_id,omitempty"`
+	Requested int    `json:"requested"`
+	Available int    `json:"available"`
+	Reason    string `json:"reason,omitempty"` // Empty when the line on its own could be reserved
+}
+
+// OrderReservationID returns the ID of the cart reservation holding the stock of an order placed
+// through Kafka.
+func OrderReservationID(orderID int64) string {
+	return fmt.Sprintf("order-%d", orderID)
+}
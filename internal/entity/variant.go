@@ -0,0 +1,52 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"product-catalog-service/pkg/money"
+)
+
+// Variant represents a purchasable SKU of a product, such as a size and colour combination.
+// When a product has variants its stock is the sum of the variant stock.
+type Variant struct {
+	ID         int64             `json:"id"`
+	ProductID  int64             `json:"product_id"`
+	SKU        string            `json:"sku" validate:"required,max=64"`
+	Attributes VariantAttributes `json:"attributes"`
+	Price      *money.Money      `json:"price" gorm:"embedded;embeddedPrefix:price_" validate:"omitempty,money"` // Overrides the product price when set
+	Stock      int               `json:"stock" validate:"gte=0"`
+}
+
+// VariantAttributes is the attribute set of a variant, e.g. {"size": "M", "colour": "red"}.
+// It is stored as a JSON column.
+type VariantAttributes map[string]string
+
+// Value implements driver.Valuer.
+func (a VariantAttributes) Value() (driver.Value, error) {
+	if a == nil {
+		return "{}", nil
+	}
+	b, err := json.Marshal(a)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// Scan implements sql.Scanner.
+func (a *VariantAttributes) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*a = VariantAttributes{}
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return errors.New("unsupported type for variant attributes")
+	}
+	return json.Unmarshal(data, a)
+}
@@ -0,0 +1,49 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"product-catalog-service/infrastructure/log"
+	"product-catalog-service/internal/apperror"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// statusError converts an error of the service layer to a gRPC status, mapping domain errors by
+// kind like the HTTP API maps them to statuses. Anything else is logged and reported as an opaque
+// INTERNAL so that internal details do not leak to clients.
+func statusError(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	var appErr *apperror.Error
+	switch {
+	case errors.As(err, &appErr):
+		return status.Error(codeOf(appErr.Kind), err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	default:
+		log.Logger.Error().Err(err).Msg("gRPC call failed")
+		return status.Error(codes.Internal, "internal error")
+	}
+}
+
+// codeOf maps the kind of a domain error to a gRPC status code.
+func codeOf(kind apperror.Kind) codes.Code {
+	switch kind {
+	case apperror.KindInvalid, apperror.KindUnsupported:
+		return codes.InvalidArgument
+	case apperror.KindNotFound:
+		return codes.NotFound
+	case apperror.KindConflict:
+		return codes.FailedPrecondition
+	case apperror.KindTooLarge:
+		return codes.ResourceExhausted
+	default:
+		return codes.Internal
+	}
+}
@@ -0,0 +1,197 @@
+// Package grpcapi serves the inventory API of pkg/inventorypb over gRPC for the order service,
+// which calls it in the hot path. It is a thin transport over service.ProductService, so the
+// business logic is shared with the Echo handlers in internal/api.
+package grpcapi
+
+import (
+	"context"
+	"product-catalog-service/infrastructure/log"
+	"product-catalog-service/internal/entity"
+	"product-catalog-service/internal/service"
+	"product-catalog-service/internal/validation"
+	"product-catalog-service/pkg/inventorypb"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type inventoryServer struct {
+	inventorypb.UnimplementedInventoryServiceServer
+
+	productService     service.ProductService
+	stockStreamService service.StockStreamService
+	validator          *validation.Validator
+}
+
+// NewServer creates a gRPC server for the inventory API. Every call must carry a JWT signed with
+// jwtSecret; unary calls without a deadline are bounded by defaultTimeout.
+func NewServer(productService service.ProductService, stockStreamService service.StockStreamService, validator *validation.Validator, jwtSecret []byte, defaultTimeout time.Duration) *grpc.Server {
+	auth := &authenticator{secret: jwtSecret}
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(auth.unaryAuth, unaryDeadline(defaultTimeout)),
+		grpc.ChainStreamInterceptor(auth.streamAuth),
+	)
+	inventorypb.RegisterInventoryServiceServer(server, &inventoryServer{
+		productService:     productService,
+		stockStreamService: stockStreamService,
+		validator:          validator,
+	})
+	return server
+}
+
+func (s *inventoryServer) GetStock(ctx context.Context, req *inventorypb.GetStockRequest) (*inventorypb.GetStockResponse, error) {
+	if req.GetProductId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "product_id must be positive")
+	}
+	stock, err := s.productService.GetProductStock(ctx, req.GetProductId())
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &inventorypb.GetStockResponse{ProductId: req.GetProductId(), Stock: int32(stock)}, nil
+}
+
+func (s *inventoryServer) BatchGetStock(ctx context.Context, req *inventorypb.BatchGetStockRequest) (*inventorypb.BatchGetStockResponse, error) {
+	request := entity.StockBatchRequest{ProductIDs: req.GetProductIds()}
+	if err := s.validator.Validate(&request); err != nil {
+		return nil, statusError(err)
+	}
+	batch, err := s.productService.GetProductStocks(ctx, request.ProductIDs)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	res := &inventorypb.BatchGetStockResponse{Stock: make(map[int64]int32, len(batch.Stock)), UnknownIds: batch.UnknownIDs}
+	for id, stock := range batch.Stock {
+		res.Stock[id] = int32(stock)
+	}
+	return res, nil
+}
+
+func (s *inventoryServer) Reserve(ctx context.Context, req *inventorypb.ReserveRequest) (*inventorypb.ReserveResponse, error) {
+	request := entity.CartReservationRequest{Lines: make([]entity.StockReservation, 0, len(req.GetLines()))}
+	for _, line := range req.GetLines() {
+		request.Lines = append(request.Lines, entity.StockReservation{
+			ProductID: line.GetProductId(),
+			VariantID: line.GetVariantId(),
+			Quantity:  int(line.GetQuantity()),
+		})
+	}
+	if err := s.validator.Validate(&request); err != nil {
+		return nil, statusError(err)
+	}
+
+	reservation, availability, err := s.productService.ReserveCart(ctx, request.Lines)
+	if err != nil {
+		if availability != nil {
+			return nil, rejectionStatus(err, availability)
+		}
+		return nil, statusError(err)
+	}
+	return &inventorypb.ReserveResponse{Reservation: toReservation(reservation)}, nil
+}
+
+func (s *inventoryServer) Release(ctx context.Context, req *inventorypb.ReleaseRequest) (*inventorypb.ReleaseResponse, error) {
+	reservation, err := s.productService.ReleaseReservation(ctx, req.GetReservationId())
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &inventorypb.ReleaseResponse{Reservation: toReservation(reservation)}, nil
+}
+
+func (s *inventoryServer) Confirm(ctx context.Context, req *inventorypb.ConfirmRequest) (*inventorypb.ConfirmResponse, error) {
+	reservation, err := s.productService.ConfirmReservation(ctx, req.GetReservationId())
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &inventorypb.ConfirmResponse{Reservation: toReservation(reservation)}, nil
+}
+
+func (s *inventoryServer) WatchStock(req *inventorypb.WatchStockRequest, stream inventorypb.InventoryService_WatchStockServer) error {
+	request := entity.StockBatchRequest{ProductIDs: req.GetProductIds()}
+	if err := s.validator.Validate(&request); err != nil {
+		return statusError(err)
+	}
+	ctx := stream.Context()
+
+	// Subscribe before reading the snapshot so that no change falls between the two.
+	sub := s.stockStreamService.Subscribe(request.ProductIDs)
+	defer sub.Close()
+	batch, err := s.productService.GetProductStocks(ctx, request.ProductIDs)
+	if err != nil {
+		return statusError(err)
+	}
+
+	snapshot := &inventorypb.WatchStockResponse{Snapshot: true}
+	now := timestamppb.Now()
+	for _, id := range request.ProductIDs {
+		if stock, ok := batch.Stock[id]; ok {
+			snapshot.Updates = append(snapshot.Updates, &inventorypb.StockUpdate{ProductId: id, Stock: int32(stock), UpdatedAt: now})
+		}
+	}
+	if err := stream.Send(snapshot); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case updates := <-sub.Updates():
+			res := &inventorypb.WatchStockResponse{Updates: make([]*inventorypb.StockUpdate, 0, len(updates))}
+			for _, update := range updates {
+				res.Updates = append(res.Updates, &inventorypb.StockUpdate{
+					ProductId: update.ProductID,
+					Stock:     int32(update.Stock),
+					UpdatedAt: timestamppb.New(update.UpdatedAt),
+				})
+			}
+			if err := stream.Send(res); err != nil {
+				log.Logger.Debug().Err(err).Msg("Stock watcher went away")
+				return err
+			}
+		}
+	}
+}
+
+// rejectionStatus reports a rejected reservation with the availability of every line as a
+// ReserveRejection detail.
+func rejectionStatus(err error, availability []entity.LineAvailability) error {
+	rejection := &inventorypb.ReserveRejection{Lines: make([]*inventorypb.LineAvailability, 0, len(availability))}
+	for _, line := range availability {
+		rejection.Lines = append(rejection.Lines, &inventorypb.LineAvailability{
+			ProductId: line.ProductID,
+			VariantId: line.VariantID,
+			Requested: int32(line.Requested),
+			Available: int32(line.Available),
+			Reason:    line.Reason,
+		})
+	}
+
+	st, _ := status.FromError(statusError(err))
+	detailed, detailsErr := st.WithDetails(rejection)
+	if detailsErr != nil {
+		log.Logger.Error().Err(detailsErr).Msg("Failed to attach reservation rejection")
+		return st.Err()
+	}
+	return detailed.Err()
+}
+
+func toReservation(reservation *entity.CartReservation) *inventorypb.Reservation {
+	res := &inventorypb.Reservation{
+		ReservationId: reservation.ID,
+		Status:        string(reservation.Status),
+		Lines:         make([]*inventorypb.ReservationLine, 0, len(reservation.Lines)),
+		CreatedAt:     timestamppb.New(reservation.CreatedAt),
+	}
+	for _, line := range reservation.Lines {
+		res.Lines = append(res.Lines, &inventorypb.ReservationLine{
+			ProductId: line.ProductID,
+			VariantId: line.VariantID,
+			Quantity:  int32(line.Quantity),
+		})
+	}
+	return res
+}
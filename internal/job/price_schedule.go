@@ -0,0 +1,48 @@
+package job
+
+import (
+	"context"
+	"product-catalog-service/infrastructure/log"
+	"product-catalog-service/internal/service"
+	"time"
+)
+
+// PriceScheduleJob periodically applies scheduled product prices once they take effect.
+type PriceScheduleJob struct {
+	priceSvc service.PriceService
+	interval time.Duration
+}
+
+func NewPriceScheduleJob(priceSvc service.PriceService, interval time.Duration) *PriceScheduleJob {
+	return &PriceScheduleJob{
+		priceSvc: priceSvc,
+		interval: interval,
+	}
+}
+
+// Start runs the job immediately and then on every interval until ctx is cancelled.
+func (j *PriceScheduleJob) Start(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		j.run(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (j *PriceScheduleJob) run(ctx context.Context) {
+	applied, err := j.priceSvc.ApplyScheduledPrices(ctx, time.Now())
+	if err != nil {
+		log.Logger.Error().Err(err).Msg("Failed to apply scheduled prices")
+		return
+	}
+	if applied > 0 {
+		log.Logger.Info().Int("products", applied).Msg("Applied scheduled prices")
+	}
+}
@@ -0,0 +1,53 @@
+package job
+
+import (
+	"context"
+	"product-catalog-service/infrastructure/log"
+	"product-catalog-service/internal/service"
+	"time"
+)
+
+// ProductArchiveJob periodically moves products that have been soft-deleted for longer than the
+// retention period into the archive table.
+type ProductArchiveJob struct {
+	productSvc service.ProductService
+	retention  time.Duration
+	interval   time.Duration
+	batchSize  int
+}
+
+func NewProductArchiveJob(productSvc service.ProductService, retention, interval time.Duration, batchSize int) *ProductArchiveJob {
+	return &ProductArchiveJob{
+		productSvc: productSvc,
+		retention:  retention,
+		interval:   interval,
+		batchSize:  batchSize,
+	}
+}
+
+// Start runs the job immediately and then on every interval until ctx is cancelled.
+func (j *ProductArchiveJob) Start(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		j.run(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (j *ProductArchiveJob) run(ctx context.Context) {
+	archived, err := j.productSvc.ArchiveDeletedProducts(ctx, j.retention, j.batchSize)
+	if err != nil {
+		log.Logger.Error().Err(err).Int64("archived", archived).Msg("Failed to archive deleted products")
+		return
+	}
+	if archived > 0 {
+		log.Logger.Info().Int64("archived", archived).Msg("Archived deleted products")
+	}
+}
@@ -0,0 +1,49 @@
+package job
+
+import (
+	"context"
+	"product-catalog-service/infrastructure/log"
+	"product-catalog-service/internal/service"
+	"time"
+)
+
+// ProductScheduleJob periodically publishes scheduled products and archives active products once
+// their publish and unpublish times pass.
+type ProductScheduleJob struct {
+	productSvc service.ProductService
+	interval   time.Duration
+}
+
+func NewProductScheduleJob(productSvc service.ProductService, interval time.Duration) *ProductScheduleJob {
+	return &ProductScheduleJob{
+		productSvc: productSvc,
+		interval:   interval,
+	}
+}
+
+// Start runs the job immediately and then on every interval until ctx is cancelled.
+func (j *ProductScheduleJob) Start(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		j.run(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (j *ProductScheduleJob) run(ctx context.Context) {
+	published, unpublished, err := j.productSvc.ApplyProductSchedules(ctx, time.Now().UTC())
+	if err != nil {
+		log.Logger.Error().Err(err).Msg("Failed to apply product schedules")
+		return
+	}
+	if published > 0 || unpublished > 0 {
+		log.Logger.Info().Int("published", published).Int("unpublished", unpublished).Msg("Applied product schedules")
+	}
+}
@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"product-catalog-service/infrastructure/log"
+	"product-catalog-service/internal/entity"
+
+	"gorm.io/gorm"
+)
+
+// CategoryRepository defines the interface for category-related database operations.
+type CategoryRepository interface {
+	// GetCategoryByID retrieves a category by its ID.
+	// Parameters:
+	//   - id: The ID of the category to retrieve.
+	// Returns:
+	//   - A pointer to the Category entity if found, or nil if not found.
+	//   - An error if any issues occur during retrieval.
+	GetCategoryByID(ctx context.Context, id int64) (*entity.Category, error)
+
+	// GetCategories retrieves all categories.
+	// Returns:
+	//   - A slice of Category entities.
+	//   - An error if any issues occur during retrieval.
+	GetCategories(ctx context.Context) ([]entity.Category, error)
+
+	// CreateCategory creates a new category.
+	// Parameters:
+	//   - category: A pointer to the Category entity to create. Its ID is set on success.
+	// Returns:
+	//   - An error if any issues occur during creation.
+	CreateCategory(ctx context.Context, category *entity.Category) error
+
+	// UpdateCategory updates the name and parent of an existing category.
+	// Parameters:
+	//   - category: A pointer to the Category entity with updated data.
+	// Returns:
+	//   - An error if any issues occur during the update.
+	UpdateCategory(ctx context.Context, category *entity.Category) error
+
+	// DeleteCategory deletes a category by its ID. Products in the category are left uncategorised.
+	// Parameters:
+	//   - id: The ID of the category to delete.
+	// Returns:
+	//   - An error if any issues occur during deletion.
+	DeleteCategory(ctx context.Context, id int64) error
+
+	// CountChildren returns the number of direct children of a category.
+	// Parameters:
+	//   - id: The ID of the parent category.
+	// Returns:
+	//   - The number of child categories.
+	//   - An error if any issues occur during the count.
+	CountChildren(ctx context.Context, id int64) (int64, error)
+}
+
+// categoryRepository is a concrete implementation of the CategoryRepository interface.
+type categoryRepository struct {
+	db *gorm.DB
+}
+
+// NewCategoryRepository creates a new instance of categoryRepository.
+// Returns:
+//   - A CategoryRepository instance.
+func NewCategoryRepository(db *gorm.DB) CategoryRepository {
+	return &categoryRepository{
+		db: db,
+	}
+}
+
+func (r *categoryRepository) GetCategoryByID(ctx context.Context, id int64) (*entity.Category, error) {
+	var category entity.Category
+	err := r.db.Table("categories").WithContext(ctx).Where("id = ?", id).First(&category).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		log.Logger.Error().Err(err).Int64("categoryID", id).Msg("Failed to get category from database")
+		return nil, fmt.Errorf("failed to get category from database: %w", err)
+	}
+	return &category, nil
+}
+
+func (r *categoryRepository) GetCategories(ctx context.Context) ([]entity.Category, error) {
+	var categories []entity.Category
+	err := r.db.Table("categories").WithContext(ctx).Order("id ASC").Find(&categories).Error
+	if err != nil {
+		log.Logger.Error().Err(err).Msg("Failed to get categories from database")
+		return nil, fmt.Errorf("failed to get categories from database: %w", err)
+	}
+	return categories, nil
+}
+
+func (r *categoryRepository) CreateCategory(ctx context.Context, category *entity.Category) error {
+	err := r.db.Table("categories").WithContext(ctx).Create(category).Error
+	if err != nil {
+		log.Logger.Error().Err(err).Str("name", category.Name).Msg("Failed to create category in database")
+		return fmt.Errorf("failed to create category in database: %w", translateError(err))
+	}
+	return nil
+}
+
+func (r *categoryRepository) UpdateCategory(ctx context.Context, category *entity.Category) error {
+	err := r.db.Table("categories").WithContext(ctx).Save(category).Error
+	if err != nil {
+		log.Logger.Error().Err(err).Int64("categoryID", category.ID).Msg("Failed to update category in database")
+		return fmt.Errorf("failed to update category in database: %w", translateError(err))
+	}
+	return nil
+}
+
+func (r *categoryRepository) DeleteCategory(ctx context.Context, id int64) error {
+	err := r.db.Table("categories").WithContext(ctx).Delete(&entity.Category{}, id).Error
+	if err != nil {
+		log.Logger.Error().Err(err).Int64("categoryID", id).Msg("Failed to delete category from database")
+		return fmt.Errorf("failed to delete category from database: %w", err)
+	}
+	return nil
+}
+
+func (r *categoryRepository) CountChildren(ctx context.Context, id int64) (int64, error) {
+	var count int64
+	err := r.db.Table("categories").WithContext(ctx).Where("parent_id = ?", id).Count(&count).Error
+	if err != nil {
+		log.Logger.Error().Err(err).Int64("categoryID", id).Msg("Failed to count child categories")
+		return 0, fmt.Errorf("failed to count child categories: %w", err)
+	}
+	return count, nil
+}
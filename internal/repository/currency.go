@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"product-catalog-service/infrastructure/log"
+	"product-catalog-service/internal/entity"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CurrencyRepository defines the interface for exchange rate and per-currency price list operations.
+type CurrencyRepository interface {
+	// GetExchangeRates retrieves every exchange rate.
+	// Returns:
+	//   - The exchange rates ordered by base and quote currency.
+	//   - An error if any issues occur during retrieval.
+	GetExchangeRates(ctx context.Context) ([]entity.ExchangeRate, error)
+
+	// GetExchangeRate retrieves the rate from one currency to another.
+	// Parameters:
+	//   - base: The currency converted from.
+	//   - quote: The currency converted to.
+	// Returns:
+	//   - A pointer to the ExchangeRate entity if found, or nil if not found.
+	//   - An error if any issues occur during retrieval.
+	GetExchangeRate(ctx context.Context, base, quote string) (*entity.ExchangeRate, error)
+
+	// SaveExchangeRate creates or replaces the rate of a currency pair.
+	// Parameters:
+	//   - rate: A pointer to the ExchangeRate entity to save.
+	// Returns:
+	//   - An error if any issues occur during the update.
+	SaveExchangeRate(ctx context.Context, rate *entity.ExchangeRate) error
+
+	// DeleteExchangeRate deletes the rate of a currency pair.
+	// Parameters:
+	//   - base: The currency converted from.
+	//   - quote: The currency converted to.
+	// Returns:
+	//   - Whether a rate was deleted.
+	//   - An error if any issues occur during deletion.
+	DeleteExchangeRate(ctx context.Context, base, quote string) (bool, error)
+
+	// GetCurrencyPrices retrieves the explicit prices of several products in one currency.
+	// Parameters:
+	//   - productIDs: The IDs of the products to look up.
+	//   - currency: The currency of the prices.
+	// Returns:
+	//   - A map from product ID to its price. Products without a price in the currency are absent.
+	//   - An error if any issues occur during retrieval.
+	GetCurrencyPrices(ctx context.Context, productIDs []int64, currency string) (map[int64]entity.CurrencyPrice, error)
+
+	// GetProductCurrencyPrices retrieves every explicit price of a product.
+	// Parameters:
+	//   - productID: The ID of the product.
+	// Returns:
+	//   - The prices ordered by currency.
+	//   - An error if any issues occur during retrieval.
+	GetProductCurrencyPrices(ctx context.Context, productID int64) ([]entity.CurrencyPrice, error)
+
+	// SaveCurrencyPrice creates or replaces the explicit price of a product in a currency.
+	// Parameters:
+	//   - price: A pointer to the CurrencyPrice entity to save.
+	// Returns:
+	//   - An error if any issues occur during the update.
+	SaveCurrencyPrice(ctx context.Context, price *entity.CurrencyPrice) error
+
+	// DeleteCurrencyPrice deletes the explicit price of a product in a currency.
+	// Parameters:
+	//   - productID: The ID of the product.
+	//   - currency: The currency of the price.
+	// Returns:
+	//   - Whether a price was deleted.
+	//   - An error if any issues occur during deletion.
+	DeleteCurrencyPrice(ctx context.Context, productID int64, currency string) (bool, error)
+}
+
+// currencyRepository is a concrete implementation of the CurrencyRepository interface.
+type currencyRepository struct {
+	db *gorm.DB
+}
+
+// NewCurrencyRepository creates a new instance of currencyRepository.
+// Returns:
+//   - A CurrencyRepository instance.
+func NewCurrencyRepository(db *gorm.DB) CurrencyRepository {
+	return &currencyRepository{
+		db: db,
+	}
+}
+
+func (r *currencyRepository) GetExchangeRates(ctx context.Context) ([]entity.ExchangeRate, error) {
+	rates := []entity.ExchangeRate{}
+	err := r.db.Table("exchange_rates").WithContext(ctx).
+		Order("base_currency ASC, quote_currency ASC").
+		Find(&rates).Error
+	if err != nil {
+		log.Logger.Error().Err(err).Msg("Failed to get exchange rates from database")
+		return nil, fmt.Errorf("failed to get exchange rates from database: %w", err)
+	}
+	return rates, nil
+}
+
+func (r *currencyRepository) GetExchangeRate(ctx context.Context, base, quote string) (*entity.ExchangeRate, error) {
+	var rate entity.ExchangeRate
+	err := r.db.Table("exchange_rates").WithContext(ctx).
+		Where("base_currency = ? AND quote_currency = ?", base, quote).
+		First(&rate).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		log.Logger.Error().Err(err).Str("base", base).Str("quote", quote).Msg("Failed to get exchange rate from database")
+		return nil, fmt.Errorf("failed to get exchange rate from database: %w", err)
+	}
+	return &rate, nil
+}
+
+func (r *currencyRepository) SaveExchangeRate(ctx context.Context, rate *entity.ExchangeRate) error {
+	err := r.db.Table("exchange_rates").WithContext(ctx).Clauses(clause.OnConflict{
+		DoUpdates: clause.AssignmentColumns([]string{"rate", "updated_at"}),
+	}).Create(rate).Error
+	if err != nil {
+		log.Logger.Error().Err(err).Str("base", rate.BaseCurrency).Str("quote", rate.QuoteCurrency).Msg("Failed to save exchange rate in database")
+		return fmt.Errorf("failed to save exchange rate in database: %w", err)
+	}
+	return nil
+}
+
+func (r *currencyRepository) DeleteExchangeRate(ctx context.Context, base, quote string) (bool, error) {
+	result := r.db.Table("exchange_rates").WithContext(ctx).
+		Where("base_currency = ? AND quote_currency = ?", base, quote).
+		Delete(&entity.ExchangeRate{})
+	if result.Error != nil {
+		log.Logger.Error().Err(result.Error).Str("base", base).Str("quote", quote).Msg("Failed to delete exchange rate from database")
+		return false, fmt.Errorf("failed to delete exchange rate from database: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *currencyRepository) GetCurrencyPrices(ctx context.Context, productIDs []int64, currency string) (map[int64]entity.CurrencyPrice, error) {
+	pricesByProduct := make(map[int64]entity.CurrencyPrice)
+	if len(productIDs) == 0 {
+		return pricesByProduct, nil
+	}
+
+	var prices []entity.CurrencyPrice
+	err := r.db.Table("product_currency_prices").WithContext(ctx).
+		Where("product_id IN ? AND price_currency = ?", productIDs, currency).
+		Find(&prices).Error
+	if err != nil {
+		log.Logger.Error().Err(err).Str("currency", currency).Msg("Failed to get currency prices from database")
+		return nil, fmt.Errorf("failed to get currency prices from database: %w", err)
+	}
+
+	for _, price := range prices {
+		pricesByProduct[price.ProductID] = price
+	}
+	return pricesByProduct, nil
+}
+
+func (r *currencyRepository) GetProductCurrencyPrices(ctx context.Context, productID int64) ([]entity.CurrencyPrice, error) {
+	prices := []entity.CurrencyPrice{}
+	err := r.db.Table("product_currency_prices").WithContext(ctx).
+		Where("product_id = ?", productID).
+		Order("price_currency ASC").
+		Find(&prices).Error
+	if err != nil {
+		log.Logger.Error().Err(err).Int64("productID", productID).Msg("Failed to get product currency prices from database")
+		return nil, fmt.Errorf("failed to get product currency prices from database: %w", err)
+	}
+	return prices, nil
+}
+
+func (r *currencyRepository) SaveCurrencyPrice(ctx context.Context, price *entity.CurrencyPrice) error {
+	err := r.db.Table("product_currency_prices").WithContext(ctx).Clauses(clause.OnConflict{
+		DoUpdates: clause.AssignmentColumns([]string{"price_amount", "updated_at"}),
+	}).Create(price).Error
+	if err != nil {
+		log.Logger.Error().Err(err).Int64("productID", price.ProductID).Str("currency", price.Price.Currency).Msg("Failed to save currency price in database")
+		return fmt.Errorf("failed to save currency price in database: %w", translateError(err))
+	}
+	return nil
+}
+
+func (r *currencyRepository) DeleteCurrencyPrice(ctx context.Context, productID int64, currency string) (bool, error) {
+	result := r.db.Table("product_currency_prices").WithContext(ctx).
+		Where("product_id = ? AND price_currency = ?", productID, currency).
+		Delete(&entity.CurrencyPrice{})
+	if result.Error != nil {
+		log.Logger.Error().Err(result.Error).Int64("productID", productID).Str("currency", currency).Msg("Failed to delete currency price from database")
+		return false, fmt.Errorf("failed to delete currency price from database: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"errors"
+	"product-catalog-service/internal/apperror"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrNotFound is returned by writes to a row that does not exist. Reads return nil instead.
+	ErrNotFound = apperror.New(apperror.KindNotFound, "not_found", "resource not found")
+	// ErrDuplicate is returned when a write violates a unique key, e.g. an external SKU or tag name that is already used.
+	ErrDuplicate = apperror.New(apperror.KindConflict, "duplicate", "resource already exists")
+	// ErrInvalidReference is returned when a write references a row that does not exist, e.g. an unknown category.
+	ErrInvalidReference = apperror.New(apperror.KindInvalid, "invalid_reference", "referenced resource does not exist")
+	// ErrMissingExternalSKU is returned when a product upserted by external SKU has none.
+	ErrMissingExternalSKU = apperror.New(apperror.KindInvalid, "missing_external_sku", "product has no external SKU")
+)
+
+// translateError replaces the constraint violations reported by the database with domain errors.
+// It relies on gorm.Config.TranslateError being enabled.
+func translateError(err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return ErrDuplicate
+	case errors.Is(err, gorm.ErrForeignKeyViolated):
+		return ErrInvalidReference
+	}
+	return err
+}
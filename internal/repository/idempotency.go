@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"product-catalog-service/internal/entity"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// IdempotencyRepository keeps the records of idempotency keys in Redis, shared by every instance.
+type IdempotencyRepository interface {
+	// ClaimKey stores the record of a key unless the key already has one.
+	// Parameters:
+	//   - key: The idempotency key, including its scope.
+	//   - record: The record of the request claiming the key.
+	//   - ttl: How long the claim is kept if it is never completed.
+	// Returns:
+	//   - Whether the key was claimed by this call.
+	//   - The existing record when the key was not claimed.
+	//   - An error if the store could not be reached.
+	ClaimKey(ctx context.Context, key string, record entity.IdempotencyRecord, ttl time.Duration) (bool, *entity.IdempotencyRecord, error)
+
+	// SaveRecord replaces the record of a key, e.g. with the response of the completed request.
+	// Parameters:
+	//   - key: The idempotency key, including its scope.
+	//   - record: The record to keep.
+	//   - ttl: How long the record is kept.
+	// Returns:
+	//   - An error if the record could not be stored.
+	SaveRecord(ctx context.Context, key string, record entity.IdempotencyRecord, ttl time.Duration) error
+
+	// DeleteRecord drops the record of a key so that the key can be claimed again.
+	// Parameters:
+	//   - key: The idempotency key, including its scope.
+	// Returns:
+	//   - An error if the record could not be deleted.
+	DeleteRecord(ctx context.Context, key string) error
+}
+
+type idempotencyRepository struct {
+	rdb *redis.Client
+}
+
+// NewIdempotencyRepository creates and returns a new instance of idempotencyRepository.
+func NewIdempotencyRepository(rdb *redis.Client) IdempotencyRepository {
+	return &idempotencyRepository{
+		rdb: rdb,
+	}
+}
+
+func (r *idempotencyRepository) ClaimKey(ctx context.Context, key string, record entity.IdempotencyRecord, ttl time.Duration) (bool, *entity.IdempotencyRecord, error) {
+	payload, err := json.Marshal(record)
+	if err != nil {
+		return false, nil, fmt.Errorf("failed to marshal idempotency record: %w", err)
+	}
+	claimed, err := r.rdb.SetNX(ctx, idempotencyKey(key), payload, ttl).Result()
+	if err != nil {
+		return false, nil, fmt.Errorf("failed to claim idempotency key: %w", err)
+	}
+	if claimed {
+		return true, nil, nil
+	}
+
+	value, err := r.rdb.Get(ctx, idempotencyKey(key)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		// The record expired between the two calls; claim the key again.
+		return r.ClaimKey(ctx, key, record, ttl)
+	}
+	if err != nil {
+		return false, nil, fmt.Errorf("failed to get idempotency record: %w", err)
+	}
+	var existing entity.IdempotencyRecord
+	if err := json.Unmarshal(value, &existing); err != nil {
+		return false, nil, fmt.Errorf("failed to unmarshal idempotency record: %w", err)
+	}
+	return false, &existing, nil
+}
+
+func (r *idempotencyRepository) SaveRecord(ctx context.Context, key string, record entity.IdempotencyRecord, ttl time.Duration) error {
+	payload, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal idempotency record: %w", err)
+	}
+	if err := r.rdb.Set(ctx, idempotencyKey(key), payload, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to save idempotency record: %w", err)
+	}
+	return nil
+}
+
+func (r *idempotencyRepository) DeleteRecord(ctx context.Context, key string) error {
+	if err := r.rdb.Del(ctx, idempotencyKey(key)).Err(); err != nil {
+		return fmt.Errorf("failed to delete idempotency record: %w", err)
+	}
+	return nil
+}
+
+func idempotencyKey(key string) string {
+	return "idempotency:" + key
+}
@@ -0,0 +1,419 @@
+package repository
+
+import (
+	"container/list"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"product-catalog-service/infrastructure/log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// LRUCacheOptions configures the in-process tier of the cache.
+type LRUCacheOptions struct {
+	// MaxEntries bounds the number of keys held in memory.
+	MaxEntries int
+	// MaxBytes, when set, bounds the total size of the keys and values held in memory.
+	MaxBytes int
+	// TTL bounds how long a key is served from memory. It also bounds how long an instance can serve
+	// a stale value when an invalidation message is lost.
+	TTL time.Duration
+	// Prefixes selects the keys held in memory; every other key, and every lock, goes to the next
+	// tier only.
+	Prefixes []string
+	// Channel is the Redis pub/sub channel that fans deleted keys out to every instance.
+	Channel string
+}
+
+// LRUCacheStats is a snapshot of the counters of the in-process tier. Hits and misses only count
+// keys held in memory.
+type LRUCacheStats struct {
+	Hits          uint64
+	Misses        uint64
+	Evictions     uint64
+	Invalidations uint64
+	Entries       int
+	Bytes         int
+}
+
+// HitRatio returns the share of lookups served from memory, or zero before the first lookup.
+func (s LRUCacheStats) HitRatio() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
+
+// LRUCacheRepository is a CacheRepository that keeps recently read keys in memory in front of
+// another CacheRepository, usually Redis. Setting or deleting a key drops it from the memory of
+// every instance, so changes such as updated or deleted products are seen everywhere.
+type LRUCacheRepository interface {
+	CacheRepository
+
+	// Stats returns the counters of the in-process tier.
+	Stats() LRUCacheStats
+
+	// Start applies the invalidations published by other instances and logs the counters on every
+	// statsInterval, or never if it is zero, until ctx is done.
+	Start(ctx context.Context, statsInterval time.Duration)
+}
+
+// lruEntry is a key held in memory.
+type lruEntry struct {
+	key   string
+	value string
+	// expiresAt is when the key stops being served from memory.
+	expiresAt time.Time
+	// remoteExpiresAt is when the key expires in the next tier, zero if it does not.
+	remoteExpiresAt time.Time
+}
+
+// lruFill is a read of a key from the next tier that has not been stored in memory yet.
+type lruFill struct {
+	// reads counts the reads of the key in flight that share the fill.
+	reads int
+	// stale is set when the key is invalidated during the read, so the value read is not stored.
+	stale bool
+}
+
+// cacheInvalidation is a changed key fanned out to every instance.
+type cacheInvalidation struct {
+	Origin string `json:"origin"`
+	Key    string `json:"key"`
+}
+
+type lruCacheRepository struct {
+	next    CacheRepository
+	rdb     *redis.Client
+	options LRUCacheOptions
+	// origin identifies this instance, which skips its own invalidations when they come back.
+	origin string
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List // Most recently used first
+	bytes   int
+	// fills holds the reads in flight per key, so that an invalidation racing with a read of the
+	// same key keeps the value read before from being stored, without affecting other keys.
+	fills map[string]*lruFill
+	stats LRUCacheStats
+}
+
+// NewLRUCacheRepository creates and returns a new instance of lruCacheRepository in front of next.
+// Invalidations are fanned out on the Redis channel of the options.
+func NewLRUCacheRepository(next CacheRepository, rdb *redis.Client, options LRUCacheOptions) LRUCacheRepository {
+	var b [8]byte
+	_, _ = rand.Read(b[:])
+	return &lruCacheRepository{
+		next:    next,
+		rdb:     rdb,
+		options: options,
+		origin:  hex.EncodeToString(b[:]),
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+		fills:   make(map[string]*lruFill),
+	}
+}
+
+// Set sets the key in the next tier and drops it from the memory of every instance, which would
+// otherwise serve the value they hold until it expires.
+func (r *lruCacheRepository) Set(ctx context.Context, key string, value interface{}) error {
+	err := r.next.Set(ctx, key, value)
+	r.invalidate(ctx, key)
+	return err
+}
+
+func (r *lruCacheRepository) SetWithTTL(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	err := r.next.SetWithTTL(ctx, key, value, ttl)
+	r.invalidate(ctx, key)
+	return err
+}
+
+func (r *lruCacheRepository) Get(ctx context.Context, key string) (string, error) {
+	value, _, err := r.GetWithTTL(ctx, key)
+	return value, err
+}
+
+func (r *lruCacheRepository) GetWithTTL(ctx context.Context, key string) (string, time.Duration, error) {
+	if !r.holds(key) {
+		return r.next.GetWithTTL(ctx, key)
+	}
+	if value, ttl, ok := r.lookup(key); ok {
+		return value, ttl, nil
+	}
+
+	fill := r.startFill(key)
+	value, ttl, err := r.next.GetWithTTL(ctx, key)
+	if err != nil || value == "" {
+		r.finishFill(key, fill)
+		return value, ttl, err
+	}
+	r.store(key, value, ttl, fill)
+	return value, ttl, nil
+}
+
+// MGet serves the keys held in memory and reads the others from the next tier in one round trip.
+// Keys read in bulk are not added to memory, since their remaining time to live is unknown.
+func (r *lruCacheRepository) MGet(ctx context.Context, keys []string) ([]string, error) {
+	values := make([]string, len(keys))
+	var missing []string
+	var missingAt []int
+	for i, key := range keys {
+		if r.holds(key) {
+			if value, _, ok := r.lookup(key); ok {
+				values[i] = value
+				continue
+			}
+		}
+		missing = append(missing, key)
+		missingAt = append(missingAt, i)
+	}
+	if len(missing) == 0 {
+		return values, nil
+	}
+
+	fetched, err := r.next.MGet(ctx, missing)
+	if err != nil {
+		return nil, err
+	}
+	for i, value := range fetched {
+		values[missingAt[i]] = value
+	}
+	return values, nil
+}
+
+// Delete deletes the key from the next tier and from the memory of every instance. The key is
+// dropped from memory even when the next tier fails, since the caller changed what it caches.
+func (r *lruCacheRepository) Delete(ctx context.Context, key string) error {
+	err := r.next.Delete(ctx, key)
+	r.invalidate(ctx, key)
+	return err
+}
+
+func (r *lruCacheRepository) Lock(ctx context.Context, key string, ttl time.Duration) (string, bool, error) {
+	return r.next.Lock(ctx, key, ttl)
+}
+
+func (r *lruCacheRepository) Unlock(ctx context.Context, key, token string) error {
+	return r.next.Unlock(ctx, key, token)
+}
+
+func (r *lruCacheRepository) Stats() LRUCacheStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stats := r.stats
+	stats.Entries = r.order.Len()
+	stats.Bytes = r.bytes
+	return stats
+}
+
+func (r *lruCacheRepository) Start(ctx context.Context, statsInterval time.Duration) {
+	pubsub := r.rdb.Subscribe(ctx, r.options.Channel)
+	defer pubsub.Close()
+	messages := pubsub.ChannelWithSubscriptions(ctx, 100)
+
+	var report <-chan time.Time
+	if statsInterval > 0 {
+		ticker := time.NewTicker(statsInterval)
+		defer ticker.Stop()
+		report = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-report:
+			stats := r.Stats()
+			log.Logger.Info().
+				Uint64("hits", stats.Hits).
+				Uint64("misses", stats.Misses).
+				Float64("hitRatio", stats.HitRatio()).
+				Uint64("evictions", stats.Evictions).
+				Uint64("invalidations", stats.Invalidations).
+				Int("entries", stats.Entries).
+				Int("bytes", stats.Bytes).
+				Msg("In-process cache statistics")
+		case msg, ok := <-messages:
+			if !ok {
+				return
+			}
+			switch msg := msg.(type) {
+			case *redis.Subscription:
+				// Invalidations published while the connection was down are lost, so memory is
+				// cleared whenever the subscription is made again.
+				if msg.Kind == "subscribe" {
+					r.clear()
+				}
+			case *redis.Message:
+				var invalidation cacheInvalidation
+				if err := json.Unmarshal([]byte(msg.Payload), &invalidation); err != nil {
+					log.Logger.Error().Err(err).Str("channel", r.options.Channel).Msg("Failed to unmarshal cache invalidation")
+					continue
+				}
+				if invalidation.Origin != r.origin {
+					r.forget(invalidation.Key)
+				}
+			}
+		}
+	}
+}
+
+// invalidate drops a changed key from memory and publishes it to the other instances. A lost
+// invalidation leaves them serving the old value for at most the TTL of the options.
+func (r *lruCacheRepository) invalidate(ctx context.Context, key string) {
+	if !r.holds(key) {
+		return
+	}
+	r.forget(key)
+
+	payload, err := json.Marshal(cacheInvalidation{Origin: r.origin, Key: key})
+	if err == nil {
+		err = r.rdb.Publish(ctx, r.options.Channel, payload).Err()
+	}
+	if err != nil {
+		log.Logger.Warn().Err(err).Str("key", key).Msg("Failed to publish cache invalidation")
+	}
+}
+
+// holds reports whether key is held in memory when read.
+func (r *lruCacheRepository) holds(key string) bool {
+	for _, prefix := range r.options.Prefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// lookup returns a key held in memory with its remaining time to live in the next tier.
+func (r *lruCacheRepository) lookup(key string) (string, time.Duration, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	element, ok := r.entries[key]
+	if !ok {
+		r.stats.Misses++
+		return "", 0, false
+	}
+	entry := element.Value.(*lruEntry)
+	now := time.Now()
+	if !now.Before(entry.expiresAt) {
+		r.remove(element)
+		r.stats.Misses++
+		return "", 0, false
+	}
+
+	r.order.MoveToFront(element)
+	r.stats.Hits++
+	var ttl time.Duration
+	if !entry.remoteExpiresAt.IsZero() {
+		ttl = entry.remoteExpiresAt.Sub(now)
+	}
+	return entry.value, ttl, true
+}
+
+// store adds a key read from the next tier with the given remaining time to live, unless the key
+// was invalidated during the fill, and evicts the least recently used keys beyond the limits.
+func (r *lruCacheRepository) store(key, value string, remoteTTL time.Duration, fill *lruFill) {
+	size := len(key) + len(value)
+	if r.options.MaxBytes > 0 && size > r.options.MaxBytes {
+		r.finishFill(key, fill)
+		return
+	}
+
+	now := time.Now()
+	entry := &lruEntry{key: key, value: value, expiresAt: now.Add(r.options.TTL)}
+	if remoteTTL > 0 {
+		entry.remoteExpiresAt = now.Add(remoteTTL)
+		if entry.remoteExpiresAt.Before(entry.expiresAt) {
+			entry.expiresAt = entry.remoteExpiresAt
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.endFill(key, fill)
+	if fill.stale {
+		return
+	}
+	if element, ok := r.entries[key]; ok {
+		r.remove(element)
+	}
+	r.entries[key] = r.order.PushFront(entry)
+	r.bytes += size
+
+	for r.order.Len() > r.options.MaxEntries || (r.options.MaxBytes > 0 && r.bytes > r.options.MaxBytes) {
+		r.remove(r.order.Back())
+		r.stats.Evictions++
+	}
+}
+
+// startFill records a read of key from the next tier, to pass to store once it is done. Reads of
+// the same key in flight together share a fill.
+func (r *lruCacheRepository) startFill(key string) *lruFill {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	fill, ok := r.fills[key]
+	if !ok {
+		fill = &lruFill{}
+		r.fills[key] = fill
+	}
+	fill.reads++
+	return fill
+}
+
+// finishFill ends a read that stores nothing.
+func (r *lruCacheRepository) finishFill(key string, fill *lruFill) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.endFill(key, fill)
+}
+
+// endFill ends one read of a fill, and stops tracking the fill after the last one. The caller
+// holds mu.
+func (r *lruCacheRepository) endFill(key string, fill *lruFill) {
+	fill.reads--
+	if fill.reads == 0 && r.fills[key] == fill {
+		delete(r.fills, key)
+	}
+}
+
+// forget drops a changed key from memory, and from the reads of it in flight.
+func (r *lruCacheRepository) forget(key string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if fill, ok := r.fills[key]; ok {
+		fill.stale = true
+		delete(r.fills, key)
+	}
+	if element, ok := r.entries[key]; ok {
+		r.remove(element)
+		r.stats.Invalidations++
+	}
+}
+
+// clear drops every key from memory.
+func (r *lruCacheRepository) clear() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for key, fill := range r.fills {
+		fill.stale = true
+		delete(r.fills, key)
+	}
+	r.stats.Invalidations += uint64(r.order.Len())
+	r.entries = make(map[string]*list.Element)
+	r.order.Init()
+	r.bytes = 0
+}
+
+// remove drops an element from memory. The caller holds mu.
+func (r *lruCacheRepository) remove(element *list.Element) {
+	entry := r.order.Remove(element).(*lruEntry)
+	delete(r.entries, entry.key)
+	r.bytes -= len(entry.key) + len(entry.value)
+}
@@ -0,0 +1,267 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"product-catalog-service/infrastructure/log"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/rs/zerolog"
+)
+
+func TestMain(m *testing.M) {
+	logger := zerolog.Nop()
+	log.Logger = &logger
+	os.Exit(m.Run())
+}
+
+type fakeCacheEntry struct {
+	value     string
+	expiresAt time.Time
+}
+
+// fakeCache is an in-memory CacheRepository, like Redis with a default time to live of two minutes.
+type fakeCache struct {
+	mu      sync.Mutex
+	entries map[string]fakeCacheEntry
+	// reads, when set, receives a channel from every read of a key, which waits on it after reading
+	// the value, so tests can change the key while the read is in flight.
+	reads chan chan struct{}
+}
+
+func newFakeCache() *fakeCache {
+	return &fakeCache{entries: make(map[string]fakeCacheEntry)}
+}
+
+func (c *fakeCache) Set(ctx context.Context, key string, value interface{}) error {
+	return c.SetWithTTL(ctx, key, value, 2*time.Minute)
+}
+
+func (c *fakeCache) SetWithTTL(_ context.Context, key string, value interface{}, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = fakeCacheEntry{value: fmt.Sprint(value), expiresAt: time.Now().Add(ttl)}
+	return nil
+}
+
+func (c *fakeCache) Get(ctx context.Context, key string) (string, error) {
+	value, _, err := c.GetWithTTL(ctx, key)
+	return value, err
+}
+
+func (c *fakeCache) GetWithTTL(_ context.Context, key string) (string, time.Duration, error) {
+	value, ttl := c.lookup(key)
+	if c.reads != nil {
+		release := make(chan struct{})
+		c.reads <- release
+		<-release
+	}
+	return value, ttl, nil
+}
+
+func (c *fakeCache) lookup(key string) (string, time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok {
+		return "", 0
+	}
+	ttl := time.Until(entry.expiresAt)
+	if ttl <= 0 {
+		delete(c.entries, key)
+		return "", 0
+	}
+	return entry.value, ttl
+}
+
+func (c *fakeCache) MGet(_ context.Context, keys []string) ([]string, error) {
+	values := make([]string, len(keys))
+	for i, key := range keys {
+		values[i], _ = c.lookup(key)
+	}
+	return values, nil
+}
+
+func (c *fakeCache) Delete(_ context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, key)
+	return nil
+}
+
+func (c *fakeCache) Lock(_ context.Context, key string, ttl time.Duration) (string, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if entry, ok := c.entries[key]; ok && time.Now().Before(entry.expiresAt) {
+		return "", false, nil
+	}
+	token := fmt.Sprintf("token-%d", time.Now().UnixNano())
+	c.entries[key] = fakeCacheEntry{value: token, expiresAt: time.Now().Add(ttl)}
+	return token, true, nil
+}
+
+func (c *fakeCache) Unlock(_ context.Context, key, token string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries[key].value == token {
+		delete(c.entries, key)
+	}
+	return nil
+}
+
+// newTestLRUCache returns an in-process cache of product keys in front of next. Invalidations are
+// published to a Redis server that does not exist, which is logged and otherwise ignored.
+func newTestLRUCache(t *testing.T, next CacheRepository) *lruCacheRepository {
+	t.Helper()
+	rdb := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1, DialTimeout: 100 * time.Millisecond})
+	t.Cleanup(func() { _ = rdb.Close() })
+	return NewLRUCacheRepository(next, rdb, LRUCacheOptions{
+		MaxEntries: 100,
+		TTL:        time.Minute,
+		Prefixes:   []string{"product:"},
+		Channel:    "cache-invalidations",
+	}).(*lruCacheRepository)
+}
+
+// get reads a key through cache and fails the test if the value is not want.
+func get(t *testing.T, cache CacheRepository, key, want string) {
+	t.Helper()
+	value, err := cache.Get(context.Background(), key)
+	if err != nil {
+		t.Fatalf("Get(%s): %v", key, err)
+	}
+	if value != want {
+		t.Fatalf("Get(%s) = %q, want %q", key, value, want)
+	}
+}
+
+// startRead reads a key through cache in the background, until it waits in the next tier, and
+// returns the channel that releases the read and the channel that receives the value read.
+func startRead(next *fakeCache, cache CacheRepository, key string) (chan struct{}, chan string) {
+	values := make(chan string, 1)
+	go func() {
+		value, _ := cache.Get(context.Background(), key)
+		values <- value
+	}()
+	return <-next.reads, values
+}
+
+func TestLRUCacheInvalidatesOnWrite(t *testing.T) {
+	ctx := context.Background()
+	for name, write := range map[string]func(CacheRepository) error{
+		"Set":        func(c CacheRepository) error { return c.Set(ctx, "product:1", "v2") },
+		"SetWithTTL": func(c CacheRepository) error { return c.SetWithTTL(ctx, "product:1", "v2", time.Minute) },
+		"Delete":     func(c CacheRepository) error { return c.Delete(ctx, "product:1") },
+	} {
+		t.Run(name, func(t *testing.T) {
+			next := newFakeCache()
+			cache := newTestLRUCache(t, next)
+			_ = next.Set(ctx, "product:1", "v1")
+			get(t, cache, "product:1", "v1")
+
+			// A change made behind the cache is not seen until the entry is invalidated.
+			_ = next.Set(ctx, "product:1", "v0")
+			get(t, cache, "product:1", "v1")
+
+			if err := write(cache); err != nil {
+				t.Fatalf("%s: %v", name, err)
+			}
+			want, _ := next.Get(ctx, "product:1")
+			get(t, cache, "product:1", want)
+			if stats := cache.Stats(); stats.Invalidations != 1 {
+				t.Errorf("Invalidations = %d, want 1", stats.Invalidations)
+			}
+		})
+	}
+}
+
+func TestLRUCacheWriteDuringFillLeavesNoStaleEntry(t *testing.T) {
+	ctx := context.Background()
+	next := newFakeCache()
+	cache := newTestLRUCache(t, next)
+	_ = next.Set(ctx, "product:1", "v1")
+
+	next.reads = make(chan chan struct{})
+	release, values := startRead(next, cache, "product:1")
+	next.reads = nil
+	if err := cache.Set(ctx, "product:1", "v2"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	close(release)
+	if value := <-values; value != "v1" {
+		t.Fatalf("read in flight = %q, want v1", value)
+	}
+
+	get(t, cache, "product:1", "v2")
+}
+
+func TestLRUCacheWriteDuringSharedFillLeavesNoStaleEntry(t *testing.T) {
+	ctx := context.Background()
+	next := newFakeCache()
+	cache := newTestLRUCache(t, next)
+	_ = next.Set(ctx, "product:1", "v1")
+
+	// The first read stores its value while the second is in flight; the write drops the value
+	// and must keep the second read from storing it again.
+	next.reads = make(chan chan struct{})
+	releaseFirst, firstValues := startRead(next, cache, "product:1")
+	releaseSecond, secondValues := startRead(next, cache, "product:1")
+	next.reads = nil
+	close(releaseFirst)
+	<-firstValues
+	if err := cache.Set(ctx, "product:1", "v2"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	close(releaseSecond)
+	<-secondValues
+
+	get(t, cache, "product:1", "v2")
+}
+
+func TestLRUCacheWriteDuringFillOfAnotherKey(t *testing.T) {
+	ctx := context.Background()
+	next := newFakeCache()
+	cache := newTestLRUCache(t, next)
+	_ = next.Set(ctx, "product:1", "v1")
+
+	next.reads = make(chan chan struct{})
+	release, values := startRead(next, cache, "product:1")
+	next.reads = nil
+	if err := cache.Set(ctx, "product:2", "v2"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	close(release)
+	<-values
+
+	// The fill of product:1 was not affected, so it is served from memory.
+	_ = next.Set(ctx, "product:1", "v0")
+	get(t, cache, "product:1", "v1")
+}
+
+func TestLRUCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	next := newFakeCache()
+	cache := newTestLRUCache(t, next)
+	cache.options.MaxEntries = 2
+	for _, key := range []string{"product:1", "product:2", "product:3"} {
+		_ = next.Set(ctx, key, "v1")
+	}
+
+	get(t, cache, "product:1", "v1")
+	get(t, cache, "product:2", "v1")
+	get(t, cache, "product:1", "v1")
+	get(t, cache, "product:3", "v1")
+
+	// product:2 was evicted, so the change behind the cache is seen, unlike for product:1.
+	_ = next.Set(ctx, "product:1", "v2")
+	_ = next.Set(ctx, "product:2", "v2")
+	get(t, cache, "product:1", "v1")
+	get(t, cache, "product:2", "v2")
+	if stats := cache.Stats(); stats.Evictions != 2 {
+		t.Errorf("Evictions = %d, want 2", stats.Evictions)
+	}
+}
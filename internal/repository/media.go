@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"product-catalog-service/infrastructure/log"
+	"product-catalog-service/internal/entity"
+
+	"gorm.io/gorm"
+)
+
+// MediaRepository defines the interface for product media database operations.
+// It only stores metadata; the files themselves live in a storage.Storage.
+type MediaRepository interface {
+	// GetMediaByID retrieves a media entry by its ID.
+	// Parameters:
+	//   - id: The ID of the media entry to retrieve.
+	// Returns:
+	//   - A pointer to the ProductMedia entity if found, or nil if not found.
+	//   - An error if any issues occur during retrieval.
+	GetMediaByID(ctx context.Context, id int64) (*entity.ProductMedia, error)
+
+	// GetMediaByProductIDs retrieves the media of several products at once.
+	// Parameters:
+	//   - productIDs: The IDs of the products to look up.
+	// Returns:
+	//   - A map from product ID to its media ordered by position. Products without media are absent.
+	//   - An error if any issues occur during retrieval.
+	GetMediaByProductIDs(ctx context.Context, productIDs []int64) (map[int64][]entity.ProductMedia, error)
+
+	// CreateMedia appends a media entry after the existing media of the product.
+	// Parameters:
+	//   - media: A pointer to the ProductMedia entity to create. Its ID and Position are set on success.
+	// Returns:
+	//   - An error if any issues occur during creation.
+	CreateMedia(ctx context.Context, media *entity.ProductMedia) error
+
+	// DeleteMedia deletes a media entry and closes the gap in the positions of the remaining media.
+	// Parameters:
+	//   - media: A pointer to the ProductMedia entity to delete.
+	// Returns:
+	//   - An error if any issues occur during deletion.
+	DeleteMedia(ctx context.Context, media *entity.ProductMedia) error
+
+	// ReorderMedia sets the positions of the media of a product to the order of mediaIDs.
+	// Parameters:
+	//   - productID: The ID of the product.
+	//   - mediaIDs: Every media ID of the product in the desired order.
+	// Returns:
+	//   - An error if any issues occur during the update.
+	ReorderMedia(ctx context.Context, productID int64, mediaIDs []int64) error
+}
+
+// mediaRepository is a concrete implementation of the MediaRepository interface.
+type mediaRepository struct {
+	db *gorm.DB
+}
+
+// NewMediaRepository creates a new instance of mediaRepository.
+// Returns:
+//   - A MediaRepository instance.
+func NewMediaRepository(db *gorm.DB) MediaRepository {
+	return &mediaRepository{
+		db: db,
+	}
+}
+
+func (r *mediaRepository) GetMediaByID(ctx context.Context, id int64) (*entity.ProductMedia, error) {
+	var media entity.ProductMedia
+	err := r.db.Table("product_media").WithContext(ctx).Where("id = ?", id).First(&media).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		log.Logger.Error().Err(err).Int64("mediaID", id).Msg("Failed to get media from database")
+		return nil, fmt.Errorf("failed to get media from database: %w", err)
+	}
+	return &media, nil
+}
+
+func (r *mediaRepository) GetMediaByProductIDs(ctx context.Context, productIDs []int64) (map[int64][]entity.ProductMedia, error) {
+	mediaByProduct := make(map[int64][]entity.ProductMedia)
+	if len(productIDs) == 0 {
+		return mediaByProduct, nil
+	}
+
+	var media []entity.ProductMedia
+	err := r.db.Table("product_media").WithContext(ctx).
+		Where("product_id IN ?", productIDs).
+		Order("product_id ASC, position ASC").
+		Find(&media).Error
+	if err != nil {
+		log.Logger.Error().Err(err).Msg("Failed to get product media from database")
+		return nil, fmt.Errorf("failed to get product media from database: %w", err)
+	}
+
+	for _, m := range media {
+		mediaByProduct[m.ProductID] = append(mediaByProduct[m.ProductID], m)
+	}
+	return mediaByProduct, nil
+}
+
+func (r *mediaRepository) CreateMedia(ctx context.Context, media *entity.ProductMedia) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var lastPosition int
+		err := tx.Table("product_media").
+			Where("product_id = ?", media.ProductID).
+			Select("COALESCE(MAX(position), 0)").
+			Scan(&lastPosition).Error
+		if err != nil {
+			return err
+		}
+
+		media.Position = lastPosition + 1
+		return tx.Table("product_media").Create(media).Error
+	})
+	if err != nil {
+		log.Logger.Error().Err(err).Int64("productID", media.ProductID).Msg("Failed to create media in database")
+		return fmt.Errorf("failed to create media in database: %w", translateError(err))
+	}
+	return nil
+}
+
+func (r *mediaRepository) DeleteMedia(ctx context.Context, media *entity.ProductMedia) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Table("product_media").Delete(&entity.ProductMedia{}, media.ID).Error; err != nil {
+			return err
+		}
+		return tx.Table("product_media").
+			Where("product_id = ? AND position > ?", media.ProductID, media.Position).
+			Update("position", gorm.Expr("position - 1")).Error
+	})
+	if err != nil {
+		log.Logger.Error().Err(err).Int64("mediaID", media.ID).Msg("Failed to delete media from database")
+		return fmt.Errorf("failed to delete media from database: %w", err)
+	}
+	return nil
+}
+
+func (r *mediaRepository) ReorderMedia(ctx context.Context, productID int64, mediaIDs []int64) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i, id := range mediaIDs {
+			err := tx.Table("product_media").
+				Where("id = ? AND product_id = ?", id, productID).
+				Update("position", i+1).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Logger.Error().Err(err).Int64("productID", productID).Msg("Failed to reorder media in database")
+		return fmt.Errorf("failed to reorder media in database: %w", err)
+	}
+	return nil
+}
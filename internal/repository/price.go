@@ -0,0 +1,254 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"product-catalog-service/infrastructure/log"
+	"product-catalog-service/internal/entity"
+	"product-catalog-service/pkg/money"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PriceRepository defines the interface for product price history database operations.
+// The products table keeps the current price; product_prices records every price with the
+// time range it applies to, including changes scheduled for the future.
+type PriceRepository interface {
+	// GetPriceAt retrieves the price of a product at a point in time.
+	// Parameters:
+	//   - productID: The ID of the product.
+	//   - at: The point in time to look up.
+	// Returns:
+	//   - The history entry in effect at that time, or nil if there is none.
+	//   - An error if any issues occur during retrieval.
+	GetPriceAt(ctx context.Context, productID int64, at time.Time) (*entity.PriceChange, error)
+
+	// GetPriceHistory retrieves every price of a product, including scheduled ones.
+	// Parameters:
+	//   - productID: The ID of the product.
+	// Returns:
+	//   - The history entries ordered by EffectiveFrom.
+	//   - An error if any issues occur during retrieval.
+	GetPriceHistory(ctx context.Context, productID int64) ([]entity.PriceChange, error)
+
+	// SchedulePrice records a price taking effect at a given time. The entry in effect at that time
+	// is ended there, and the new entry lasts until the next scheduled entry, if any. An entry that
+	// starts at exactly the same time is replaced. If the time is not after now, the product price
+	// is changed immediately.
+	// Parameters:
+	//   - productID: The ID of the product.
+	//   - price: The new price.
+	//   - from: When the price takes effect.
+	//   - now: The current time.
+	// Returns:
+	//   - The recorded history entry.
+	//   - An error if any issues occur; nothing is changed in that case.
+	SchedulePrice(ctx context.Context, productID int64, price money.Money, from, now time.Time) (*entity.PriceChange, error)
+
+	// ApplyDuePrices sets the price of every product with a scheduled entry that has taken effect.
+	// Parameters:
+	//   - now: The current time.
+	// Returns:
+	//   - The IDs of the products whose price changed.
+	//   - An error if any issues occur; no price is applied in that case.
+	ApplyDuePrices(ctx context.Context, now time.Time) ([]int64, error)
+}
+
+// priceRepository is a concrete implementation of the PriceRepository interface.
+type priceRepository struct {
+	cache CacheRepository
+	db    *gorm.DB
+}
+
+// NewPriceRepository creates a new instance of priceRepository.
+// Returns:
+//   - A PriceRepository instance.
+func NewPriceRepository(cacheRepo CacheRepository, db *gorm.DB) PriceRepository {
+	return &priceRepository{
+		cache: cacheRepo,
+		db:    db,
+	}
+}
+
+func (r *priceRepository) GetPriceAt(ctx context.Context, productID int64, at time.Time) (*entity.PriceChange, error) {
+	var change entity.PriceChange
+	err := r.db.Table("product_prices").WithContext(ctx).
+		Where("product_id = ? AND effective_from <= ? AND (effective_to IS NULL OR effective_to > ?)", productID, at, at).
+		Order("effective_from DESC").
+		First(&change).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		log.Logger.Error().Err(err).Int64("productID", productID).Time("at", at).Msg("Failed to get product price from database")
+		return nil, fmt.Errorf("failed to get product price from database: %w", err)
+	}
+	return &change, nil
+}
+
+func (r *priceRepository) GetPriceHistory(ctx context.Context, productID int64) ([]entity.PriceChange, error) {
+	history := []entity.PriceChange{}
+	err := r.db.Table("product_prices").WithContext(ctx).
+		Where("product_id = ?", productID).
+		Order("effective_from ASC").
+		Find(&history).Error
+	if err != nil {
+		log.Logger.Error().Err(err).Int64("productID", productID).Msg("Failed to get price history from database")
+		return nil, fmt.Errorf("failed to get price history from database: %w", err)
+	}
+	return history, nil
+}
+
+func (r *priceRepository) SchedulePrice(ctx context.Context, productID int64, price money.Money, from, now time.Time) (*entity.PriceChange, error) {
+	immediate := !from.After(now)
+	if immediate {
+		from = now
+	}
+
+	change := &entity.PriceChange{
+		ProductID:     productID,
+		Price:         price,
+		EffectiveFrom: from,
+		CreatedAt:     now,
+	}
+	if immediate {
+		change.AppliedAt = &now
+	}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Lock the product so concurrent changes of its history are serialised.
+		var lockedID int64
+		err := tx.Table("products").
+			Where("id = ?", productID).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Pluck("id", &lockedID).Error
+		if err != nil {
+			return err
+		}
+
+		var next sql.NullTime
+		err = tx.Table("product_prices").
+			Where("product_id = ? AND effective_from > ?", productID, from).
+			Select("MIN(effective_from)").
+			Scan(&next).Error
+		if err != nil {
+			return err
+		}
+		if next.Valid {
+			change.EffectiveTo = &next.Time
+		}
+
+		err = tx.Table("product_prices").
+			Where("product_id = ? AND effective_from = ?", productID, from).
+			Delete(&entity.PriceChange{}).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Table("product_prices").
+			Where("product_id = ? AND effective_from < ? AND (effective_to IS NULL OR effective_to > ?)", productID, from, from).
+			Update("effective_to", from).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Table("product_prices").Create(change).Error; err != nil {
+			return err
+		}
+
+		if !immediate {
+			return nil
+		}
+		return tx.Table("products").
+			Where("id = ?", productID).
+			Updates(map[string]interface{}{"price_amount": price.Amount, "price_currency": price.Currency}).Error
+	})
+	if err != nil {
+		log.Logger.Error().Err(err).Int64("productID", productID).Msg("Failed to schedule product price")
+		return nil, fmt.Errorf("failed to schedule product price: %w", err)
+	}
+
+	if immediate {
+		r.invalidateProduct(ctx, productID)
+	}
+	return change, nil
+}
+
+func (r *priceRepository) ApplyDuePrices(ctx context.Context, now time.Time) ([]int64, error) {
+	var productIDs []int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var due []entity.PriceChange
+		err := tx.Table("product_prices").
+			Where("applied_at IS NULL AND effective_from <= ?", now).
+			Order("product_id ASC, effective_from ASC").
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Find(&due).Error
+		if err != nil || len(due) == 0 {
+			return err
+		}
+
+		// When several entries of a product are due, the latest one is the price now in effect.
+		latest := make(map[int64]entity.PriceChange)
+		ids := make([]int64, 0, len(due))
+		for _, change := range due {
+			latest[change.ProductID] = change
+			ids = append(ids, change.ID)
+		}
+		for productID, change := range latest {
+			err := tx.Table("products").
+				Where("id = ?", productID).
+				Updates(map[string]interface{}{"price_amount": change.Price.Amount, "price_currency": change.Price.Currency}).Error
+			if err != nil {
+				return err
+			}
+			productIDs = append(productIDs, productID)
+		}
+		return tx.Table("product_prices").Where("id IN ?", ids).Update("applied_at", now).Error
+	})
+	if err != nil {
+		log.Logger.Error().Err(err).Msg("Failed to apply scheduled prices")
+		return nil, fmt.Errorf("failed to apply scheduled prices: %w", err)
+	}
+
+	for _, productID := range productIDs {
+		r.invalidateProduct(ctx, productID)
+	}
+	return productIDs, nil
+}
+
+func (r *priceRepository) invalidateProduct(ctx context.Context, productID int64) {
+	if err := r.cache.Delete(ctx, fmt.Sprintf("product:%d", productID)); err != nil {
+		log.Logger.Error().Err(err).Int64("productID", productID).Msg("Failed to invalidate product in cache")
+	}
+}
+
+// recordCurrentPrices starts a new history entry for every product whose current price differs
+// from the entry in effect now, e.g. after a product is created or imported. It must run in the
+// transaction that changed the prices.
+func recordCurrentPrices(tx *gorm.DB, productIDs []int64, now time.Time) error {
+	if len(productIDs) == 0 {
+		return nil
+	}
+
+	err := tx.Exec(`UPDATE product_prices pp JOIN products p ON p.id = pp.product_id
+		SET pp.effective_to = ?
+		WHERE p.id IN ? AND pp.effective_from <= ? AND (pp.effective_to IS NULL OR pp.effective_to > ?)
+			AND (pp.price_amount <> p.price_amount OR pp.price_currency <> p.price_currency)`,
+		now, productIDs, now, now).Error
+	if err != nil {
+		return err
+	}
+
+	return tx.Exec(`INSERT INTO product_prices
+			(product_id, price_amount, price_currency, effective_from, effective_to, applied_at, created_at)
+		SELECT p.id, p.price_amount, p.price_currency, ?,
+			(SELECT MIN(n.effective_from) FROM product_prices n WHERE n.product_id = p.id AND n.effective_from > ?),
+			?, ?
+		FROM products p
+		WHERE p.id IN ? AND NOT EXISTS (
+			SELECT 1 FROM product_prices c
+			WHERE c.product_id = p.id AND c.effective_from <= ? AND (c.effective_to IS NULL OR c.effective_to > ?))`,
+		now, now, now, now, productIDs, now, now).Error
+}
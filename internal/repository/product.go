@@ -2,13 +2,19 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
-	"errors"
 	"fmt"
+	"math"
+	"math/rand/v2"
 	"product-catalog-service/infrastructure/log"
 	"product-catalog-service/internal/entity"
+	"sync/atomic"
+	"time"
 
+	"golang.org/x/sync/singleflight"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // ProductRepository defines the interface for product-related database operations.
@@ -21,7 +27,27 @@ type ProductRepository interface {
 	//   - An error if any issues occur during retrieval.
 	GetProductByID(ctx context.Context, id int64) (*entity.Product, error)
 
-	// CreateProduct creates a new product in the repository.
+	// GetProductsByIDs retrieves several products, reading the cache with a single MGET and the
+	// cache misses with a single query.
+	// Parameters:
+	//   - ids: The IDs of the products to retrieve.
+	// Returns:
+	//   - A map from ID to product. IDs of products that do not exist are absent.
+	//   - An error if any issues occur during retrieval.
+	GetProductsByIDs(ctx context.Context, ids []int64) (map[int64]*entity.Product, error)
+
+	// AdjustProductStock adds delta to the stock of a product in a single statement, unless the
+	// stock would become negative.
+	// Parameters:
+	//   - id: The ID of the product.
+	//   - delta: The change of the stock, negative to remove stock.
+	// Returns:
+	//   - The stock after the change.
+	//   - Whether the stock was changed; false when the product does not exist or has too little stock.
+	//   - An error if any issues occur during the update.
+	AdjustProductStock(ctx context.Context, id int64, delta int) (int, bool, error)
+
+	// CreateProduct creates a new product in the repository and starts its price history.
 	// Parameters:
 	//   - product: A pointer to the Product entity to create.
 	// Returns:
@@ -29,7 +55,8 @@ type ProductRepository interface {
 	//   - An error if any issues occur during creation.
 	CreateProduct(ctx context.Context, product *entity.Product) error
 
-	// UpdateProduct updates an existing product in the repository.
+	// UpdateProduct updates an existing product in the repository. The price is left untouched:
+	// it only changes through PriceRepository so that every change is recorded in the price history.
 	// Parameters:
 	//   - product: A pointer to the Product entity with updated data.
 	// Returns:
@@ -37,39 +64,148 @@ type ProductRepository interface {
 	//   - An error if any issues occur during the update.
 	UpdateProduct(ctx context.Context, product *entity.Product) (*entity.Product, error)
 
-	// DeleteProduct deletes a product from the repository by its ID.
+	// DeleteProduct soft-deletes a product by its ID. The row is kept, with deleted_at set,
+	// so historical order references remain valid; it is hidden from every other query.
 	// Parameters:
 	//   - id: The ID of the product to delete.
 	// Returns:
 	//   - An error if any issues occur during deletion.
 	DeleteProduct(ctx context.Context, id int64) error
 
-	// GetProducts retrieves all products from the database.
+	// RestoreProduct clears the deletion mark of a soft-deleted product.
+	// Parameters:
+	//   - id: The ID of the product to restore.
+	// Returns:
+	//   - The restored Product entity, or nil if no soft-deleted product has that ID.
+	//   - An error if any issues occur during the restore.
+	RestoreProduct(ctx context.Context, id int64) (*entity.Product, error)
+
+	// ArchiveDeletedProducts moves products soft-deleted before a cut-off into products_archive,
+	// together with a snapshot of their variants, media, tags and currency prices, and removes them
+	// and those rows from the catalogue.
+	// Parameters:
+	//   - before: Products deleted before this time are archived.
+	//   - limit: The maximum number of products archived in this call.
+	// Returns:
+	//   - The IDs of the archived products.
+	//   - The media of the archived products, whose stored files are no longer referenced.
+	//   - An error if any issues occur; nothing is archived in that case.
+	ArchiveDeletedProducts(ctx context.Context, before time.Time, limit int) ([]int64, []entity.ProductMedia, error)
+
+	// UpdateProductStatus moves a product to another lifecycle status and sets its schedule.
+	// The update only applies if the product still has the expected current status.
+	// Parameters:
+	//   - id: The ID of the product to update.
+	//   - from: The status the product is expected to have.
+	//   - change: The new status and schedule.
+	// Returns:
+	//   - The updated Product entity, or nil if the product does not exist or its status is no longer from.
+	//   - An error if any issues occur during the update.
+	UpdateProductStatus(ctx context.Context, id int64, from entity.ProductStatus, change entity.ProductStatusChange) (*entity.Product, error)
+
+	// PublishDueProducts activates scheduled products whose publish time has passed.
+	// Parameters:
+	//   - now: The current time.
+	// Returns:
+	//   - The IDs of the activated products.
+	//   - An error if any issues occur; no product is activated in that case.
+	PublishDueProducts(ctx context.Context, now time.Time) ([]int64, error)
+
+	// UnpublishDueProducts archives active products whose unpublish time has passed.
+	// Parameters:
+	//   - now: The current time.
+	// Returns:
+	//   - The IDs of the archived products.
+	//   - An error if any issues occur; no product is archived in that case.
+	UnpublishDueProducts(ctx context.Context, now time.Time) ([]int64, error)
+
+	// GetProducts retrieves all products matching the filter from the database.
 	// Parameters:
 	//   - ctx: The context for managing request deadlines, cancellation signals, and other request-scoped values.
+	//   - filter: The category and tag constraints to apply. A zero filter returns every product.
 	// Returns:
 	//   - A slice of Product entities if the operation is successful.
 	//   - Nil if an error occurs during the retrieval process.
-	GetProducts(ctx context.Context) ([]entity.Product, error)
+	GetProducts(ctx context.Context, filter entity.ProductFilter) ([]entity.Product, error)
+
+	// UpsertProducts inserts or updates products by their external SKU in a single transaction.
+	// Price changes are recorded in the price history.
+	// The stock of existing products with variants is left untouched, as it is derived from the variants.
+	// Parameters:
+	//   - products: The products to upsert. Each must have an ExternalSKU.
+	// Returns:
+	//   - The upserted products as stored, including their IDs.
+	//   - ErrMissingExternalSKU if a product has no ExternalSKU, or an error if any other issues
+	//     occur; no product of the batch is written in either case.
+	UpsertProducts(ctx context.Context, products []entity.Product) ([]entity.Product, error)
+
+	// StreamProducts reads every product in ID order, one keyset page at a time, from a single
+	// read-only transaction so all pages reflect the same consistent snapshot.
+	// Parameters:
+	//   - batchSize: The number of products per page.
+	//   - fn: Called with the snapshot time and each page of products. Returning an error stops the stream.
+	// Returns:
+	//   - The time of the snapshot the products were read from.
+	//   - An error if any issues occur during retrieval or if fn fails.
+	StreamProducts(ctx context.Context, batchSize int, fn func(snapshotAt time.Time, batch []entity.Product) error) (time.Time, error)
+}
+
+// ProductCacheOptions tunes how GetProductByID fills the product cache.
+type ProductCacheOptions struct {
+	// LockTTL, when set, makes instances take a Redis lock of this duration before loading a product
+	// missing from the cache, so that one instance queries the database while the others wait for
+	// the cache to be filled. It bounds the wait if the holder fails.
+	LockTTL time.Duration
+	// EarlyRefreshBeta scales the probabilistic early refresh of cached products: a read refreshes a
+	// product before its entry expires with a probability that grows as the expiry nears, so popular
+	// products are reloaded by one read instead of missing for every reader. Larger values refresh
+	// earlier; zero disables early refresh.
+	EarlyRefreshBeta float64
+	// NotFoundTTL, when set, caches that a product does not exist for this long, so that requests
+	// for unknown IDs do not all reach the database.
+	NotFoundTTL time.Duration
+	// IDFilter, when set, rejects IDs of products that cannot exist before the cache is read.
+	IDFilter ProductIDFilter
 }
 
+const (
+	// productLoadTimeout bounds a database load shared by concurrent readers, which is not cancelled
+	// when the reader that started it gives up.
+	productLoadTimeout = 5 * time.Second
+	// productLockPollInterval is how often an instance waiting for another to fill the cache checks it.
+	productLockPollInterval = 25 * time.Millisecond
+	// defaultProductLoadTime is the assumed duration of a database load until one has been measured.
+	defaultProductLoadTime = 10 * time.Millisecond
+	// productNotFound is cached for a product that does not exist. It is not valid JSON, so it cannot
+	// be mistaken for a product.
+	productNotFound = "-"
+)
+
 // productRepository is a concrete implementation of the ProductRepository interface.
 type productRepository struct {
-	cache CacheRepository
-	db    *gorm.DB
+	cache        CacheRepository
+	db           *gorm.DB
+	cacheOptions ProductCacheOptions
+
+	// loads coalesces concurrent loads of the same product on this instance.
+	loads singleflight.Group
+	// loadTime is a moving average of the duration of product loads, in nanoseconds, which early
+	// refresh weighs against the remaining time to live of an entry.
+	loadTime atomic.Int64
 }
 
 // NewProductRepository creates a new instance of productRepository.
 // Returns:
 //   - A ProductRepository instance.
-func NewProductRepository(cacheRepo CacheRepository, db *gorm.DB) ProductRepository {
+func NewProductRepository(cacheRepo CacheRepository, db *gorm.DB, cacheOptions ProductCacheOptions) ProductRepository {
 	return &productRepository{
-		cache: cacheRepo,
-		db:    db,
+		cache:        cacheRepo,
+		db:           db,
+		cacheOptions: cacheOptions,
 	}
 }
 
-// GetProductByID retrieves a product by its ID from the in-memory store.
+// GetProductByID retrieves a product by its ID, from the cache when possible.
 // Parameters:
 //   - id: The ID of the product to retrieve.
 //
@@ -77,41 +213,304 @@ func NewProductRepository(cacheRepo CacheRepository, db *gorm.DB) ProductReposit
 //   - A pointer to the Product entity if found, or nil if not found.
 //   - An error if any issues occur during retrieval.
 func (r *productRepository) GetProductByID(ctx context.Context, id int64) (*entity.Product, error) {
+	if !r.mayExist(id) {
+		return nil, nil
+	}
 	key := fmt.Sprintf("product:%d", id)
-	productCache, err := r.cache.Get(ctx, key)
+	productCache, ttl, err := r.cache.GetWithTTL(ctx, key)
 	if err != nil {
 		log.Logger.Error().Err(err).Int64("productID", id).Msg("Failed to get product from cache")
 		return nil, fmt.Errorf("failed to get product from cache: %w", err)
 	}
 
+	if productCache == productNotFound {
+		return nil, nil
+	}
 	if productCache != "" {
 		var productFromCache entity.Product
 		if err := json.Unmarshal([]byte(productCache), &productFromCache); err != nil {
 			log.Logger.Error().Err(err).Int64("productID", id).Msg("Failed to unmarshal product from cache")
 			return nil, fmt.Errorf("failed to unmarshal product from cache: %w", err)
 		}
+		if r.refreshEarly(ttl) {
+			go r.refreshProduct(id)
+		}
 		return &productFromCache, nil
 	}
 
+	return r.loadProduct(ctx, id)
+}
+
+// loadProduct loads a product missing from the cache. Concurrent readers of the same product share
+// one load, which is not cancelled when a reader gives up.
+func (r *productRepository) loadProduct(ctx context.Context, id int64) (*entity.Product, error) {
+	loaded := r.loads.DoChan(fmt.Sprintf("product:%d", id), func() (interface{}, error) {
+		loadCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), productLoadTimeout)
+		defer cancel()
+		return r.fillProductCache(loadCtx, id)
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case result := <-loaded:
+		if result.Err != nil {
+			return nil, result.Err
+		}
+		product := result.Val.(*entity.Product)
+		if product == nil {
+			return nil, nil
+		}
+		// Every reader gets its own copy, since callers may change the product they are given.
+		productCopy := *product
+		return &productCopy, nil
+	}
+}
+
+// refreshProduct reloads a cached product ahead of its expiry.
+func (r *productRepository) refreshProduct(id int64) {
+	result := <-r.loads.DoChan(fmt.Sprintf("product:%d", id), func() (interface{}, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), productLoadTimeout)
+		defer cancel()
+		return r.fillProductCache(ctx, id)
+	})
+	if result.Err != nil {
+		log.Logger.Warn().Err(result.Err).Int64("productID", id).Msg("Failed to refresh product in cache")
+	}
+}
+
+// fillProductCache reads a product from the database and caches it. With a lock TTL set, an
+// instance that finds another loading the product waits for the cache instead, up to the TTL.
+func (r *productRepository) fillProductCache(ctx context.Context, id int64) (*entity.Product, error) {
+	key := fmt.Sprintf("product:%d", id)
+	if r.cacheOptions.LockTTL > 0 {
+		lockKey := "lock:" + key
+		token, locked, err := r.cache.Lock(ctx, lockKey, r.cacheOptions.LockTTL)
+		switch {
+		case err != nil:
+			// The lock only spares the database, so it is skipped rather than failing the read.
+			log.Logger.Warn().Err(err).Int64("productID", id).Msg("Failed to lock product cache")
+		case locked:
+			defer func() {
+				if err := r.cache.Unlock(context.WithoutCancel(ctx), lockKey, token); err != nil {
+					log.Logger.Warn().Err(err).Int64("productID", id).Msg("Failed to unlock product cache")
+				}
+			}()
+		default:
+			if product, cached := r.waitForProductCache(ctx, key); cached {
+				return product, nil
+			}
+		}
+	}
+
+	start := time.Now()
 	var product entity.Product
-	err = r.db.Table("products").WithContext(ctx).Where("id = ?", id).First(&product).Error
+	err := r.db.Table("products").WithContext(ctx).Where("id = ?", id).First(&product).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
+			r.cacheNotFound(ctx, key)
 			return nil, nil
 		}
 		log.Logger.Error().Err(err).Int64("productID", id).Msg("Failed to get product from database")
-		return nil, errors.New("failed to get product from database")
+		return nil, fmt.Errorf("failed to get product from database: %w", err)
 	}
+	r.observeLoadTime(time.Since(start))
 
-	// Cache the product for future requests
-	if err := r.cache.Set(ctx, key, product); err != nil {
-		log.Logger.Error().Err(err).Int64("productID", id).Msg("Failed to set product in cache")
-		return nil, fmt.Errorf("failed to set product in cache: %w", err)
+	// The cache is only an optimisation here, so a failed write does not fail the read.
+	data, err := json.Marshal(&product)
+	if err == nil {
+		err = r.cache.Set(ctx, key, string(data))
+	}
+	if err != nil {
+		log.Logger.Warn().Err(err).Int64("productID", id).Msg("Failed to set product in cache")
 	}
-
 	return &product, nil
 }
 
+// waitForProductCache polls the cache while another instance holds the lock of a product. Once
+// the product is cached it returns the product, or nil if it does not exist, and true; it returns
+// false when the lock TTL passes first.
+func (r *productRepository) waitForProductCache(ctx context.Context, key string) (*entity.Product, bool) {
+	ticker := time.NewTicker(productLockPollInterval)
+	defer ticker.Stop()
+	deadline := time.After(r.cacheOptions.LockTTL)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, false
+		case <-deadline:
+			return nil, false
+		case <-ticker.C:
+			cached, err := r.cache.Get(ctx, key)
+			if err != nil {
+				return nil, false
+			}
+			switch cached {
+			case "":
+				continue
+			case productNotFound:
+				return nil, true
+			}
+			var product entity.Product
+			if err := json.Unmarshal([]byte(cached), &product); err != nil {
+				return nil, false
+			}
+			return &product, true
+		}
+	}
+}
+
+// mayExist reports whether a product may exist according to the ID filter, if there is one.
+func (r *productRepository) mayExist(id int64) bool {
+	return r.cacheOptions.IDFilter == nil || r.cacheOptions.IDFilter.MayExist(id)
+}
+
+// cacheNotFound records that a product does not exist, if negative caching is enabled. It only
+// spares the database, so a failed write is logged.
+func (r *productRepository) cacheNotFound(ctx context.Context, key string) {
+	if r.cacheOptions.NotFoundTTL <= 0 {
+		return
+	}
+	if err := r.cache.SetWithTTL(ctx, key, productNotFound, r.cacheOptions.NotFoundTTL); err != nil {
+		log.Logger.Warn().Err(err).Str("key", key).Msg("Failed to cache missing product")
+	}
+}
+
+// productAdded drops a cached "not found" of a created or restored product and records it in the
+// ID filter.
+func (r *productRepository) productAdded(ctx context.Context, id int64) {
+	if r.cacheOptions.IDFilter != nil {
+		r.cacheOptions.IDFilter.Add(ctx, id)
+	}
+	if err := r.cache.Delete(ctx, fmt.Sprintf("product:%d", id)); err != nil {
+		log.Logger.Error().Err(err).Int64("productID", id).Msg("Failed to invalidate product in cache")
+	}
+}
+
+// refreshEarly decides whether a read of an entry with the given remaining time to live refreshes
+// it, following the XFetch algorithm: the entry is refreshed when the load time, scaled by beta and
+// a random factor, reaches past its expiry. The closer the expiry and the more reads, the likelier
+// one of them refreshes it.
+func (r *productRepository) refreshEarly(ttl time.Duration) bool {
+	if r.cacheOptions.EarlyRefreshBeta <= 0 || ttl <= 0 {
+		return false
+	}
+	loadTime := time.Duration(r.loadTime.Load())
+	if loadTime <= 0 {
+		loadTime = defaultProductLoadTime
+	}
+	gap := float64(loadTime) * r.cacheOptions.EarlyRefreshBeta * -math.Log(1-rand.Float64())
+	return gap >= float64(ttl)
+}
+
+// observeLoadTime adds the duration of a load to the moving average. Concurrent updates may lose
+// one another, which the estimate tolerates.
+func (r *productRepository) observeLoadTime(d time.Duration) {
+	previous := r.loadTime.Load()
+	if previous == 0 {
+		r.loadTime.Store(int64(d))
+		return
+	}
+	r.loadTime.Store(previous - previous/8 + int64(d)/8)
+}
+
+func (r *productRepository) GetProductsByIDs(ctx context.Context, ids []int64) (map[int64]*entity.Product, error) {
+	possible := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		if r.mayExist(id) {
+			possible = append(possible, id)
+		}
+	}
+	ids = possible
+	products := make(map[int64]*entity.Product, len(ids))
+	if len(ids) == 0 {
+		return products, nil
+	}
+
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = fmt.Sprintf("product:%d", id)
+	}
+	cached, err := r.cache.MGet(ctx, keys)
+	if err != nil {
+		log.Logger.Error().Err(err).Int("count", len(ids)).Msg("Failed to get products from cache")
+		return nil, fmt.Errorf("failed to get products from cache: %w", err)
+	}
+
+	var missing []int64
+	for i, id := range ids {
+		if cached[i] == productNotFound {
+			continue
+		}
+		if cached[i] == "" {
+			missing = append(missing, id)
+			continue
+		}
+		var product entity.Product
+		if err := json.Unmarshal([]byte(cached[i]), &product); err != nil {
+			log.Logger.Error().Err(err).Int64("productID", id).Msg("Failed to unmarshal product from cache")
+			missing = append(missing, id)
+			continue
+		}
+		products[id] = &product
+	}
+	if len(missing) == 0 {
+		return products, nil
+	}
+
+	var found []entity.Product
+	err = r.db.Table("products").WithContext(ctx).Where("id IN ?", missing).Find(&found).Error
+	if err != nil {
+		log.Logger.Error().Err(err).Int("count", len(missing)).Msg("Failed to get products from database")
+		return nil, fmt.Errorf("failed to get products from database: %w", err)
+	}
+	for i := range found {
+		product := &found[i]
+		products[product.ID] = product
+
+		// The cache is only an optimisation here, so a failed write does not fail the read.
+		data, err := json.Marshal(product)
+		if err == nil {
+			err = r.cache.Set(ctx, fmt.Sprintf("product:%d", product.ID), string(data))
+		}
+		if err != nil {
+			log.Logger.Warn().Err(err).Int64("productID", product.ID).Msg("Failed to set product in cache")
+		}
+	}
+	return products, nil
+}
+
+func (r *productRepository) AdjustProductStock(ctx context.Context, id int64, delta int) (int, bool, error) {
+	var stock int
+	adjusted := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Table("products").
+			Where("id = ? AND deleted_at IS NULL AND stock + ? >= 0", id, delta).
+			Update("stock", gorm.Expr("stock + ?", delta))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		adjusted = true
+		return tx.Table("products").Select("stock").Where("id = ?", id).Row().Scan(&stock)
+	})
+	if err != nil {
+		log.Logger.Error().Err(err).Int64("productID", id).Int("delta", delta).Msg("Failed to adjust product stock in database")
+		return 0, false, fmt.Errorf("failed to adjust product stock in database: %w", err)
+	}
+	if !adjusted {
+		return 0, false, nil
+	}
+
+	if err := r.cache.Delete(ctx, fmt.Sprintf("product:%d", id)); err != nil {
+		log.Logger.Error().Err(err).Int64("productID", id).Msg("Failed to invalidate product in cache")
+	}
+	return stock, true, nil
+}
+
 // CreateProduct adds a new product to the in-memory store.
 // Parameters:
 //   - product: A pointer to the Product entity to create.
@@ -120,11 +519,17 @@ func (r *productRepository) GetProductByID(ctx context.Context, id int64) (*enti
 //   - A pointer to the created Product entity.
 //   - An error if any issues occur during creation.
 func (r *productRepository) CreateProduct(ctx context.Context, product *entity.Product) error {
-	err := r.db.Table("products").WithContext(ctx).Create(product).Error
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Table("products").Create(product).Error; err != nil {
+			return err
+		}
+		return recordCurrentPrices(tx, []int64{product.ID}, time.Now())
+	})
 	if err != nil {
 		log.Logger.Error().Err(err).Msg("Failed to create product in database")
-		return errors.New("failed to create product in database: %w")
+		return fmt.Errorf("failed to create product in database: %w", translateError(err))
 	}
+	r.productAdded(ctx, product.ID)
 	return nil
 }
 
@@ -136,13 +541,15 @@ func (r *productRepository) CreateProduct(ctx context.Context, product *entity.P
 //   - A pointer to the updated Product entity.
 //   - An error if any issues occur during the update.
 func (r *productRepository) UpdateProduct(ctx context.Context, product *entity.Product) (*entity.Product, error) {
-	err := r.db.Table("products").WithContext(ctx).Save(product).Error
+	// A stale copy must not revert a price applied concurrently, e.g. by a scheduled price change.
+	err := r.db.Table("products").WithContext(ctx).Omit("price_amount", "price_currency").Save(product).Error
 	if err != nil {
 		log.Logger.Error().Err(err).Int64("productID", product.ID).Msg("Failed to update product in database")
-		return nil, errors.New("failed to update product in database")
+		return nil, fmt.Errorf("failed to update product in database: %w", translateError(err))
 	}
 
-	err = r.cache.Set(ctx, fmt.Sprintf("product:%d", product.ID), product)
+	// The copy may hold a stale price, so drop the cached product instead of overwriting it.
+	err = r.cache.Delete(ctx, fmt.Sprintf("product:%d", product.ID))
 	if err != nil {
 		log.Logger.Error().Err(err).Int64("productID", product.ID).Msg("Failed to update product in cache")
 		return nil, fmt.Errorf("failed to update product in cache: %w", err)
@@ -151,7 +558,7 @@ func (r *productRepository) UpdateProduct(ctx context.Context, product *entity.P
 	return product, nil
 }
 
-// DeleteProduct removes a product from the in-memory store by its ID.
+// DeleteProduct soft-deletes a product by its ID.
 // Parameters:
 //   - id: The ID of the product to delete.
 //
@@ -164,7 +571,7 @@ func (r *productRepository) DeleteProduct(ctx context.Context, id int64) error {
 	}
 
 	if product == nil {
-		return fmt.Errorf("product with ID %d not found", id)
+		return fmt.Errorf("%w: product with ID %d", ErrNotFound, id)
 	}
 
 	err = r.db.Table("products").WithContext(ctx).Delete(&entity.Product{}, id).Error
@@ -173,6 +580,7 @@ func (r *productRepository) DeleteProduct(ctx context.Context, id int64) error {
 		return fmt.Errorf("failed to delete product from database: %w", err)
 	}
 
+	// The ID filter lets the product through until its next rebuild.
 	err = r.cache.Delete(ctx, fmt.Sprintf("product:%d", id))
 	if err != nil {
 		log.Logger.Error().Err(err).Int64("productID", id).Msg("Failed to delete product from cache")
@@ -182,12 +590,264 @@ func (r *productRepository) DeleteProduct(ctx context.Context, id int64) error {
 	return nil
 }
 
-func (r *productRepository) GetProducts(ctx context.Context) ([]entity.Product, error) {
+func (r *productRepository) GetProducts(ctx context.Context, filter entity.ProductFilter) ([]entity.Product, error) {
 	var products []entity.Product
-	err := r.db.Table("products").WithContext(ctx).Find(&products).Error
+	query := r.db.Table("products").WithContext(ctx)
+	if filter.CategoryID != 0 {
+		query = query.Where(`category_id IN (
+			WITH RECURSIVE subtree AS (
+				SELECT id FROM categories WHERE id = ?
+				UNION ALL
+				SELECT c.id FROM categories c JOIN subtree s ON c.parent_id = s.id
+			)
+			SELECT id FROM subtree)`, filter.CategoryID)
+	}
+	if filter.Tag != "" {
+		query = query.Where(`id IN (
+			SELECT pt.product_id FROM product_tags pt JOIN tags t ON t.id = pt.tag_id WHERE t.name = ?)`, filter.Tag)
+	}
+	if filter.ActiveOnly {
+		query = query.Where("status = ? AND (unpublish_at IS NULL OR unpublish_at > ?)", entity.ProductStatusActive, time.Now().UTC())
+	}
+
+	err := query.Find(&products).Error
 	if err != nil {
 		log.Logger.Error().Err(err).Msg("Failed to get products from database")
 		return []entity.Product{}, err
 	}
 	return products, nil
 }
+
+func (r *productRepository) UpsertProducts(ctx context.Context, products []entity.Product) ([]entity.Product, error) {
+	skus := make([]string, 0, len(products))
+	for i, product := range products {
+		if product.ExternalSKU == nil {
+			return nil, fmt.Errorf("%w: product %d of the batch", ErrMissingExternalSKU, i)
+		}
+		skus = append(skus, *product.ExternalSKU)
+	}
+
+	var stored []entity.Product
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Table("products").Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "external_sku"}},
+			DoUpdates: clause.Set{
+				{Column: clause.Column{Name: "name"}, Value: gorm.Expr("VALUES(name)")},
+				{Column: clause.Column{Name: "description"}, Value: gorm.Expr("VALUES(description)")},
+				{Column: clause.Column{Name: "price_amount"}, Value: gorm.Expr("VALUES(price_amount)")},
+				{Column: clause.Column{Name: "price_currency"}, Value: gorm.Expr("VALUES(price_currency)")},
+				{Column: clause.Column{Name: "category_id"}, Value: gorm.Expr("VALUES(category_id)")},
+				{Column: clause.Column{Name: "stock"}, Value: gorm.Expr(
+					"IF(EXISTS(SELECT 1 FROM product_variants WHERE product_id = products.id), products.stock, VALUES(stock))")},
+			},
+		}).Create(&products).Error
+		if err != nil {
+			return err
+		}
+
+		// IDs assigned by Create are unreliable for updated rows, so read the batch back.
+		// Soft-deleted products are updated too but stay deleted until restored.
+		if err := tx.Table("products").Unscoped().Where("external_sku IN ?", skus).Find(&stored).Error; err != nil {
+			return err
+		}
+
+		ids := make([]int64, 0, len(stored))
+		for _, product := range stored {
+			ids = append(ids, product.ID)
+		}
+		return recordCurrentPrices(tx, ids, time.Now())
+	})
+	if err != nil {
+		log.Logger.Error().Err(err).Int("products", len(products)).Msg("Failed to upsert products in database")
+		return nil, fmt.Errorf("failed to upsert products in database: %w", translateError(err))
+	}
+
+	for _, product := range stored {
+		if product.DeletedAt.Valid {
+			if err := r.cache.Delete(ctx, fmt.Sprintf("product:%d", product.ID)); err != nil {
+				log.Logger.Error().Err(err).Int64("productID", product.ID).Msg("Failed to invalidate product in cache")
+			}
+			continue
+		}
+		r.productAdded(ctx, product.ID)
+	}
+	return stored, nil
+}
+
+func (r *productRepository) StreamProducts(ctx context.Context, batchSize int, fn func(snapshotAt time.Time, batch []entity.Product) error) (time.Time, error) {
+	var snapshotAt time.Time
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// InnoDB fixes the read view at the first consistent read of a REPEATABLE READ transaction,
+		// so the snapshot time is taken just before the first page is read.
+		snapshotAt = time.Now().UTC()
+
+		var lastID int64
+		for {
+			var batch []entity.Product
+			err := tx.Table("products").
+				Where("id > ?", lastID).
+				Order("id ASC").
+				Limit(batchSize).
+				Find(&batch).Error
+			if err != nil {
+				return err
+			}
+			if len(batch) == 0 {
+				return nil
+			}
+
+			if err := fn(snapshotAt, batch); err != nil {
+				return err
+			}
+			if len(batch) < batchSize {
+				return nil
+			}
+			lastID = batch[len(batch)-1].ID
+		}
+	}, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		log.Logger.Error().Err(err).Msg("Failed to stream products from database")
+		return snapshotAt, fmt.Errorf("failed to stream products from database: %w", err)
+	}
+	return snapshotAt, nil
+}
+
+func (r *productRepository) RestoreProduct(ctx context.Context, id int64) (*entity.Product, error) {
+	result := r.db.Table("products").WithContext(ctx).Unscoped().
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Update("deleted_at", nil)
+	if result.Error != nil {
+		log.Logger.Error().Err(result.Error).Int64("productID", id).Msg("Failed to restore product in database")
+		return nil, fmt.Errorf("failed to restore product in database: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	r.productAdded(ctx, id)
+	return r.GetProductByID(ctx, id)
+}
+
+func (r *productRepository) ArchiveDeletedProducts(ctx context.Context, before time.Time, limit int) ([]int64, []entity.ProductMedia, error) {
+	var archived []int64
+	var media []entity.ProductMedia
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// SKIP LOCKED lets several instances run the purge job without blocking each other.
+		var ids []int64
+		err := tx.Table("products").Unscoped().
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", before).
+			Order("id ASC").
+			Limit(limit).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Pluck("id", &ids).Error
+		if err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		// Deleting a product cascades to its variants, media, tags and currency prices, so they are
+		// archived with it.
+		err = tx.Exec(`INSERT INTO products_archive
+			(id, external_sku, name, description, price_amount, price_currency, stock, category_id, status, deleted_at, related, archived_at)
+			SELECT p.id, p.external_sku, p.name, p.description, p.price_amount, p.price_currency, p.stock, p.category_id, p.status, p.deleted_at,
+				JSON_OBJECT(
+					'variants', (SELECT JSON_ARRAYAGG(JSON_OBJECT('id', v.id, 'sku', v.sku, 'attributes', v.attributes,
+						'price_amount', v.price_amount, 'price_currency', v.price_currency, 'stock', v.stock))
+						FROM product_variants v WHERE v.product_id = p.id),
+					'media', (SELECT JSON_ARRAYAGG(JSON_OBJECT('id', m.id, 'url', m.url, 'storage_key', m.storage_key,
+						'content_type', m.content_type, 'size', m.size, 'position', m.position))
+						FROM product_media m WHERE m.product_id = p.id),
+					'tags', (SELECT JSON_ARRAYAGG(t.name)
+						FROM product_tags pt JOIN tags t ON t.id = pt.tag_id WHERE pt.product_id = p.id),
+					'currency_prices', (SELECT JSON_ARRAYAGG(JSON_OBJECT('price_amount', c.price_amount, 'price_currency', c.price_currency))
+						FROM product_currency_prices c WHERE c.product_id = p.id)),
+				?
+			FROM products p WHERE p.id IN ?`, time.Now().UTC(), ids).Error
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Table("product_media").Where("product_id IN ?", ids).Find(&media).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM products WHERE id IN ?", ids).Error; err != nil {
+			return err
+		}
+		archived = ids
+		return nil
+	})
+	if err != nil {
+		log.Logger.Error().Err(err).Time("before", before).Msg("Failed to archive deleted products")
+		return nil, nil, fmt.Errorf("failed to archive deleted products: %w", err)
+	}
+
+	// The ID filter forgot these products when they were soft-deleted, so only cached copies, such
+	// as a cached "not found", are left to drop.
+	for _, id := range archived {
+		if err := r.cache.Delete(ctx, fmt.Sprintf("product:%d", id)); err != nil {
+			log.Logger.Error().Err(err).Int64("productID", id).Msg("Failed to invalidate product in cache")
+		}
+	}
+	return archived, media, nil
+}
+
+func (r *productRepository) UpdateProductStatus(ctx context.Context, id int64, from entity.ProductStatus, change entity.ProductStatusChange) (*entity.Product, error) {
+	result := r.db.Table("products").WithContext(ctx).
+		Where("id = ? AND status = ? AND deleted_at IS NULL", id, from).
+		Updates(map[string]interface{}{
+			"status":       change.Status,
+			"publish_at":   change.PublishAt,
+			"unpublish_at": change.UnpublishAt,
+		})
+	if result.Error != nil {
+		log.Logger.Error().Err(result.Error).Int64("productID", id).Msg("Failed to update product status in database")
+		return nil, fmt.Errorf("failed to update product status in database: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	if err := r.cache.Delete(ctx, fmt.Sprintf("product:%d", id)); err != nil {
+		log.Logger.Error().Err(err).Int64("productID", id).Msg("Failed to invalidate product in cache")
+	}
+	return r.GetProductByID(ctx, id)
+}
+
+func (r *productRepository) PublishDueProducts(ctx context.Context, now time.Time) ([]int64, error) {
+	return r.transitionDueProducts(ctx, entity.ProductStatusScheduled, entity.ProductStatusActive, "publish_at", now)
+}
+
+func (r *productRepository) UnpublishDueProducts(ctx context.Context, now time.Time) ([]int64, error) {
+	return r.transitionDueProducts(ctx, entity.ProductStatusActive, entity.ProductStatusArchived, "unpublish_at", now)
+}
+
+// transitionDueProducts moves every product in status from whose timeColumn has passed to status to.
+func (r *productRepository) transitionDueProducts(ctx context.Context, from, to entity.ProductStatus, timeColumn string, now time.Time) ([]int64, error) {
+	var ids []int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Table("products").
+			Where("status = ? AND deleted_at IS NULL AND "+timeColumn+" <= ?", from, now).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Pluck("id", &ids).Error
+		if err != nil || len(ids) == 0 {
+			return err
+		}
+
+		return tx.Table("products").
+			Where("id IN ? AND status = ?", ids, from).
+			Update("status", to).Error
+	})
+	if err != nil {
+		log.Logger.Error().Err(err).Str("from", string(from)).Str("to", string(to)).Msg("Failed to apply product schedules")
+		return nil, fmt.Errorf("failed to apply product schedules: %w", err)
+	}
+
+	for _, id := range ids {
+		if err := r.cache.Delete(ctx, fmt.Sprintf("product:%d", id)); err != nil {
+			log.Logger.Error().Err(err).Int64("productID", id).Msg("Failed to invalidate product in cache")
+		}
+	}
+	return ids, nil
+}
@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"product-catalog-service/infrastructure/log"
+	"product-catalog-service/pkg/bloom"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"gorm.io/gorm"
+)
+
+// ProductIDFilter is an in-memory Bloom filter of the IDs of existing products, which rejects IDs
+// that cannot exist before they reach the cache or the database. It never rejects an existing
+// product: IDs above the highest ID of the last snapshot pass, so products created by any instance
+// or by the import command are found before the filter learns about them. Deleted products pass
+// until the next rebuild: the filter cannot tell whether it recorded an ID, for example when its
+// addition was lost, and removing one it did not record could hide other products.
+type ProductIDFilter interface {
+	// MayExist reports whether a product may exist. False means it certainly does not.
+	MayExist(id int64) bool
+
+	// Add records a created or restored product on every instance.
+	// Parameters:
+	//   - id: The ID of the product.
+	Add(ctx context.Context, id int64)
+
+	// Start builds the filter, applies the changes published by other instances and rebuilds the
+	// filter on every interval until ctx is done. Until the filter is built, every ID may exist.
+	Start(ctx context.Context, interval time.Duration)
+}
+
+// productIDChange is an addition to the filter fanned out to every instance.
+type productIDChange struct {
+	Origin string `json:"origin"`
+	ID     int64  `json:"id"`
+}
+
+type productIDFilter struct {
+	rdb               *redis.Client
+	db                *gorm.DB
+	channel           string
+	capacity          int
+	falsePositiveRate float64
+	// origin identifies this instance, which skips its own changes when they come back.
+	origin string
+
+	mu        sync.RWMutex
+	filter    *bloom.Counting
+	highWater int64
+	// rebuilding collects the products added while a rebuild reads its snapshot, which may be too
+	// early to include them; they are added to the new filter again.
+	rebuilding bool
+	addedSince []int64
+}
+
+// NewProductIDFilter creates and returns a new instance of productIDFilter, sized for capacity
+// products, or more if the catalogue is larger, with the given false positive rate. Changes are
+// fanned out on the Redis channel.
+func NewProductIDFilter(rdb *redis.Client, db *gorm.DB, channel string, capacity int, falsePositiveRate float64) ProductIDFilter {
+	var b [8]byte
+	_, _ = rand.Read(b[:])
+	return &productIDFilter{
+		rdb:               rdb,
+		db:                db,
+		channel:           channel,
+		capacity:          capacity,
+		falsePositiveRate: falsePositiveRate,
+		origin:            hex.EncodeToString(b[:]),
+	}
+}
+
+func (f *productIDFilter) MayExist(id int64) bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.filter == nil || id > f.highWater || f.filter.Test(id)
+}
+
+func (f *productIDFilter) Add(ctx context.Context, id int64) {
+	f.apply(productIDChange{ID: id})
+	f.publish(ctx, productIDChange{Origin: f.origin, ID: id})
+}
+
+// apply changes the filter of this instance.
+func (f *productIDFilter) apply(change productIDChange) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.filter == nil {
+		return
+	}
+	f.filter.Add(change.ID)
+	if f.rebuilding {
+		f.addedSince = append(f.addedSince, change.ID)
+	}
+}
+
+// publish fans a change out to the other instances. A lost change is repaired by the next rebuild;
+// until then a lost restore hides the product.
+func (f *productIDFilter) publish(ctx context.Context, change productIDChange) {
+	payload, err := json.Marshal(change)
+	if err == nil {
+		err = f.rdb.Publish(ctx, f.channel, payload).Err()
+	}
+	if err != nil {
+		log.Logger.Warn().Err(err).Int64("productID", change.ID).Msg("Failed to publish product ID filter change")
+	}
+}
+
+func (f *productIDFilter) Start(ctx context.Context, interval time.Duration) {
+	// Changes published while the filter is built are applied to the old filter, or dropped before
+	// the first build, and are covered by the snapshot or the collected additions.
+	go f.subscribe(ctx)
+	if err := f.rebuild(ctx); err != nil {
+		log.Logger.Error().Err(err).Msg("Failed to build product ID filter")
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := f.rebuild(ctx); err != nil {
+				log.Logger.Error().Err(err).Msg("Failed to rebuild product ID filter")
+			}
+		}
+	}
+}
+
+// subscribe applies the changes published by other instances until ctx is done.
+func (f *productIDFilter) subscribe(ctx context.Context) {
+	pubsub := f.rdb.Subscribe(ctx, f.channel)
+	defer pubsub.Close()
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-messages:
+			if !ok {
+				return
+			}
+			var change productIDChange
+			if err := json.Unmarshal([]byte(msg.Payload), &change); err != nil {
+				log.Logger.Error().Err(err).Str("channel", f.channel).Msg("Failed to unmarshal product ID filter change")
+				continue
+			}
+			if change.Origin != f.origin {
+				f.apply(change)
+			}
+		}
+	}
+}
+
+// rebuild replaces the filter with one built from a snapshot of the products. Rebuilding sheds the
+// deleted products and the changes applied twice, which can only cause false positives.
+func (f *productIDFilter) rebuild(ctx context.Context) error {
+	f.mu.Lock()
+	f.rebuilding = true
+	f.addedSince = nil
+	f.mu.Unlock()
+	defer func() {
+		f.mu.Lock()
+		f.rebuilding = false
+		f.addedSince = nil
+		f.mu.Unlock()
+	}()
+
+	var ids []int64
+	var highWater int64
+	err := f.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Soft-deleted products count towards the high water mark, since their IDs are taken.
+		if err := tx.Table("products").Select("COALESCE(MAX(id), 0)").Scan(&highWater).Error; err != nil {
+			return err
+		}
+		return tx.Table("products").Where("deleted_at IS NULL").Pluck("id", &ids).Error
+	}, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return fmt.Errorf("failed to read product IDs from database: %w", err)
+	}
+
+	filter := bloom.NewCounting(max(f.capacity, len(ids)), f.falsePositiveRate)
+	for _, id := range ids {
+		filter.Add(id)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, id := range f.addedSince {
+		filter.Add(id)
+	}
+	f.filter = filter
+	f.highWater = highWater
+	log.Logger.Info().Int("products", len(ids)).Int64("highWater", highWater).Msg("Product ID filter built")
+	return nil
+}
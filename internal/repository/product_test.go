@@ -0,0 +1,233 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"product-catalog-service/internal/entity"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// productDB is a database/sql driver that answers every query with one product, and counts the
+// queries. While gate is open, queries wait for it to be closed.
+type productDB struct {
+	queries atomic.Int64
+	gate    chan struct{}
+}
+
+func (d *productDB) Connect(context.Context) (driver.Conn, error) { return productConn{d}, nil }
+func (d *productDB) Driver() driver.Driver                        { return productDriver{d} }
+
+type productDriver struct{ db *productDB }
+
+func (d productDriver) Open(string) (driver.Conn, error) { return productConn(d), nil }
+
+type productConn struct{ db *productDB }
+
+func (c productConn) Prepare(string) (driver.Stmt, error) { return productStmt(c), nil }
+func (c productConn) Close() error                        { return nil }
+func (c productConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("transactions are not supported")
+}
+
+type productStmt struct{ db *productDB }
+
+func (s productStmt) Close() error  { return nil }
+func (s productStmt) NumInput() int { return -1 }
+func (s productStmt) Exec([]driver.Value) (driver.Result, error) {
+	return nil, errors.New("writes are not supported")
+}
+func (s productStmt) Query([]driver.Value) (driver.Rows, error) {
+	s.db.queries.Add(1)
+	if s.db.gate != nil {
+		<-s.db.gate
+	}
+	return &productRows{}, nil
+}
+
+type productRows struct{ done bool }
+
+func (r *productRows) Columns() []string {
+	return []string{"id", "name", "price_amount", "price_currency", "stock"}
+}
+func (r *productRows) Close() error { return nil }
+func (r *productRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0], dest[1], dest[2], dest[3], dest[4] = int64(1), "Keyboard", int64(4500), "USD", int64(5)
+	return nil
+}
+
+// newTestProductRepository returns a product repository reading from db and cache.
+func newTestProductRepository(t *testing.T, db *productDB, cache CacheRepository, options ProductCacheOptions) *productRepository {
+	t.Helper()
+	gormDB, err := gorm.Open(mysql.New(mysql.Config{Conn: sql.OpenDB(db), SkipInitializeWithVersion: true}),
+		&gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("gorm.Open: %v", err)
+	}
+	return NewProductRepository(cache, gormDB, options).(*productRepository)
+}
+
+// waitFor polls until cond holds, and fails the test if it does not within a second.
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// readProduct reads product 1 through repo in the background, and sends the product read or the
+// reason the read failed.
+func readProduct(repo *productRepository) <-chan productRead {
+	reads := make(chan productRead, 1)
+	go func() {
+		product, err := repo.GetProductByID(context.Background(), 1)
+		if err == nil && (product == nil || product.Name != "Keyboard") {
+			err = errors.New("product 1 not found")
+		}
+		reads <- productRead{product, err}
+	}()
+	return reads
+}
+
+type productRead struct {
+	product *entity.Product
+	err     error
+}
+
+// observedCache counts the reads of keys and the locks that were already held when taken.
+type observedCache struct {
+	*fakeCache
+	reads     atomic.Int64
+	contended atomic.Int64
+}
+
+func (c *observedCache) GetWithTTL(ctx context.Context, key string) (string, time.Duration, error) {
+	c.reads.Add(1)
+	return c.fakeCache.GetWithTTL(ctx, key)
+}
+
+func (c *observedCache) Lock(ctx context.Context, key string, ttl time.Duration) (string, bool, error) {
+	token, locked, err := c.fakeCache.Lock(ctx, key, ttl)
+	if err == nil && !locked {
+		c.contended.Add(1)
+	}
+	return token, locked, err
+}
+
+func TestGetProductByIDLoadsConcurrentMissesOnce(t *testing.T) {
+	db := &productDB{gate: make(chan struct{})}
+	cache := &observedCache{fakeCache: newFakeCache()}
+	repo := newTestProductRepository(t, db, cache, ProductCacheOptions{})
+
+	var reads []<-chan productRead
+	for i := 0; i < 50; i++ {
+		reads = append(reads, readProduct(repo))
+	}
+	// A reader that misses the cache joins the load in flight right after, unless the load ends in
+	// between, which the gate prevents.
+	waitFor(t, "every reader to miss the cache", func() bool { return cache.reads.Load() == 50 })
+	time.Sleep(10 * time.Millisecond)
+	close(db.gate)
+
+	// Every reader gets its own copy, since callers may change the product they are given.
+	seen := make(map[*entity.Product]bool)
+	for _, read := range reads {
+		result := <-read
+		if result.err != nil {
+			t.Fatalf("GetProductByID: %v", result.err)
+		}
+		if seen[result.product] {
+			t.Fatal("two readers got the same product")
+		}
+		seen[result.product] = true
+	}
+	if queries := db.queries.Load(); queries != 1 {
+		t.Errorf("database queried %d times, want 1", queries)
+	}
+	if cached, _ := cache.Get(context.Background(), "product:1"); cached == "" {
+		t.Error("product 1 is not cached")
+	}
+}
+
+func TestGetProductByIDInstancesWaitForLockHolder(t *testing.T) {
+	db := &productDB{gate: make(chan struct{})}
+	cache := &observedCache{fakeCache: newFakeCache()}
+	options := ProductCacheOptions{LockTTL: time.Second}
+	// Each repository stands for an instance, which coalesces its own loads only.
+	holder := newTestProductRepository(t, db, cache, options)
+	waiter := newTestProductRepository(t, db, cache, options)
+
+	holderRead := readProduct(holder)
+	waitFor(t, "the first query", func() bool { return db.queries.Load() > 0 })
+	waiterRead := readProduct(waiter)
+	waitFor(t, "the waiting instance", func() bool { return cache.contended.Load() > 0 })
+	close(db.gate)
+
+	for _, read := range []<-chan productRead{holderRead, waiterRead} {
+		if result := <-read; result.err != nil {
+			t.Fatalf("GetProductByID: %v", result.err)
+		}
+	}
+	if queries := db.queries.Load(); queries != 1 {
+		t.Errorf("database queried %d times, want 1", queries)
+	}
+	if locked, _ := cache.Get(context.Background(), "lock:product:1"); locked != "" {
+		t.Error("lock of product 1 was not released")
+	}
+}
+
+func TestGetProductByIDRefreshesEarly(t *testing.T) {
+	ctx := context.Background()
+	for _, tc := range []struct {
+		name        string
+		beta        float64
+		ttl         time.Duration
+		wantQueries int64
+	}{
+		{"near expiry", 1000, 100 * time.Millisecond, 1},
+		{"far from expiry", 1, time.Hour, 0},
+		{"disabled", 0, 100 * time.Millisecond, 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			db := &productDB{}
+			cache := newFakeCache()
+			repo := newTestProductRepository(t, db, cache, ProductCacheOptions{EarlyRefreshBeta: tc.beta})
+			// Scaled loads take far longer than the remaining time to live near expiry, so a refresh
+			// is all but certain there, and unscaled ones never reach an hour ahead.
+			repo.loadTime.Store(int64(time.Second))
+			_ = cache.SetWithTTL(ctx, "product:1", `{"id":1,"name":"Cached"}`, tc.ttl)
+
+			product, err := repo.GetProductByID(ctx, 1)
+			if err != nil || product == nil || product.Name != "Cached" {
+				t.Fatalf("GetProductByID = %v, %v, want the cached product", product, err)
+			}
+			if tc.wantQueries > 0 {
+				waitFor(t, "the refresh", func() bool {
+					cached, ttl, _ := cache.GetWithTTL(ctx, "product:1")
+					return ttl > time.Minute && cached != `{"id":1,"name":"Cached"}`
+				})
+			} else {
+				time.Sleep(10 * time.Millisecond)
+			}
+			if queries := db.queries.Load(); queries != tc.wantQueries {
+				t.Errorf("database queried %d times, want %d", queries, tc.wantQueries)
+			}
+		})
+	}
+}
@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"github.com/go-redis/redis/v8"
 	"time"
@@ -9,8 +11,20 @@ import (
 
 type CacheRepository interface {
 	Set(ctx context.Context, key string, value interface{}) error
+	// SetWithTTL writes a key that expires after ttl instead of the default time to live.
+	SetWithTTL(ctx context.Context, key string, value interface{}, ttl time.Duration) error
 	Get(ctx context.Context, key string) (string, error)
+	// GetWithTTL reads a key together with its remaining time to live, which is not positive for a
+	// key without expiry.
+	GetWithTTL(ctx context.Context, key string) (string, time.Duration, error)
+	MGet(ctx context.Context, keys []string) ([]string, error)
 	Delete(ctx context.Context, key string) error
+	// Lock takes the lock key for at most ttl unless another holder has it, and returns the token
+	// that releases it.
+	Lock(ctx context.Context, key string, ttl time.Duration) (string, bool, error)
+	// Unlock releases a lock taken with token. A lock that expired and was taken by another holder
+	// is left alone.
+	Unlock(ctx context.Context, key, token string) error
 }
 
 type cacheRepository struct {
@@ -31,6 +45,10 @@ func (r *cacheRepository) Set(ctx context.Context, key string, value interface{}
 	return nil
 }
 
+func (r *cacheRepository) SetWithTTL(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	return r.rdb.Set(ctx, key, value, ttl).Err()
+}
+
 func (r *cacheRepository) Get(ctx context.Context, key string) (string, error) {
 	value, err := r.rdb.Get(ctx, key).Result()
 	if err != nil {
@@ -42,6 +60,44 @@ func (r *cacheRepository) Get(ctx context.Context, key string) (string, error) {
 	return value, nil
 }
 
+func (r *cacheRepository) GetWithTTL(ctx context.Context, key string) (string, time.Duration, error) {
+	pipe := r.rdb.Pipeline()
+	get := pipe.Get(ctx, key)
+	pttl := pipe.PTTL(ctx, key)
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return "", 0, err
+	}
+
+	value, err := get.Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return "", 0, nil
+		}
+		return "", 0, err
+	}
+	return value, pttl.Val(), nil
+}
+
+// MGet reads several keys in one round trip. The values are in the order of keys, with an empty
+// string for each missing key.
+func (r *cacheRepository) MGet(ctx context.Context, keys []string) ([]string, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	results, err := r.rdb.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]string, len(results))
+	for i, result := range results {
+		if value, ok := result.(string); ok {
+			values[i] = value
+		}
+	}
+	return values, nil
+}
+
 func (r *cacheRepository) Delete(ctx context.Context, key string) error {
 	err := r.rdb.Del(ctx, key).Err()
 	if err != nil {
@@ -49,3 +105,27 @@ func (r *cacheRepository) Delete(ctx context.Context, key string) error {
 	}
 	return nil
 }
+
+func (r *cacheRepository) Lock(ctx context.Context, key string, ttl time.Duration) (string, bool, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", false, err
+	}
+	token := hex.EncodeToString(b[:])
+	locked, err := r.rdb.SetNX(ctx, key, token, ttl).Result()
+	if err != nil {
+		return "", false, err
+	}
+	return token, locked, nil
+}
+
+// unlockScript deletes a lock only while it still holds the token of the caller.
+var unlockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+func (r *cacheRepository) Unlock(ctx context.Context, key, token string) error {
+	return unlockScript.Run(ctx, r.rdb, []string{key}, token).Err()
+}
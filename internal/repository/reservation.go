@@ -0,0 +1,307 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"product-catalog-service/infrastructure/log"
+	"product-catalog-service/internal/entity"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ReservationRepository defines the interface for cart reservation operations.
+type ReservationRepository interface {
+	// ReserveCart takes the stock of every line of a reservation and records the reservation in a
+	// single transaction. The stock rows are locked first, so either every line is reserved or none is.
+	// Parameters:
+	//   - reservation: The reservation to record, with its ID and lines set.
+	// Returns:
+	//   - The availability of every line, in the order of the lines.
+	//   - Whether the reservation was recorded; false when any line lacks stock, in which case no stock is taken.
+	//   - An error if any issues occur; no stock is taken in that case.
+	ReserveCart(ctx context.Context, reservation *entity.CartReservation) ([]entity.LineAvailability, bool, error)
+
+	// GetReservation retrieves a reservation and its lines.
+	// Parameters:
+	//   - id: The ID of the reservation.
+	// Returns:
+	//   - A pointer to the CartReservation entity if found, or nil if not found.
+	//   - An error if any issues occur during retrieval.
+	GetReservation(ctx context.Context, id string) (*entity.CartReservation, error)
+
+	// ReleaseReservation returns the stock of every line of a reservation and marks it released.
+	// Lines of products deleted since the reservation are skipped.
+	// Parameters:
+	//   - id: The ID of the reservation.
+	// Returns:
+	//   - The reservation as stored after the call, or nil if not found.
+	//   - Whether the stock was returned by this call; false when the reservation is no longer reserved.
+	//   - An error if any issues occur; no stock is returned in that case.
+	ReleaseReservation(ctx context.Context, id string) (*entity.CartReservation, bool, error)
+
+	// ConfirmReservation marks a reserved reservation confirmed, after which its stock is no longer returned.
+	// Parameters:
+	//   - id: The ID of the reservation.
+	// Returns:
+	//   - The reservation as stored after the call, or nil if not found. Its status tells whether
+	//     it was confirmed, by this or an earlier call, or had been released before.
+	//   - An error if any issues occur during the update.
+	ConfirmReservation(ctx context.Context, id string) (*entity.CartReservation, error)
+}
+
+type reservationRepository struct {
+	cache CacheRepository
+	db    *gorm.DB
+}
+
+// NewReservationRepository creates a new instance of reservationRepository.
+func NewReservationRepository(cacheRepo CacheRepository, db *gorm.DB) ReservationRepository {
+	return &reservationRepository{
+		cache: cacheRepo,
+		db:    db,
+	}
+}
+
+type lockedStock struct {
+	ID        int64
+	ProductID int64
+	Stock     int
+}
+
+func (r *reservationRepository) ReserveCart(ctx context.Context, reservation *entity.CartReservation) ([]entity.LineAvailability, bool, error) {
+	var availability []entity.LineAvailability
+	reserved := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var productIDs, variantIDs []int64
+		for _, line := range reservation.Lines {
+			if line.VariantID != 0 {
+				variantIDs = append(variantIDs, line.VariantID)
+			} else {
+				productIDs = append(productIDs, line.ProductID)
+			}
+		}
+
+		// Rows are locked in ID order, variants before products like single variant reservations,
+		// so that concurrent carts cannot deadlock each other.
+		variants := make(map[int64]lockedStock)
+		if len(variantIDs) > 0 {
+			var rows []lockedStock
+			err := tx.Table("product_variants").
+				Select("id, product_id, stock").
+				Where("id IN ?", variantIDs).
+				Order("id ASC").
+				Clauses(clause.Locking{Strength: "UPDATE"}).
+				Find(&rows).Error
+			if err != nil {
+				return err
+			}
+			for _, row := range rows {
+				variants[row.ID] = row
+			}
+		}
+		products := make(map[int64]lockedStock)
+		if len(productIDs) > 0 {
+			var rows []lockedStock
+			err := tx.Table("products").
+				Select("id, id AS product_id, stock").
+				Where("id IN ? AND deleted_at IS NULL", productIDs).
+				Order("id ASC").
+				Clauses(clause.Locking{Strength: "UPDATE"}).
+				Find(&rows).Error
+			if err != nil {
+				return err
+			}
+			for _, row := range rows {
+				products[row.ID] = row
+			}
+		}
+
+		complete := true
+		availability = make([]entity.LineAvailability, 0, len(reservation.Lines))
+		for _, line := range reservation.Lines {
+			line := entity.LineAvailability{ProductID: line.ProductID, VariantID: line.VariantID, Requested: line.Quantity}
+			var row lockedStock
+			var ok bool
+			if line.VariantID != 0 {
+				row, ok = variants[line.VariantID]
+				if !ok || row.ProductID != line.ProductID {
+					line.Reason = entity.LineVariantNotFound
+				}
+			} else if row, ok = products[line.ProductID]; !ok {
+				line.Reason = entity.LineProductNotFound
+			}
+			if line.Reason == "" {
+				line.Available = row.Stock
+				if row.Stock < line.Requested {
+					line.Reason = entity.LineInsufficientStock
+				}
+			}
+			if line.Reason != "" {
+				complete = false
+			}
+			availability = append(availability, line)
+		}
+		if !complete {
+			return nil
+		}
+
+		synced := make(map[int64]bool)
+		for _, line := range reservation.Lines {
+			var err error
+			if line.VariantID != 0 {
+				err = tx.Table("product_variants").
+					Where("id = ?", line.VariantID).
+					Update("stock", gorm.Expr("stock - ?", line.Quantity)).Error
+			} else {
+				err = tx.Table("products").
+					Where("id = ?", line.ProductID).
+					Update("stock", gorm.Expr("stock - ?", line.Quantity)).Error
+			}
+			if err != nil {
+				return err
+			}
+		}
+		for _, line := range reservation.Lines {
+			if line.VariantID != 0 && !synced[line.ProductID] {
+				synced[line.ProductID] = true
+				if err := syncProductStock(tx, line.ProductID); err != nil {
+					return err
+				}
+			}
+		}
+
+		if err := tx.Table("stock_reservations").Create(reservation).Error; err != nil {
+			return err
+		}
+		for i := range reservation.Lines {
+			reservation.Lines[i].ReservationID = reservation.ID
+		}
+		if err := tx.Table("stock_reservation_lines").Create(&reservation.Lines).Error; err != nil {
+			return err
+		}
+
+		reserved = true
+		return nil
+	})
+	if err != nil {
+		log.Logger.Error().Err(err).Str("reservationID", reservation.ID).Msg("Failed to reserve cart in database")
+		return nil, false, fmt.Errorf("failed to reserve cart in database: %w", translateError(err))
+	}
+
+	if reserved {
+		r.invalidateProducts(ctx, reservation.Lines)
+	}
+	return availability, reserved, nil
+}
+
+func (r *reservationRepository) GetReservation(ctx context.Context, id string) (*entity.CartReservation, error) {
+	reservation, err := r.getReservation(r.db.WithContext(ctx), id)
+	if err != nil {
+		log.Logger.Error().Err(err).Str("reservationID", id).Msg("Failed to get reservation from database")
+		return nil, fmt.Errorf("failed to get reservation from database: %w", err)
+	}
+	return reservation, nil
+}
+
+func (r *reservationRepository) ReleaseReservation(ctx context.Context, id string) (*entity.CartReservation, bool, error) {
+	var reservation *entity.CartReservation
+	released := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		result := tx.Table("stock_reservations").
+			Where("id = ? AND status = ?", id, entity.ReservationReserved).
+			Updates(map[string]interface{}{"status": entity.ReservationReleased, "released_at": now})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		var err error
+		reservation, err = r.getReservation(tx, id)
+		if err != nil || reservation == nil || result.RowsAffected == 0 {
+			return err
+		}
+
+		synced := make(map[int64]bool)
+		for _, line := range reservation.Lines {
+			if line.VariantID != 0 {
+				err = tx.Table("product_variants").
+					Where("id = ?", line.VariantID).
+					Update("stock", gorm.Expr("stock + ?", line.Quantity)).Error
+				if err == nil && !synced[line.ProductID] {
+					synced[line.ProductID] = true
+					err = syncProductStock(tx, line.ProductID)
+				}
+			} else {
+				err = tx.Table("products").
+					Where("id = ? AND deleted_at IS NULL", line.ProductID).
+					Update("stock", gorm.Expr("stock + ?", line.Quantity)).Error
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		released = true
+		return nil
+	})
+	if err != nil {
+		log.Logger.Error().Err(err).Str("reservationID", id).Msg("Failed to release reservation in database")
+		return nil, false, fmt.Errorf("failed to release reservation in database: %w", err)
+	}
+
+	if released {
+		r.invalidateProducts(ctx, reservation.Lines)
+	}
+	return reservation, released, nil
+}
+
+func (r *reservationRepository) ConfirmReservation(ctx context.Context, id string) (*entity.CartReservation, error) {
+	db := r.db.WithContext(ctx)
+	err := db.Table("stock_reservations").
+		Where("id = ? AND status = ?", id, entity.ReservationReserved).
+		Updates(map[string]interface{}{"status": entity.ReservationConfirmed, "confirmed_at": time.Now()}).Error
+	if err != nil {
+		log.Logger.Error().Err(err).Str("reservationID", id).Msg("Failed to confirm reservation in database")
+		return nil, fmt.Errorf("failed to confirm reservation in database: %w", err)
+	}
+
+	reservation, err := r.getReservation(db, id)
+	if err != nil {
+		log.Logger.Error().Err(err).Str("reservationID", id).Msg("Failed to get reservation from database")
+		return nil, fmt.Errorf("failed to get reservation from database: %w", err)
+	}
+	return reservation, nil
+}
+
+// getReservation reads a reservation and its lines with db, which may be a transaction.
+func (r *reservationRepository) getReservation(db *gorm.DB, id string) (*entity.CartReservation, error) {
+	var reservation entity.CartReservation
+	err := db.Table("stock_reservations").Where("id = ?", id).First(&reservation).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Table("stock_reservation_lines").
+		Where("reservation_id = ?", id).
+		Order("product_id ASC, variant_id ASC").
+		Find(&reservation.Lines).Error
+	if err != nil {
+		return nil, err
+	}
+	return &reservation, nil
+}
+
+// invalidateProducts drops the cached products of the lines so their new stock is read on the next request.
+func (r *reservationRepository) invalidateProducts(ctx context.Context, lines []entity.ReservationLine) {
+	for _, line := range lines {
+		if err := r.cache.Delete(ctx, fmt.Sprintf("product:%d", line.ProductID)); err != nil {
+			log.Logger.Error().Err(err).Int64("productID", line.ProductID).Msg("Failed to invalidate product in cache")
+		}
+	}
+}
@@ -0,0 +1,245 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"product-catalog-service/infrastructure/log"
+	"product-catalog-service/internal/entity"
+	"strings"
+	"time"
+	"unicode"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// maxSearchTermLength is the longest word kept in the search_terms vocabulary.
+	maxSearchTermLength = 64
+	// maxTypoCandidates bounds the vocabulary words compared with one misspelled query term.
+	maxTypoCandidates = 5000
+)
+
+// SearchRepository defines the interface for full-text product search backends.
+type SearchRepository interface {
+	// Search finds active products whose name or description match the query.
+	// Parameters:
+	//   - query: The free-text query entered by the user.
+	//   - limit: The maximum number of results to return.
+	// Returns:
+	//   - The matching products ordered by descending relevance.
+	//   - An error if any issues occur during the search.
+	Search(ctx context.Context, query string, limit int) ([]entity.ProductSearchResult, error)
+
+	// IndexProduct adds a product to the index, or refreshes it if already present.
+	// Callers only index purchasable products and remove the others.
+	// Parameters:
+	//   - product: A pointer to the Product entity to index.
+	// Returns:
+	//   - An error if any issues occur during indexing.
+	IndexProduct(ctx context.Context, product *entity.Product) error
+
+	// RemoveProduct removes a product from the index by its ID.
+	// Parameters:
+	//   - id: The ID of the product to remove.
+	// Returns:
+	//   - An error if any issues occur during removal.
+	RemoveProduct(ctx context.Context, id int64) error
+}
+
+// mysqlSearchRepository is a SearchRepository backed by the MySQL FULLTEXT index on products.
+// Matching is done in boolean mode with prefix terms so partially typed words still match,
+// while ranking uses natural language relevance. When nothing matches, misspelled terms are
+// corrected against the search_terms vocabulary of indexed words and the search is repeated.
+type mysqlSearchRepository struct {
+	db *gorm.DB
+}
+
+// productSearchRow is the scan target for full-text queries.
+type productSearchRow struct {
+	entity.Product
+	Score float64
+}
+
+// NewMySQLSearchRepository creates a new instance of mysqlSearchRepository.
+// Returns:
+//   - A SearchRepository instance.
+func NewMySQLSearchRepository(db *gorm.DB) SearchRepository {
+	return &mysqlSearchRepository{
+		db: db,
+	}
+}
+
+func (r *mysqlSearchRepository) Search(ctx context.Context, query string, limit int) ([]entity.ProductSearchResult, error) {
+	terms := tokenize(query)
+	if len(terms) == 0 {
+		return []entity.ProductSearchResult{}, nil
+	}
+
+	rows, err := r.match(ctx, terms, limit)
+	if err == nil && len(rows) == 0 {
+		var corrected []string
+		corrected, err = r.correctTerms(ctx, terms)
+		if err == nil && len(corrected) > len(terms) {
+			terms = corrected
+			rows, err = r.match(ctx, terms, limit)
+		}
+	}
+	if err != nil {
+		log.Logger.Error().Err(err).Str("query", query).Msg("Failed to search products in database")
+		return nil, fmt.Errorf("failed to search products in database: %w", err)
+	}
+
+	results := make([]entity.ProductSearchResult, 0, len(rows))
+	for _, row := range rows {
+		results = append(results, entity.ProductSearchResult{
+			Product:    row.Product,
+			Score:      row.Score,
+			Highlights: highlightProduct(&row.Product, terms),
+		})
+	}
+	return results, nil
+}
+
+// match runs the FULLTEXT query for the terms.
+func (r *mysqlSearchRepository) match(ctx context.Context, terms []string, limit int) ([]productSearchRow, error) {
+	prefixTerms := make([]string, 0, len(terms))
+	for _, term := range terms {
+		prefixTerms = append(prefixTerms, term+"*")
+	}
+	booleanQuery := strings.Join(prefixTerms, " ")
+	naturalQuery := strings.Join(terms, " ")
+
+	var rows []productSearchRow
+	err := r.db.Table("products").WithContext(ctx).
+		Select("*, MATCH(name, description) AGAINST (? IN NATURAL LANGUAGE MODE) AS score", naturalQuery).
+		Where("MATCH(name, description) AGAINST (? IN BOOLEAN MODE)", booleanQuery).
+		Where("status = ? AND (unpublish_at IS NULL OR unpublish_at > ?)", entity.ProductStatusActive, time.Now().UTC()).
+		Order("score DESC").
+		Order("id ASC").
+		Limit(limit).
+		Find(&rows).Error
+	return rows, err
+}
+
+// correctTerms returns the terms followed by the vocabulary words within the allowed edit
+// distance of each of them. Candidates share the first letter of the term, which keeps the
+// lookup on the primary key; a typo in the first letter is not corrected.
+func (r *mysqlSearchRepository) correctTerms(ctx context.Context, terms []string) ([]string, error) {
+	corrected := append([]string(nil), terms...)
+	for _, term := range terms {
+		maxTypos := allowedTypos(term)
+		if maxTypos == 0 {
+			continue
+		}
+		first, length := []rune(term)[0], len([]rune(term))
+
+		var candidates []string
+		err := r.db.Table("search_terms").WithContext(ctx).
+			Where("term LIKE ? AND CHAR_LENGTH(term) BETWEEN ? AND ?", string(first)+"%", length-maxTypos, length+maxTypos).
+			Limit(maxTypoCandidates).
+			Pluck("term", &candidates).Error
+		if err != nil {
+			return nil, err
+		}
+		for _, candidate := range candidates {
+			if candidate != term && editDistance(term, candidate, maxTypos) <= maxTypos {
+				corrected = append(corrected, candidate)
+			}
+		}
+	}
+	return corrected, nil
+}
+
+// IndexProduct adds the words of the product to the search_terms vocabulary used to correct
+// typos; MySQL maintains the FULLTEXT index itself.
+func (r *mysqlSearchRepository) IndexProduct(ctx context.Context, product *entity.Product) error {
+	seen := make(map[string]bool)
+	var rows []map[string]interface{}
+	for _, term := range append(tokenize(product.Name), tokenize(product.Description)...) {
+		// The shortest term corrected has four letters and one typo, so shorter words are useless.
+		if n := len([]rune(term)); seen[term] || n < 3 || n > maxSearchTermLength {
+			continue
+		}
+		seen[term] = true
+		rows = append(rows, map[string]interface{}{"term": term})
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	err := r.db.Table("search_terms").WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(rows).Error
+	if err != nil {
+		log.Logger.Error().Err(err).Int64("productID", product.ID).Msg("Failed to index product search terms")
+		return fmt.Errorf("failed to index product search terms: %w", err)
+	}
+	return nil
+}
+
+// RemoveProduct is a no-op because MySQL maintains the FULLTEXT index itself. Words are left in
+// the vocabulary, where they can only correct a term into one that matches nothing.
+func (r *mysqlSearchRepository) RemoveProduct(ctx context.Context, id int64) error {
+	return nil
+}
+
+// tokenize lower-cases the text and splits it into letter and digit runs.
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// highlightProduct returns the name and description with matched words wrapped in <em> tags.
+// Fields without any match are omitted.
+func highlightProduct(product *entity.Product, terms []string) map[string]string {
+	highlights := make(map[string]string)
+	if name, ok := highlight(product.Name, terms); ok {
+		highlights["name"] = name
+	}
+	if description, ok := highlight(product.Description, terms); ok {
+		highlights["description"] = description
+	}
+	return highlights
+}
+
+// highlight wraps every word of text that starts with one of the terms in <em> tags.
+// It reports whether any word was highlighted.
+func highlight(text string, terms []string) (string, bool) {
+	var (
+		sb      strings.Builder
+		word    strings.Builder
+		matched bool
+	)
+
+	flush := func() {
+		if word.Len() == 0 {
+			return
+		}
+		w := word.String()
+		lower := strings.ToLower(w)
+		for _, term := range terms {
+			if strings.HasPrefix(lower, term) {
+				sb.WriteString("<em>" + w + "</em>")
+				matched = true
+				word.Reset()
+				return
+			}
+		}
+		sb.WriteString(w)
+		word.Reset()
+	}
+
+	for _, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			word.WriteRune(r)
+			continue
+		}
+		flush()
+		sb.WriteRune(r)
+	}
+	flush()
+
+	return sb.String(), matched
+}
@@ -0,0 +1,215 @@
+package repository
+
+import (
+	"context"
+	"math"
+	"product-catalog-service/internal/entity"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	// nameTermWeight boosts terms found in the product name over the description.
+	nameTermWeight = 3
+	// prefixMatchFactor and typoMatchFactor scale the score of inexact term matches.
+	prefixMatchFactor = 0.8
+	typoMatchFactor   = 0.6
+)
+
+// memorySearchRepository is an in-process inverted index implementing SearchRepository.
+// It is meant for tests and small deployments; the index lives only in memory and must be
+// populated at startup and kept in sync through IndexProduct and RemoveProduct.
+type memorySearchRepository struct {
+	mu       sync.RWMutex
+	products map[int64]entity.Product
+	// postings maps a term to the weighted term frequency per product ID.
+	postings map[string]map[int64]float64
+}
+
+// NewMemorySearchRepository creates a new, empty instance of memorySearchRepository.
+// Returns:
+//   - A SearchRepository instance.
+func NewMemorySearchRepository() SearchRepository {
+	return &memorySearchRepository{
+		products: make(map[int64]entity.Product),
+		postings: make(map[string]map[int64]float64),
+	}
+}
+
+func (r *memorySearchRepository) Search(ctx context.Context, query string, limit int) ([]entity.ProductSearchResult, error) {
+	terms := tokenize(query)
+	if len(terms) == 0 {
+		return []entity.ProductSearchResult{}, nil
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	total := float64(len(r.products))
+	scores := make(map[int64]float64)
+	matchedTerms := make(map[int64][]string)
+
+	for _, queryTerm := range terms {
+		for indexTerm, postings := range r.postings {
+			factor := matchFactor(queryTerm, indexTerm)
+			if factor == 0 {
+				continue
+			}
+			idf := math.Log(1 + total/float64(len(postings)))
+			for id, tf := range postings {
+				scores[id] += factor * tf * idf
+				matchedTerms[id] = append(matchedTerms[id], indexTerm)
+			}
+		}
+	}
+
+	results := make([]entity.ProductSearchResult, 0, len(scores))
+	for id, score := range scores {
+		product := r.products[id]
+		results = append(results, entity.ProductSearchResult{
+			Product:    product,
+			Score:      score,
+			Highlights: highlightProduct(&product, matchedTerms[id]),
+		})
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Product.ID < results[j].Product.ID
+	})
+
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+func (r *memorySearchRepository) IndexProduct(ctx context.Context, product *entity.Product) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.removeLocked(product.ID)
+
+	frequencies := make(map[string]float64)
+	for _, term := range tokenize(product.Name) {
+		frequencies[term] += nameTermWeight
+	}
+	for _, term := range tokenize(product.Description) {
+		frequencies[term]++
+	}
+
+	for term, tf := range frequencies {
+		postings, ok := r.postings[term]
+		if !ok {
+			postings = make(map[int64]float64)
+			r.postings[term] = postings
+		}
+		postings[product.ID] = tf
+	}
+	r.products[product.ID] = *product
+	return nil
+}
+
+func (r *memorySearchRepository) RemoveProduct(ctx context.Context, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.removeLocked(id)
+	return nil
+}
+
+// removeLocked drops every posting of the product. The caller must hold the write lock.
+func (r *memorySearchRepository) removeLocked(id int64) {
+	product, ok := r.products[id]
+	if !ok {
+		return
+	}
+
+	terms := append(tokenize(product.Name), tokenize(product.Description)...)
+	for _, term := range terms {
+		postings, ok := r.postings[term]
+		if !ok {
+			continue
+		}
+		delete(postings, id)
+		if len(postings) == 0 {
+			delete(r.postings, term)
+		}
+	}
+	delete(r.products, id)
+}
+
+// matchFactor scores how well an index term matches a query term: 1 for an exact match,
+// prefixMatchFactor when the query is a prefix of the term and typoMatchFactor when the
+// two are within the allowed edit distance. It returns 0 when they do not match.
+func matchFactor(queryTerm, indexTerm string) float64 {
+	if queryTerm == indexTerm {
+		return 1
+	}
+	if strings.HasPrefix(indexTerm, queryTerm) {
+		return prefixMatchFactor
+	}
+
+	maxTypos := allowedTypos(queryTerm)
+	if maxTypos == 0 {
+		return 0
+	}
+	if distance := editDistance(queryTerm, indexTerm, maxTypos); distance <= maxTypos {
+		return typoMatchFactor / float64(distance)
+	}
+	return 0
+}
+
+// allowedTypos returns the maximum edit distance tolerated for a query term.
+// Short terms must match exactly to avoid noisy results.
+func allowedTypos(term string) int {
+	switch n := len([]rune(term)); {
+	case n >= 8:
+		return 2
+	case n >= 4:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// editDistance computes the optimal string alignment distance between a and b, where an
+// insertion, deletion, substitution or transposition of adjacent characters each count as one
+// edit. It stops early and returns maxDistance+1 once the distance is known to exceed maxDistance.
+func editDistance(a, b string, maxDistance int) int {
+	ra, rb := []rune(a), []rune(b)
+	if diff := len(ra) - len(rb); diff > maxDistance || -diff > maxDistance {
+		return maxDistance + 1
+	}
+
+	prevPrev := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		rowMin := curr[0]
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				curr[j] = min(curr[j], prevPrev[j-2]+1)
+			}
+			rowMin = min(rowMin, curr[j])
+		}
+		if rowMin > maxDistance {
+			return maxDistance + 1
+		}
+		prevPrev, prev, curr = prev, curr, prevPrev
+	}
+	return prev[len(rb)]
+}
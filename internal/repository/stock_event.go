@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"product-catalog-service/infrastructure/log"
+	"product-catalog-service/internal/entity"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// StockEventRepository fans stock updates out to every instance of the service through Redis pub/sub.
+type StockEventRepository interface {
+	// PublishStockUpdate announces a stock change to every instance, including this one.
+	// Parameters:
+	//   - update: The stock of the product after the change.
+	// Returns:
+	//   - An error if the update could not be published.
+	PublishStockUpdate(ctx context.Context, update entity.StockUpdate) error
+
+	// SubscribeStockUpdates receives the updates published by every instance.
+	// Returns:
+	//   - A channel of updates, closed once ctx is done.
+	SubscribeStockUpdates(ctx context.Context) <-chan entity.StockUpdate
+}
+
+type stockEventRepository struct {
+	rdb     *redis.Client
+	channel string
+}
+
+// NewStockEventRepository creates and returns a new instance of stockEventRepository.
+func NewStockEventRepository(rdb *redis.Client, channel string) StockEventRepository {
+	return &stockEventRepository{
+		rdb:     rdb,
+		channel: channel,
+	}
+}
+
+func (r *stockEventRepository) PublishStockUpdate(ctx context.Context, update entity.StockUpdate) error {
+	payload, err := json.Marshal(update)
+	if err != nil {
+		return fmt.Errorf("failed to marshal stock update: %w", err)
+	}
+	if err := r.rdb.Publish(ctx, r.channel, payload).Err(); err != nil {
+		return fmt.Errorf("failed to publish stock update: %w", err)
+	}
+	return nil
+}
+
+func (r *stockEventRepository) SubscribeStockUpdates(ctx context.Context) <-chan entity.StockUpdate {
+	// The client resubscribes by itself after a dropped connection.
+	pubsub := r.rdb.Subscribe(ctx, r.channel)
+	messages := pubsub.Channel()
+
+	updates := make(chan entity.StockUpdate)
+	go func() {
+		defer close(updates)
+		defer pubsub.Close()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-messages:
+				if !ok {
+					return
+				}
+				var update entity.StockUpdate
+				if err := json.Unmarshal([]byte(msg.Payload), &update); err != nil {
+					log.Logger.Error().Err(err).Str("channel", r.channel).Msg("Failed to unmarshal stock update")
+					continue
+				}
+				select {
+				case updates <- update:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return updates
+}
@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"product-catalog-service/infrastructure/log"
+	"product-catalog-service/internal/entity"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TagRepository defines the interface for tag-related database operations.
+type TagRepository interface {
+	// GetTags retrieves all tags.
+	// Returns:
+	//   - A slice of Tag entities.
+	//   - An error if any issues occur during retrieval.
+	GetTags(ctx context.Context) ([]entity.Tag, error)
+
+	// CreateTag creates a new tag.
+	// Parameters:
+	//   - tag: A pointer to the Tag entity to create. Its ID is set on success.
+	// Returns:
+	//   - An error if any issues occur during creation.
+	CreateTag(ctx context.Context, tag *entity.Tag) error
+
+	// DeleteTag deletes a tag by its ID and detaches it from every product.
+	// Parameters:
+	//   - id: The ID of the tag to delete.
+	// Returns:
+	//   - An error if any issues occur during deletion.
+	DeleteTag(ctx context.Context, id int64) error
+
+	// SetProductTags replaces the tags of a product, creating tags that do not exist yet.
+	// Parameters:
+	//   - productID: The ID of the product to tag.
+	//   - names: The complete set of tag names for the product.
+	// Returns:
+	//   - An error if any issues occur while updating the tags.
+	SetProductTags(ctx context.Context, productID int64, names []string) error
+
+	// GetTagsByProductIDs retrieves the tag names of several products at once.
+	// Parameters:
+	//   - productIDs: The IDs of the products to look up.
+	// Returns:
+	//   - A map from product ID to its tag names. Products without tags are absent.
+	//   - An error if any issues occur during retrieval.
+	GetTagsByProductIDs(ctx context.Context, productIDs []int64) (map[int64][]string, error)
+}
+
+// tagRepository is a concrete implementation of the TagRepository interface.
+type tagRepository struct {
+	db *gorm.DB
+}
+
+// productTag is a row of the product_tags join table.
+type productTag struct {
+	ProductID int64
+	TagID     int64
+}
+
+// NewTagRepository creates a new instance of tagRepository.
+// Returns:
+//   - A TagRepository instance.
+func NewTagRepository(db *gorm.DB) TagRepository {
+	return &tagRepository{
+		db: db,
+	}
+}
+
+func (r *tagRepository) GetTags(ctx context.Context) ([]entity.Tag, error) {
+	var tags []entity.Tag
+	err := r.db.Table("tags").WithContext(ctx).Order("name ASC").Find(&tags).Error
+	if err != nil {
+		log.Logger.Error().Err(err).Msg("Failed to get tags from database")
+		return nil, fmt.Errorf("failed to get tags from database: %w", err)
+	}
+	return tags, nil
+}
+
+func (r *tagRepository) CreateTag(ctx context.Context, tag *entity.Tag) error {
+	err := r.db.Table("tags").WithContext(ctx).Create(tag).Error
+	if err != nil {
+		log.Logger.Error().Err(err).Str("name", tag.Name).Msg("Failed to create tag in database")
+		return fmt.Errorf("failed to create tag in database: %w", translateError(err))
+	}
+	return nil
+}
+
+func (r *tagRepository) DeleteTag(ctx context.Context, id int64) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Table("product_tags").Where("tag_id = ?", id).Delete(&productTag{}).Error; err != nil {
+			return err
+		}
+		return tx.Table("tags").Delete(&entity.Tag{}, id).Error
+	})
+	if err != nil {
+		log.Logger.Error().Err(err).Int64("tagID", id).Msg("Failed to delete tag from database")
+		return fmt.Errorf("failed to delete tag from database: %w", err)
+	}
+	return nil
+}
+
+func (r *tagRepository) SetProductTags(ctx context.Context, productID int64, names []string) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Table("product_tags").Where("product_id = ?", productID).Delete(&productTag{}).Error; err != nil {
+			return err
+		}
+		if len(names) == 0 {
+			return nil
+		}
+
+		tags := make([]entity.Tag, 0, len(names))
+		for _, name := range names {
+			tags = append(tags, entity.Tag{Name: name})
+		}
+		if err := tx.Table("tags").Clauses(clause.OnConflict{DoNothing: true}).Create(&tags).Error; err != nil {
+			return err
+		}
+
+		var tagIDs []int64
+		if err := tx.Table("tags").Where("name IN ?", names).Pluck("id", &tagIDs).Error; err != nil {
+			return err
+		}
+
+		links := make([]productTag, 0, len(tagIDs))
+		for _, tagID := range tagIDs {
+			links = append(links, productTag{ProductID: productID, TagID: tagID})
+		}
+		return tx.Table("product_tags").Create(&links).Error
+	})
+	if err != nil {
+		log.Logger.Error().Err(err).Int64("productID", productID).Msg("Failed to set product tags in database")
+		return fmt.Errorf("failed to set product tags in database: %w", translateError(err))
+	}
+	return nil
+}
+
+func (r *tagRepository) GetTagsByProductIDs(ctx context.Context, productIDs []int64) (map[int64][]string, error) {
+	tagsByProduct := make(map[int64][]string)
+	if len(productIDs) == 0 {
+		return tagsByProduct, nil
+	}
+
+	var rows []struct {
+		ProductID int64
+		Name      string
+	}
+	err := r.db.Table("product_tags").WithContext(ctx).
+		Select("product_tags.product_id, tags.name").
+		Joins("JOIN tags ON tags.id = product_tags.tag_id").
+		Where("product_tags.product_id IN ?", productIDs).
+		Order("tags.name ASC").
+		Scan(&rows).Error
+	if err != nil {
+		log.Logger.Error().Err(err).Msg("Failed to get product tags from database")
+		return nil, fmt.Errorf("failed to get product tags from database: %w", err)
+	}
+
+	for _, row := range rows {
+		tagsByProduct[row.ProductID] = append(tagsByProduct[row.ProductID], row.Name)
+	}
+	return tagsByProduct, nil
+}
@@ -0,0 +1,209 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"product-catalog-service/infrastructure/log"
+	"product-catalog-service/internal/entity"
+
+	"gorm.io/gorm"
+)
+
+// VariantRepository defines the interface for product variant (SKU) database operations.
+// Every write that changes variant stock also recomputes the stock of the parent product.
+type VariantRepository interface {
+	// GetVariantByID retrieves a variant by its ID.
+	// Parameters:
+	//   - id: The ID of the variant to retrieve.
+	// Returns:
+	//   - A pointer to the Variant entity if found, or nil if not found.
+	//   - An error if any issues occur during retrieval.
+	GetVariantByID(ctx context.Context, id int64) (*entity.Variant, error)
+
+	// GetVariantsByProductID retrieves all variants of a product.
+	// Parameters:
+	//   - productID: The ID of the parent product.
+	// Returns:
+	//   - A slice of Variant entities.
+	//   - An error if any issues occur during retrieval.
+	GetVariantsByProductID(ctx context.Context, productID int64) ([]entity.Variant, error)
+
+	// CountVariants returns the number of variants of a product.
+	// Parameters:
+	//   - productID: The ID of the parent product.
+	// Returns:
+	//   - The number of variants.
+	//   - An error if any issues occur during the count.
+	CountVariants(ctx context.Context, productID int64) (int64, error)
+
+	// CreateVariant creates a new variant and updates the product stock.
+	// Parameters:
+	//   - variant: A pointer to the Variant entity to create. Its ID is set on success.
+	// Returns:
+	//   - An error if any issues occur during creation.
+	CreateVariant(ctx context.Context, variant *entity.Variant) error
+
+	// UpdateVariant updates an existing variant and the product stock.
+	// Parameters:
+	//   - variant: A pointer to the Variant entity with updated data.
+	// Returns:
+	//   - An error if any issues occur during the update.
+	UpdateVariant(ctx context.Context, variant *entity.Variant) error
+
+	// DeleteVariant deletes a variant and updates the product stock.
+	// Parameters:
+	//   - variant: A pointer to the Variant entity to delete.
+	// Returns:
+	//   - An error if any issues occur during deletion.
+	DeleteVariant(ctx context.Context, variant *entity.Variant) error
+
+	// AdjustVariantStock atomically adds delta to the variant stock and updates the product stock.
+	// A negative delta is only applied if enough stock is available.
+	// Parameters:
+	//   - variant: A pointer to the Variant entity to adjust.
+	//   - delta: The stock change; negative to reserve, positive to release.
+	// Returns:
+	//   - True if the stock was adjusted, false if there was not enough stock.
+	//   - An error if any issues occur during the update.
+	AdjustVariantStock(ctx context.Context, variant *entity.Variant, delta int) (bool, error)
+}
+
+// variantRepository is a concrete implementation of the VariantRepository interface.
+type variantRepository struct {
+	cache CacheRepository
+	db    *gorm.DB
+}
+
+// NewVariantRepository creates a new instance of variantRepository.
+// Returns:
+//   - A VariantRepository instance.
+func NewVariantRepository(cacheRepo CacheRepository, db *gorm.DB) VariantRepository {
+	return &variantRepository{
+		cache: cacheRepo,
+		db:    db,
+	}
+}
+
+func (r *variantRepository) GetVariantByID(ctx context.Context, id int64) (*entity.Variant, error) {
+	var variant entity.Variant
+	err := r.db.Table("product_variants").WithContext(ctx).Where("id = ?", id).First(&variant).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		log.Logger.Error().Err(err).Int64("variantID", id).Msg("Failed to get variant from database")
+		return nil, fmt.Errorf("failed to get variant from database: %w", err)
+	}
+	return &variant, nil
+}
+
+func (r *variantRepository) GetVariantsByProductID(ctx context.Context, productID int64) ([]entity.Variant, error) {
+	var variants []entity.Variant
+	err := r.db.Table("product_variants").WithContext(ctx).Where("product_id = ?", productID).Order("id ASC").Find(&variants).Error
+	if err != nil {
+		log.Logger.Error().Err(err).Int64("productID", productID).Msg("Failed to get variants from database")
+		return nil, fmt.Errorf("failed to get variants from database: %w", err)
+	}
+	return variants, nil
+}
+
+func (r *variantRepository) CountVariants(ctx context.Context, productID int64) (int64, error) {
+	var count int64
+	err := r.db.Table("product_variants").WithContext(ctx).Where("product_id = ?", productID).Count(&count).Error
+	if err != nil {
+		log.Logger.Error().Err(err).Int64("productID", productID).Msg("Failed to count variants")
+		return 0, fmt.Errorf("failed to count variants: %w", err)
+	}
+	return count, nil
+}
+
+func (r *variantRepository) CreateVariant(ctx context.Context, variant *entity.Variant) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Table("product_variants").Create(variant).Error; err != nil {
+			return err
+		}
+		return syncProductStock(tx, variant.ProductID)
+	})
+	if err != nil {
+		log.Logger.Error().Err(err).Int64("productID", variant.ProductID).Msg("Failed to create variant in database")
+		return fmt.Errorf("failed to create variant in database: %w", translateError(err))
+	}
+
+	r.invalidateProduct(ctx, variant.ProductID)
+	return nil
+}
+
+func (r *variantRepository) UpdateVariant(ctx context.Context, variant *entity.Variant) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Table("product_variants").Save(variant).Error; err != nil {
+			return err
+		}
+		return syncProductStock(tx, variant.ProductID)
+	})
+	if err != nil {
+		log.Logger.Error().Err(err).Int64("variantID", variant.ID).Msg("Failed to update variant in database")
+		return fmt.Errorf("failed to update variant in database: %w", translateError(err))
+	}
+
+	r.invalidateProduct(ctx, variant.ProductID)
+	return nil
+}
+
+func (r *variantRepository) DeleteVariant(ctx context.Context, variant *entity.Variant) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Table("product_variants").Delete(&entity.Variant{}, variant.ID).Error; err != nil {
+			return err
+		}
+		return syncProductStock(tx, variant.ProductID)
+	})
+	if err != nil {
+		log.Logger.Error().Err(err).Int64("variantID", variant.ID).Msg("Failed to delete variant from database")
+		return fmt.Errorf("failed to delete variant from database: %w", err)
+	}
+
+	r.invalidateProduct(ctx, variant.ProductID)
+	return nil
+}
+
+func (r *variantRepository) AdjustVariantStock(ctx context.Context, variant *entity.Variant, delta int) (bool, error) {
+	adjusted := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Table("product_variants").
+			Where("id = ? AND stock + ? >= 0", variant.ID, delta).
+			Update("stock", gorm.Expr("stock + ?", delta))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		adjusted = true
+		return syncProductStock(tx, variant.ProductID)
+	})
+	if err != nil {
+		log.Logger.Error().Err(err).Int64("variantID", variant.ID).Int("delta", delta).Msg("Failed to adjust variant stock in database")
+		return false, fmt.Errorf("failed to adjust variant stock in database: %w", err)
+	}
+
+	if adjusted {
+		variant.Stock += delta
+		r.invalidateProduct(ctx, variant.ProductID)
+	}
+	return adjusted, nil
+}
+
+// invalidateProduct drops the cached product so the recomputed stock is read on the next request.
+func (r *variantRepository) invalidateProduct(ctx context.Context, productID int64) {
+	if err := r.cache.Delete(ctx, fmt.Sprintf("product:%d", productID)); err != nil {
+		log.Logger.Error().Err(err).Int64("productID", productID).Msg("Failed to invalidate product in cache")
+	}
+}
+
+// syncProductStock sets the product stock to the sum of its variant stock within tx.
+func syncProductStock(tx *gorm.DB, productID int64) error {
+	return tx.Exec(`UPDATE products
+		SET stock = (SELECT COALESCE(SUM(stock), 0) FROM product_variants WHERE product_id = ?)
+		WHERE id = ?`, productID, productID).Error
+}
@@ -22,6 +22,8 @@ func InitDB(appConfig config.Config) *gorm.DB {
 	// Connect to database using GORM
 	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{
 		Logger: logger.Default.LogMode(logger.Info),
+		// Report constraint violations as gorm.ErrDuplicatedKey and gorm.ErrForeignKeyViolated.
+		TranslateError: true,
 	})
 
 	if err != nil {
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"product-catalog-service/internal/entity"
+	"product-catalog-service/internal/repository"
+)
+
+// CacheService reports on the in-process cache tier of this instance.
+type CacheService interface {
+	// Stats returns the counters of the in-process cache, with Enabled false when it is disabled.
+	Stats() entity.CacheStats
+}
+
+type cacheService struct {
+	localCache repository.LRUCacheRepository
+}
+
+// NewCacheService creates and returns a new instance of cacheService. localCache is nil when the
+// in-process cache is disabled.
+func NewCacheService(localCache repository.LRUCacheRepository) CacheService {
+	return &cacheService{
+		localCache: localCache,
+	}
+}
+
+func (s *cacheService) Stats() entity.CacheStats {
+	if s.localCache == nil {
+		return entity.CacheStats{}
+	}
+	stats := s.localCache.Stats()
+	return entity.CacheStats{
+		Enabled:       true,
+		Hits:          stats.Hits,
+		Misses:        stats.Misses,
+		HitRatio:      stats.HitRatio(),
+		Evictions:     stats.Evictions,
+		Invalidations: stats.Invalidations,
+		Entries:       stats.Entries,
+		Bytes:         stats.Bytes,
+	}
+}
@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"product-catalog-service/infrastructure/log"
+	"product-catalog-service/internal/apperror"
+	"product-catalog-service/internal/entity"
+	"product-catalog-service/internal/repository"
+	"strings"
+)
+
+var (
+	// ErrCategoryNotFound is returned when a category does not exist.
+	ErrCategoryNotFound = apperror.New(apperror.KindNotFound, "category_not_found", "category not found")
+	// ErrCategoryHasChildren is returned when deleting a category that still has child categories.
+	ErrCategoryHasChildren = apperror.New(apperror.KindConflict, "category_has_children", "category has child categories")
+	// ErrInvalidCategory is returned when a category would break the tree, e.g. by becoming its own ancestor.
+	ErrInvalidCategory = apperror.New(apperror.KindInvalid, "invalid_category", "invalid category")
+	// ErrInvalidTag is returned when a tag has no name.
+	ErrInvalidTag = apperror.New(apperror.KindInvalid, "invalid_tag", "tag name is required")
+)
+
+type CategoryService interface {
+	GetCategories(ctx context.Context) ([]entity.Category, error)
+	GetCategory(ctx context.Context, id int64) (*entity.Category, error)
+	CreateCategory(ctx context.Context, category *entity.Category) error
+	UpdateCategory(ctx context.Context, category *entity.Category) error
+	DeleteCategory(ctx context.Context, id int64) error
+	GetTags(ctx context.Context) ([]entity.Tag, error)
+	CreateTag(ctx context.Context, tag *entity.Tag) error
+	DeleteTag(ctx context.Context, id int64) error
+	AssignProductCategory(ctx context.Context, productID int64, categoryID *int64) error
+	SetProductTags(ctx context.Context, productID int64, tags []string) error
+}
+
+type categoryService struct {
+	categoryRepo repository.CategoryRepository
+	tagRepo      repository.TagRepository
+	productRepo  repository.ProductRepository
+}
+
+// NewCategoryService creates and returns a new instance of categoryService.
+func NewCategoryService(categoryRepo repository.CategoryRepository, tagRepo repository.TagRepository, productRepo repository.ProductRepository) CategoryService {
+	return &categoryService{
+		categoryRepo: categoryRepo,
+		tagRepo:      tagRepo,
+		productRepo:  productRepo,
+	}
+}
+
+func (s *categoryService) GetCategories(ctx context.Context) ([]entity.Category, error) {
+	return s.categoryRepo.GetCategories(ctx)
+}
+
+func (s *categoryService) GetCategory(ctx context.Context, id int64) (*entity.Category, error) {
+	return s.categoryRepo.GetCategoryByID(ctx, id)
+}
+
+func (s *categoryService) CreateCategory(ctx context.Context, category *entity.Category) error {
+	if err := s.validateParent(ctx, category); err != nil {
+		return err
+	}
+	return s.categoryRepo.CreateCategory(ctx, category)
+}
+
+func (s *categoryService) UpdateCategory(ctx context.Context, category *entity.Category) error {
+	existing, err := s.categoryRepo.GetCategoryByID(ctx, category.ID)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		log.Logger.Warn().Int64("categoryID", category.ID).Msg("Category not found for update")
+		return ErrCategoryNotFound
+	}
+
+	if err := s.validateParent(ctx, category); err != nil {
+		return err
+	}
+	return s.categoryRepo.UpdateCategory(ctx, category)
+}
+
+func (s *categoryService) DeleteCategory(ctx context.Context, id int64) error {
+	children, err := s.categoryRepo.CountChildren(ctx, id)
+	if err != nil {
+		return err
+	}
+	if children > 0 {
+		log.Logger.Warn().Int64("categoryID", id).Int64("children", children).Msg("Refusing to delete category with children")
+		return ErrCategoryHasChildren
+	}
+	return s.categoryRepo.DeleteCategory(ctx, id)
+}
+
+func (s *categoryService) GetTags(ctx context.Context) ([]entity.Tag, error) {
+	return s.tagRepo.GetTags(ctx)
+}
+
+func (s *categoryService) CreateTag(ctx context.Context, tag *entity.Tag) error {
+	tag.Name = normalizeTag(tag.Name)
+	if tag.Name == "" {
+		return ErrInvalidTag
+	}
+	return s.tagRepo.CreateTag(ctx, tag)
+}
+
+func (s *categoryService) DeleteTag(ctx context.Context, id int64) error {
+	return s.tagRepo.DeleteTag(ctx, id)
+}
+
+func (s *categoryService) AssignProductCategory(ctx context.Context, productID int64, categoryID *int64) error {
+	product, err := s.productRepo.GetProductByID(ctx, productID)
+	if err != nil {
+		return err
+	}
+	if product == nil {
+		log.Logger.Warn().Int64("productID", productID).Msg("Product not found for category assignment")
+		return ErrProductNotFound
+	}
+
+	if categoryID != nil {
+		category, err := s.categoryRepo.GetCategoryByID(ctx, *categoryID)
+		if err != nil {
+			return err
+		}
+		if category == nil {
+			log.Logger.Warn().Int64("categoryID", *categoryID).Msg("Category not found for product assignment")
+			return ErrCategoryNotFound
+		}
+	}
+
+	product.CategoryID = categoryID
+	_, err = s.productRepo.UpdateProduct(ctx, product)
+	return err
+}
+
+func (s *categoryService) SetProductTags(ctx context.Context, productID int64, tags []string) error {
+	product, err := s.productRepo.GetProductByID(ctx, productID)
+	if err != nil {
+		return err
+	}
+	if product == nil {
+		log.Logger.Warn().Int64("productID", productID).Msg("Product not found for tagging")
+		return ErrProductNotFound
+	}
+
+	seen := make(map[string]bool, len(tags))
+	names := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		name := normalizeTag(tag)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+
+	return s.tagRepo.SetProductTags(ctx, productID, names)
+}
+
+// validateParent checks that the parent of a category exists and that attaching the category
+// to it would not create a cycle in the tree.
+func (s *categoryService) validateParent(ctx context.Context, category *entity.Category) error {
+	if category.ParentID == nil {
+		return nil
+	}
+	if *category.ParentID == category.ID {
+		return fmt.Errorf("%w: category cannot be its own parent", ErrInvalidCategory)
+	}
+
+	categories, err := s.categoryRepo.GetCategories(ctx)
+	if err != nil {
+		return err
+	}
+	parents := make(map[int64]*int64, len(categories))
+	for _, c := range categories {
+		parents[c.ID] = c.ParentID
+	}
+
+	if _, ok := parents[*category.ParentID]; !ok {
+		log.Logger.Warn().Int64("parentID", *category.ParentID).Msg("Parent category not found")
+		return fmt.Errorf("%w: parent category %d", ErrCategoryNotFound, *category.ParentID)
+	}
+
+	// Walk up from the new parent; reaching the category itself means it would become its own ancestor.
+	for ancestor, depth := category.ParentID, 0; ancestor != nil && depth <= len(categories); ancestor, depth = parents[*ancestor], depth+1 {
+		if category.ID != 0 && *ancestor == category.ID {
+			return fmt.Errorf("%w: category cannot be moved under its own descendant", ErrInvalidCategory)
+		}
+	}
+	return nil
+}
+
+// normalizeTag trims and lower-cases a tag name so equivalent spellings share one tag.
+func normalizeTag(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"product-catalog-service/infrastructure/log"
+	"product-catalog-service/internal/apperror"
+	"product-catalog-service/internal/entity"
+	"product-catalog-service/internal/repository"
+	"product-catalog-service/pkg/money"
+	"time"
+)
+
+var (
+	// ErrInvalidCurrency is returned for unsupported currency codes and invalid exchange rates.
+	ErrInvalidCurrency = apperror.New(apperror.KindInvalid, "invalid_currency", "invalid currency")
+	// ErrCurrencyUnavailable is returned when a product has neither a price list entry nor an
+	// exchange rate for the requested currency.
+	ErrCurrencyUnavailable = apperror.New(apperror.KindInvalid, "currency_unavailable", "price not available in the requested currency")
+	// ErrCurrencyNotFound is returned when deleting an exchange rate or currency price that does not exist.
+	ErrCurrencyNotFound = apperror.New(apperror.KindNotFound, "currency_not_found", "exchange rate or currency price not found")
+)
+
+type CurrencyService interface {
+	GetExchangeRates(ctx context.Context) ([]entity.ExchangeRate, error)
+	SetExchangeRate(ctx context.Context, rate *entity.ExchangeRate) error
+	DeleteExchangeRate(ctx context.Context, base, quote string) error
+	GetCurrencyPrices(ctx context.Context, productID int64) ([]entity.CurrencyPrice, error)
+	SetCurrencyPrice(ctx context.Context, productID int64, price money.Money) (*entity.CurrencyPrice, error)
+	DeleteCurrencyPrice(ctx context.Context, productID int64, currency string) error
+	LocalizeProducts(ctx context.Context, products []entity.Product, currency string) error
+	PriceIn(ctx context.Context, product *entity.Product, variant *entity.Variant, currency string) (money.Money, error)
+}
+
+type currencyService struct {
+	currencyRepo repository.CurrencyRepository
+	productRepo  repository.ProductRepository
+}
+
+// NewCurrencyService creates and returns a new instance of currencyService.
+func NewCurrencyService(currencyRepo repository.CurrencyRepository, productRepo repository.ProductRepository) CurrencyService {
+	return &currencyService{
+		currencyRepo: currencyRepo,
+		productRepo:  productRepo,
+	}
+}
+
+func (s *currencyService) GetExchangeRates(ctx context.Context) ([]entity.ExchangeRate, error) {
+	return s.currencyRepo.GetExchangeRates(ctx)
+}
+
+func (s *currencyService) SetExchangeRate(ctx context.Context, rate *entity.ExchangeRate) error {
+	base, err := money.NormalizeCurrency(rate.BaseCurrency)
+	if err != nil {
+		return fmt.Errorf("%w: unsupported base currency %q", ErrInvalidCurrency, rate.BaseCurrency)
+	}
+	quote, err := money.NormalizeCurrency(rate.QuoteCurrency)
+	if err != nil {
+		return fmt.Errorf("%w: unsupported quote currency %q", ErrInvalidCurrency, rate.QuoteCurrency)
+	}
+	if base == quote {
+		return fmt.Errorf("%w: base and quote currency must differ", ErrInvalidCurrency)
+	}
+	if rate.Rate <= 0 {
+		return fmt.Errorf("%w: rate must be positive", ErrInvalidCurrency)
+	}
+
+	rate.BaseCurrency = base
+	rate.QuoteCurrency = quote
+	rate.UpdatedAt = time.Now()
+	if err := s.currencyRepo.SaveExchangeRate(ctx, rate); err != nil {
+		return err
+	}
+	log.Logger.Info().Str("base", base).Str("quote", quote).Str("rate", rate.Rate.String()).Msg("Exchange rate updated")
+	return nil
+}
+
+func (s *currencyService) DeleteExchangeRate(ctx context.Context, base, quote string) error {
+	base, err := money.NormalizeCurrency(base)
+	if err != nil {
+		return fmt.Errorf("%w: unsupported base currency", ErrInvalidCurrency)
+	}
+	quote, err = money.NormalizeCurrency(quote)
+	if err != nil {
+		return fmt.Errorf("%w: unsupported quote currency", ErrInvalidCurrency)
+	}
+
+	deleted, err := s.currencyRepo.DeleteExchangeRate(ctx, base, quote)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return fmt.Errorf("%w: no %s to %s exchange rate", ErrCurrencyNotFound, base, quote)
+	}
+	return nil
+}
+
+func (s *currencyService) GetCurrencyPrices(ctx context.Context, productID int64) ([]entity.CurrencyPrice, error) {
+	return s.currencyRepo.GetProductCurrencyPrices(ctx, productID)
+}
+
+// SetCurrencyPrice sets the explicit price of a product in a currency other than its own.
+func (s *currencyService) SetCurrencyPrice(ctx context.Context, productID int64, price money.Money) (*entity.CurrencyPrice, error) {
+	product, err := s.productRepo.GetProductByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if product == nil {
+		log.Logger.Warn().Int64("productID", productID).Msg("Product not found for currency price")
+		return nil, ErrProductNotFound
+	}
+	if price.Currency == "" || price.IsNegative() {
+		return nil, fmt.Errorf("%w: a non-negative price with a currency is required", ErrInvalidPrice)
+	}
+	if price.Currency == product.Price.Currency {
+		return nil, fmt.Errorf("%w: %s is the product currency, change the product price instead", ErrInvalidPrice, price.Currency)
+	}
+
+	currencyPrice := &entity.CurrencyPrice{
+		ProductID: productID,
+		Price:     price,
+		UpdatedAt: time.Now(),
+	}
+	if err := s.currencyRepo.SaveCurrencyPrice(ctx, currencyPrice); err != nil {
+		return nil, err
+	}
+	return currencyPrice, nil
+}
+
+func (s *currencyService) DeleteCurrencyPrice(ctx context.Context, productID int64, currency string) error {
+	currency, err := money.NormalizeCurrency(currency)
+	if err != nil {
+		return fmt.Errorf("%w: unsupported currency", ErrInvalidCurrency)
+	}
+
+	deleted, err := s.currencyRepo.DeleteCurrencyPrice(ctx, productID, currency)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return fmt.Errorf("%w: product %d has no %s price", ErrCurrencyNotFound, productID, currency)
+	}
+	return nil
+}
+
+// LocalizeProducts replaces the price of each product with its price in currency. An empty
+// currency leaves the prices in the product currency.
+func (s *currencyService) LocalizeProducts(ctx context.Context, products []entity.Product, currency string) error {
+	if currency == "" || len(products) == 0 {
+		return nil
+	}
+	currency, err := money.NormalizeCurrency(currency)
+	if err != nil {
+		return fmt.Errorf("%w: unsupported currency %q", ErrInvalidCurrency, currency)
+	}
+
+	productIDs := make([]int64, 0, len(products))
+	for _, product := range products {
+		productIDs = append(productIDs, product.ID)
+	}
+	listed, err := s.currencyRepo.GetCurrencyPrices(ctx, productIDs, currency)
+	if err != nil {
+		return err
+	}
+
+	rates := make(map[string]*entity.ExchangeRate)
+	for i := range products {
+		product := &products[i]
+		if product.Price.Currency == currency {
+			continue
+		}
+		if listedPrice, ok := listed[product.ID]; ok {
+			product.Price = listedPrice.Price
+			continue
+		}
+
+		rate, ok := rates[product.Price.Currency]
+		if !ok {
+			if rate, err = s.currencyRepo.GetExchangeRate(ctx, product.Price.Currency, currency); err != nil {
+				return err
+			}
+			rates[product.Price.Currency] = rate
+		}
+		if product.Price, err = convertPrice(product.Price, rate, currency); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PriceIn returns the price of a product, or of one of its variants, in currency. An explicit
+// price list entry wins over conversion, but only applies to the product price: a variant price
+// override is always converted. An empty currency returns the price in the product currency.
+func (s *currencyService) PriceIn(ctx context.Context, product *entity.Product, variant *entity.Variant, currency string) (money.Money, error) {
+	base := product.Price
+	if variant != nil && variant.Price != nil {
+		base = *variant.Price
+	}
+	if currency == "" || currency == base.Currency {
+		return base, nil
+	}
+	currency, err := money.NormalizeCurrency(currency)
+	if err != nil {
+		return money.Money{}, fmt.Errorf("%w: unsupported currency %q", ErrInvalidCurrency, currency)
+	}
+
+	if variant == nil || variant.Price == nil {
+		listed, err := s.currencyRepo.GetCurrencyPrices(ctx, []int64{product.ID}, currency)
+		if err != nil {
+			return money.Money{}, err
+		}
+		if listedPrice, ok := listed[product.ID]; ok {
+			return listedPrice.Price, nil
+		}
+	}
+
+	rate, err := s.currencyRepo.GetExchangeRate(ctx, base.Currency, currency)
+	if err != nil {
+		return money.Money{}, err
+	}
+	return convertPrice(base, rate, currency)
+}
+
+// convertPrice converts a price with an exchange rate, which is nil when none is configured.
+func convertPrice(price money.Money, rate *entity.ExchangeRate, currency string) (money.Money, error) {
+	if rate == nil {
+		return money.Money{}, fmt.Errorf("%w: no %s to %s exchange rate", ErrCurrencyUnavailable, price.Currency, currency)
+	}
+	return price.Convert(currency, rate.Rate, money.DefaultRounding)
+}
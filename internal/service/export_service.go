@@ -0,0 +1,241 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"product-catalog-service/infrastructure/log"
+	"product-catalog-service/internal/apperror"
+	"product-catalog-service/internal/entity"
+	"product-catalog-service/internal/repository"
+	"strconv"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+const defaultExportBatchSize = 1000
+
+// ErrUnsupportedExportFormat is returned when an export is requested in an unknown format.
+var ErrUnsupportedExportFormat = apperror.New(apperror.KindInvalid, "unsupported_export_format", "format must be csv, jsonl or parquet")
+
+type ExportService interface {
+	ExportProducts(ctx context.Context, w io.Writer, opts entity.ExportOptions, begin func(snapshotAt time.Time)) (*entity.ExportSummary, error)
+}
+
+type exportService struct {
+	productRepo repository.ProductRepository
+}
+
+// NewExportService creates and returns a new instance of exportService.
+func NewExportService(productRepo repository.ProductRepository) ExportService {
+	return &exportService{
+		productRepo: productRepo,
+	}
+}
+
+// exportWriter encodes export rows in one output format.
+type exportWriter interface {
+	WriteRows(rows []entity.ExportRow) error
+	// Close flushes buffered data and writes any format footer. It does not close the underlying writer.
+	Close() error
+}
+
+// ExportProducts streams every product with its current stock to w, as of a single consistent snapshot.
+// begin, if not nil, is called with the snapshot time before the first byte is written, so callers can
+// announce it (e.g. in a response header). The returned summary carries the SHA-256 of the written bytes.
+func (s *exportService) ExportProducts(ctx context.Context, w io.Writer, opts entity.ExportOptions, begin func(snapshotAt time.Time)) (*entity.ExportSummary, error) {
+	switch opts.Format {
+	case entity.ExportFormatCSV, entity.ExportFormatJSONL, entity.ExportFormatParquet:
+	default:
+		return nil, ErrUnsupportedExportFormat
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = defaultExportBatchSize
+	}
+
+	hasher := sha256.New()
+	out := io.MultiWriter(w, hasher)
+	summary := &entity.ExportSummary{Format: opts.Format}
+
+	var writer exportWriter
+	start := func(snapshotAt time.Time) {
+		summary.SnapshotAt = snapshotAt
+		if begin != nil {
+			begin(snapshotAt)
+		}
+		writer = newExportWriter(opts.Format, out, snapshotAt)
+	}
+
+	snapshotAt, err := s.productRepo.StreamProducts(ctx, opts.BatchSize, func(snapshotAt time.Time, batch []entity.Product) error {
+		if writer == nil {
+			start(snapshotAt)
+		}
+
+		rows := make([]entity.ExportRow, 0, len(batch))
+		for _, product := range batch {
+			rows = append(rows, productToExportRow(&product))
+		}
+		summary.Rows += len(rows)
+		return writer.WriteRows(rows)
+	})
+	if err != nil {
+		log.Logger.Error().Err(err).Int("rows", summary.Rows).Msg("Product export failed")
+		return nil, err
+	}
+
+	// An empty catalogue still produces a well-formed file.
+	if writer == nil {
+		start(snapshotAt)
+	}
+	if err := writer.Close(); err != nil {
+		log.Logger.Error().Err(err).Msg("Failed to finish product export")
+		return nil, err
+	}
+
+	summary.Checksum = hex.EncodeToString(hasher.Sum(nil))
+	log.Logger.Info().
+		Str("format", string(summary.Format)).
+		Time("snapshotAt", summary.SnapshotAt).
+		Int("rows", summary.Rows).
+		Str("checksum", summary.Checksum).
+		Msg("Product export finished")
+	return summary, nil
+}
+
+func newExportWriter(format entity.ExportFormat, w io.Writer, snapshotAt time.Time) exportWriter {
+	switch format {
+	case entity.ExportFormatCSV:
+		return newCSVExportWriter(w)
+	case entity.ExportFormatJSONL:
+		return newJSONLExportWriter(w)
+	default:
+		return newParquetExportWriter(w, snapshotAt)
+	}
+}
+
+func productToExportRow(product *entity.Product) entity.ExportRow {
+	return entity.ExportRow{
+		ID:          product.ID,
+		ExternalSKU: product.ExternalSKU,
+		Name:        product.Name,
+		Description: product.Description,
+		Price:       product.Price.Decimal(),
+		Currency:    product.Price.Currency,
+		Stock:       int64(product.Stock),
+		CategoryID:  product.CategoryID,
+		Status:      string(product.Status),
+	}
+}
+
+// csvExportWriter writes a header line followed by one line per product.
+type csvExportWriter struct {
+	writer        *csv.Writer
+	headerWritten bool
+}
+
+func newCSVExportWriter(w io.Writer) *csvExportWriter {
+	return &csvExportWriter{writer: csv.NewWriter(w)}
+}
+
+func (c *csvExportWriter) WriteRows(rows []entity.ExportRow) error {
+	if !c.headerWritten {
+		if err := c.writeHeader(); err != nil {
+			return err
+		}
+	}
+
+	for _, row := range rows {
+		record := []string{
+			strconv.FormatInt(row.ID, 10),
+			"",
+			row.Name,
+			row.Description,
+			row.Price,
+			row.Currency,
+			strconv.FormatInt(row.Stock, 10),
+			"",
+			row.Status,
+		}
+		if row.ExternalSKU != nil {
+			record[1] = *row.ExternalSKU
+		}
+		if row.CategoryID != nil {
+			record[7] = strconv.FormatInt(*row.CategoryID, 10)
+		}
+		if err := c.writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	// Flush per batch so rows reach the client while the export is still running.
+	c.writer.Flush()
+	return c.writer.Error()
+}
+
+func (c *csvExportWriter) Close() error {
+	if !c.headerWritten {
+		if err := c.writeHeader(); err != nil {
+			return err
+		}
+	}
+	c.writer.Flush()
+	return c.writer.Error()
+}
+
+func (c *csvExportWriter) writeHeader() error {
+	c.headerWritten = true
+	return c.writer.Write([]string{"id", "external_sku", "name", "description", "price", "currency", "stock", "category_id", "status"})
+}
+
+// jsonlExportWriter writes one JSON object per line.
+type jsonlExportWriter struct {
+	buffer  *bufio.Writer
+	encoder *json.Encoder
+}
+
+func newJSONLExportWriter(w io.Writer) *jsonlExportWriter {
+	buffer := bufio.NewWriter(w)
+	return &jsonlExportWriter{buffer: buffer, encoder: json.NewEncoder(buffer)}
+}
+
+func (j *jsonlExportWriter) WriteRows(rows []entity.ExportRow) error {
+	for i := range rows {
+		if err := j.encoder.Encode(&rows[i]); err != nil {
+			return err
+		}
+	}
+	return j.buffer.Flush()
+}
+
+func (j *jsonlExportWriter) Close() error {
+	return j.buffer.Flush()
+}
+
+// parquetExportWriter writes one row group per batch and records the snapshot time in the file metadata.
+type parquetExportWriter struct {
+	writer *parquet.GenericWriter[entity.ExportRow]
+}
+
+func newParquetExportWriter(w io.Writer, snapshotAt time.Time) *parquetExportWriter {
+	return &parquetExportWriter{
+		writer: parquet.NewGenericWriter[entity.ExportRow](w,
+			parquet.KeyValueMetadata("snapshot_at", snapshotAt.Format(time.RFC3339Nano)),
+		),
+	}
+}
+
+func (p *parquetExportWriter) WriteRows(rows []entity.ExportRow) error {
+	if _, err := p.writer.Write(rows); err != nil {
+		return err
+	}
+	return p.writer.Flush()
+}
+
+func (p *parquetExportWriter) Close() error {
+	return p.writer.Close()
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"product-catalog-service/infrastructure/log"
+	"product-catalog-service/internal/apperror"
+	"product-catalog-service/internal/entity"
+	"product-catalog-service/internal/repository"
+	"time"
+)
+
+var (
+	// ErrIdempotencyInProgress is returned for a retry that arrives while the first request with
+	// the same key is still being handled. It is safe to retry later.
+	ErrIdempotencyInProgress = apperror.New(apperror.KindConflict, "idempotency_in_progress", "a request with this idempotency key is in progress")
+	// ErrIdempotencyKeyReused is returned when a key is sent with a different request than the first.
+	ErrIdempotencyKeyReused = apperror.New(apperror.KindInvalid, "idempotency_key_reused", "idempotency key was used with a different request")
+	// ErrInvalidIdempotencyKey is returned for a key that is empty or too long.
+	ErrInvalidIdempotencyKey = apperror.New(apperror.KindInvalid, "invalid_idempotency_key", "idempotency key must be 1 to 255 characters")
+)
+
+const maxIdempotencyKeyLength = 255
+
+// idempotencyClaimTTL bounds how long a key stays claimed by a request that never completes, e.g.
+// because its instance stopped. It is well above the request timeout.
+const idempotencyClaimTTL = time.Minute
+
+// IdempotencyService makes retried requests safe: the first request with a key is handled and its
+// response kept, and retries with the same key get that response instead of being handled again.
+type IdempotencyService interface {
+	// Begin claims key for the request identified by fingerprint. Keys are unique within scope,
+	// e.g. a client, so that clients cannot collide. It returns the response to replay when the key
+	// has already completed, or nil when the caller is to handle the request and then call Complete
+	// or Abandon.
+	Begin(ctx context.Context, scope, key, fingerprint string) (*entity.IdempotencyRecord, error)
+	// Complete keeps the response of the request that claimed key for later retries.
+	Complete(ctx context.Context, scope, key string, record entity.IdempotencyRecord)
+	// Abandon releases key without a response, so that a retry is handled again.
+	Abandon(ctx context.Context, scope, key string)
+}
+
+type idempotencyService struct {
+	idempotencyRepo repository.IdempotencyRepository
+	ttl             time.Duration
+}
+
+// NewIdempotencyService creates and returns a new instance of idempotencyService. Responses are
+// kept for ttl.
+func NewIdempotencyService(idempotencyRepo repository.IdempotencyRepository, ttl time.Duration) IdempotencyService {
+	return &idempotencyService{
+		idempotencyRepo: idempotencyRepo,
+		ttl:             ttl,
+	}
+}
+
+func (s *idempotencyService) Begin(ctx context.Context, scope, key, fingerprint string) (*entity.IdempotencyRecord, error) {
+	if key == "" || len(key) > maxIdempotencyKeyLength {
+		return nil, ErrInvalidIdempotencyKey
+	}
+	claimed, existing, err := s.idempotencyRepo.ClaimKey(ctx, scope+":"+key, entity.IdempotencyRecord{Fingerprint: fingerprint}, idempotencyClaimTTL)
+	if err != nil {
+		return nil, err
+	}
+	if claimed {
+		return nil, nil
+	}
+	if existing.Fingerprint != fingerprint {
+		return nil, ErrIdempotencyKeyReused
+	}
+	if !existing.Completed {
+		return nil, ErrIdempotencyInProgress
+	}
+	return existing, nil
+}
+
+func (s *idempotencyService) Complete(ctx context.Context, scope, key string, record entity.IdempotencyRecord) {
+	record.Completed = true
+	if err := s.idempotencyRepo.SaveRecord(ctx, scope+":"+key, record, s.ttl); err != nil {
+		// The claim expires on its own; until then retries are told the request is in progress.
+		log.Logger.Error().Err(err).Msg("Failed to save idempotent response")
+	}
+}
+
+func (s *idempotencyService) Abandon(ctx context.Context, scope, key string) {
+	if err := s.idempotencyRepo.DeleteRecord(ctx, scope+":"+key); err != nil {
+		log.Logger.Error().Err(err).Msg("Failed to release idempotency key")
+	}
+}
@@ -0,0 +1,315 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"product-catalog-service/infrastructure/log"
+	"product-catalog-service/internal/apperror"
+	"product-catalog-service/internal/entity"
+	"product-catalog-service/internal/repository"
+	"product-catalog-service/pkg/money"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultImportBatchSize = 500
+	// maxReportedImportErrors caps the row errors kept in a report so huge broken files stay cheap.
+	maxReportedImportErrors = 1000
+	// maxImportLineBytes is the longest JSON Lines record accepted.
+	maxImportLineBytes = 1 << 20
+)
+
+// ErrUnsupportedImportFormat is returned when an import is requested in an unknown format.
+var ErrUnsupportedImportFormat = apperror.New(apperror.KindUnsupported, "unsupported_import_format", "format must be csv or jsonl")
+
+type ImportService interface {
+	ImportProducts(ctx context.Context, r io.Reader, opts entity.ImportOptions, progress func(entity.ImportProgress)) (*entity.ImportReport, error)
+}
+
+type importService struct {
+	productRepo repository.ProductRepository
+	searchRepo  repository.SearchRepository
+}
+
+// NewImportService creates and returns a new instance of importService.
+func NewImportService(productRepo repository.ProductRepository, searchRepo repository.SearchRepository) ImportService {
+	return &importService{
+		productRepo: productRepo,
+		searchRepo:  searchRepo,
+	}
+}
+
+// importRowHandler receives each parsed row with its 1-based row number and any parse errors.
+type importRowHandler func(rowNum int, row *entity.ImportRow, parseErrs []string) error
+
+// ImportProducts reads products from r and upserts them by external SKU in batches, one transaction
+// per batch. Invalid rows are skipped and listed in the report; the rest of the file is still imported.
+// progress, if not nil, is called after every batch.
+// When an error is returned, batches written before the failure stay committed and the report
+// reflects the work done so far.
+func (s *importService) ImportProducts(ctx context.Context, r io.Reader, opts entity.ImportOptions, progress func(entity.ImportProgress)) (*entity.ImportReport, error) {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = defaultImportBatchSize
+	}
+
+	report := &entity.ImportReport{
+		DryRun: opts.DryRun,
+		Errors: []entity.ImportRowError{},
+	}
+	firstRowBySKU := make(map[string]int)
+	batch := make([]entity.Product, 0, opts.BatchSize)
+	rowsSinceProgress := 0
+
+	reportProgress := func() {
+		rowsSinceProgress = 0
+		if progress != nil {
+			progress(entity.ImportProgress{
+				RowsRead:     report.RowsRead,
+				RowsInvalid:  report.RowsInvalid,
+				RowsUpserted: report.RowsUpserted,
+			})
+		}
+	}
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		stored, err := s.productRepo.UpsertProducts(ctx, batch)
+		if err != nil {
+			return err
+		}
+		report.RowsUpserted += len(stored)
+		now := time.Now()
+		for i := range stored {
+			// Imported products start as drafts, so only already-active products are searchable.
+			if !stored[i].IsPurchasable(now) {
+				continue
+			}
+			if err := s.searchRepo.IndexProduct(ctx, &stored[i]); err != nil {
+				log.Logger.Error().Err(err).Int64("productID", stored[i].ID).Msg("Failed to index imported product for search")
+			}
+		}
+		batch = batch[:0]
+		reportProgress()
+		return nil
+	}
+
+	handle := func(rowNum int, row *entity.ImportRow, parseErrs []string) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		report.RowsRead++
+		rowsSinceProgress++
+		errs := append(parseErrs, validateImportRow(row)...)
+		if row.ExternalSKU != "" {
+			if first, ok := firstRowBySKU[row.ExternalSKU]; ok {
+				errs = append(errs, fmt.Sprintf("duplicate external_sku, first seen on row %d", first))
+			} else {
+				firstRowBySKU[row.ExternalSKU] = rowNum
+			}
+		}
+
+		if len(errs) > 0 {
+			report.RowsInvalid++
+			if len(report.Errors) < maxReportedImportErrors {
+				report.Errors = append(report.Errors, entity.ImportRowError{Row: rowNum, ExternalSKU: row.ExternalSKU, Errors: errs})
+			} else {
+				report.ErrorsTruncated = true
+			}
+		} else {
+			report.RowsValid++
+			if !opts.DryRun {
+				batch = append(batch, importRowToProduct(row))
+			}
+		}
+
+		if len(batch) >= opts.BatchSize {
+			return flush()
+		}
+		if rowsSinceProgress >= opts.BatchSize {
+			reportProgress()
+		}
+		return nil
+	}
+
+	var err error
+	switch opts.Format {
+	case entity.ImportFormatCSV:
+		err = readImportCSV(r, handle)
+	case entity.ImportFormatJSONL:
+		err = readImportJSONLines(r, handle)
+	default:
+		return nil, ErrUnsupportedImportFormat
+	}
+	if err == nil {
+		err = flush()
+	}
+	if err != nil {
+		log.Logger.Error().Err(err).Int("rowsRead", report.RowsRead).Int("rowsUpserted", report.RowsUpserted).Msg("Product import failed")
+		return report, err
+	}
+
+	if rowsSinceProgress > 0 {
+		reportProgress()
+	}
+	log.Logger.Info().
+		Bool("dryRun", report.DryRun).
+		Int("rowsRead", report.RowsRead).
+		Int("rowsInvalid", report.RowsInvalid).
+		Int("rowsUpserted", report.RowsUpserted).
+		Msg("Product import finished")
+	return report, nil
+}
+
+// readImportCSV parses a CSV file whose header names the ImportRow columns.
+// external_sku, name, price, currency and stock are required columns; description and category_id are optional.
+func readImportCSV(r io.Reader, handle importRowHandler) error {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	// Trailing optional columns may be omitted, so rows are not required to match the header width.
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"external_sku", "name", "price", "currency", "stock"} {
+		if _, ok := columns[required]; !ok {
+			return fmt.Errorf("CSV header is missing the %q column", required)
+		}
+	}
+
+	for rowNum := 1; ; rowNum++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		row := &entity.ImportRow{}
+		var parseErrs []string
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return fmt.Errorf("failed to read CSV row %d: %w", rowNum, err)
+			}
+			parseErrs = append(parseErrs, parseErr.Err.Error())
+		}
+
+		field := func(name string) string {
+			i, ok := columns[name]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		row.ExternalSKU = field("external_sku")
+		row.Name = field("name")
+		row.Description = field("description")
+		row.Price = json.Number(field("price"))
+		row.Currency = field("currency")
+		if v := field("stock"); v != "" {
+			if row.Stock, err = strconv.Atoi(v); err != nil {
+				parseErrs = append(parseErrs, "stock must be an integer")
+			}
+		}
+		if v := field("category_id"); v != "" {
+			categoryID, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				parseErrs = append(parseErrs, "category_id must be an integer")
+			} else {
+				row.CategoryID = &categoryID
+			}
+		}
+
+		if err := handle(rowNum, row, parseErrs); err != nil {
+			return err
+		}
+	}
+}
+
+// readImportJSONLines parses one ImportRow JSON object per line. Blank lines are skipped but counted.
+func readImportJSONLines(r io.Reader, handle importRowHandler) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxImportLineBytes)
+
+	for rowNum := 1; scanner.Scan(); rowNum++ {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		row := &entity.ImportRow{}
+		var parseErrs []string
+		decoder := json.NewDecoder(bytes.NewReader(line))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(row); err != nil {
+			parseErrs = append(parseErrs, "invalid JSON: "+err.Error())
+		}
+
+		if err := handle(rowNum, row, parseErrs); err != nil {
+			return err
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read JSON Lines input: %w", err)
+	}
+	return nil
+}
+
+// validateImportRow returns the validation errors of a parsed row.
+func validateImportRow(row *entity.ImportRow) []string {
+	var errs []string
+	if row.ExternalSKU == "" {
+		errs = append(errs, "external_sku is required")
+	} else if len(row.ExternalSKU) > 64 {
+		errs = append(errs, "external_sku must be at most 64 characters")
+	}
+	if strings.TrimSpace(row.Name) == "" {
+		errs = append(errs, "name is required")
+	}
+	if row.Price == "" {
+		errs = append(errs, "price is required")
+	} else if price, err := money.Parse(row.Price.String(), row.Currency); errors.Is(err, money.ErrUnknownCurrency) {
+		errs = append(errs, "currency must be a supported ISO 4217 code")
+	} else if err != nil {
+		errs = append(errs, "price must be a decimal amount with at most as many decimals as the currency allows")
+	} else if price.IsNegative() {
+		errs = append(errs, "price must not be negative")
+	}
+	if row.Stock < 0 {
+		errs = append(errs, "stock must not be negative")
+	}
+	return errs
+}
+
+// importRowToProduct converts a row that passed validateImportRow.
+func importRowToProduct(row *entity.ImportRow) entity.Product {
+	sku := row.ExternalSKU
+	price, _ := money.Parse(row.Price.String(), row.Currency)
+	return entity.Product{
+		ExternalSKU: &sku,
+		Name:        row.Name,
+		Description: row.Description,
+		Price:       price,
+		Stock:       row.Stock,
+		CategoryID:  row.CategoryID,
+		// New products wait for review as drafts; the status of existing products is kept.
+		Status: entity.ProductStatusDraft,
+	}
+}
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"product-catalog-service/infrastructure/log"
+	"product-catalog-service/infrastructure/storage"
+	"product-catalog-service/internal/apperror"
+	"product-catalog-service/internal/entity"
+	"product-catalog-service/internal/repository"
+)
+
+var (
+	// ErrUnsupportedMediaType is returned when an upload is not one of the accepted image formats.
+	ErrUnsupportedMediaType = apperror.New(apperror.KindUnsupported, "unsupported_media_type", "only JPEG, PNG, GIF and WebP images are accepted")
+	// ErrMediaTooLarge is returned when an upload exceeds the configured size limit.
+	ErrMediaTooLarge = apperror.New(apperror.KindTooLarge, "media_too_large", "media exceeds maximum upload size")
+	// ErrMediaNotFound is returned when a media does not exist or belongs to another product.
+	ErrMediaNotFound = apperror.New(apperror.KindNotFound, "media_not_found", "media not found")
+	// ErrInvalidMediaOrder is returned when a media order does not list every media of the product exactly once.
+	ErrInvalidMediaOrder = apperror.New(apperror.KindInvalid, "invalid_media_order", "media order must list every media of the product")
+)
+
+// allowedImageTypes maps the accepted image content types to the file extension used in storage.
+var allowedImageTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+type MediaService interface {
+	UploadMedia(ctx context.Context, productID int64, content io.Reader) (*entity.ProductMedia, error)
+	GetMedia(ctx context.Context, productID int64) ([]entity.ProductMedia, error)
+	DeleteMedia(ctx context.Context, productID, mediaID int64) error
+	ReorderMedia(ctx context.Context, productID int64, mediaIDs []int64) error
+}
+
+type mediaService struct {
+	mediaRepo      repository.MediaRepository
+	productRepo    repository.ProductRepository
+	storage        storage.Storage
+	maxUploadBytes int64
+}
+
+// NewMediaService creates and returns a new instance of mediaService.
+func NewMediaService(mediaRepo repository.MediaRepository, productRepo repository.ProductRepository, storage storage.Storage, maxUploadBytes int64) MediaService {
+	return &mediaService{
+		mediaRepo:      mediaRepo,
+		productRepo:    productRepo,
+		storage:        storage,
+		maxUploadBytes: maxUploadBytes,
+	}
+}
+
+// UploadMedia validates an image, stores it and appends it to the media of the product.
+// The content type is sniffed from the data rather than trusted from the client.
+func (s *mediaService) UploadMedia(ctx context.Context, productID int64, content io.Reader) (*entity.ProductMedia, error) {
+	product, err := s.productRepo.GetProductByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if product == nil {
+		log.Logger.Warn().Int64("productID", productID).Msg("Product not found for media upload")
+		return nil, ErrProductNotFound
+	}
+
+	reader := bufio.NewReaderSize(content, 512)
+	head, err := reader.Peek(512)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to read upload: %w", err)
+	}
+	contentType := http.DetectContentType(head)
+	extension, ok := allowedImageTypes[contentType]
+	if !ok {
+		log.Logger.Warn().Int64("productID", productID).Str("contentType", contentType).Msg("Rejected media upload")
+		return nil, ErrUnsupportedMediaType
+	}
+
+	key, err := newMediaKey(productID, extension)
+	if err != nil {
+		return nil, err
+	}
+
+	// Read one byte past the limit so oversized uploads can be detected and removed.
+	limited := &countingReader{reader: io.LimitReader(reader, s.maxUploadBytes+1)}
+	url, err := s.storage.Save(ctx, key, limited, contentType)
+	if err != nil {
+		log.Logger.Error().Err(err).Int64("productID", productID).Msg("Failed to store media")
+		return nil, err
+	}
+	if limited.count > s.maxUploadBytes {
+		s.deleteStored(ctx, key)
+		return nil, ErrMediaTooLarge
+	}
+
+	media := &entity.ProductMedia{
+		ProductID:   productID,
+		URL:         url,
+		StorageKey:  key,
+		ContentType: contentType,
+		Size:        limited.count,
+	}
+	if err := s.mediaRepo.CreateMedia(ctx, media); err != nil {
+		s.deleteStored(ctx, key)
+		return nil, err
+	}
+	return media, nil
+}
+
+func (s *mediaService) GetMedia(ctx context.Context, productID int64) ([]entity.ProductMedia, error) {
+	mediaByProduct, err := s.mediaRepo.GetMediaByProductIDs(ctx, []int64{productID})
+	if err != nil {
+		return nil, err
+	}
+	media := mediaByProduct[productID]
+	if media == nil {
+		media = []entity.ProductMedia{}
+	}
+	return media, nil
+}
+
+func (s *mediaService) DeleteMedia(ctx context.Context, productID, mediaID int64) error {
+	media, err := s.mediaRepo.GetMediaByID(ctx, mediaID)
+	if err != nil {
+		return err
+	}
+	if media == nil || media.ProductID != productID {
+		log.Logger.Warn().Int64("productID", productID).Int64("mediaID", mediaID).Msg("Media not found for deletion")
+		return ErrMediaNotFound
+	}
+
+	if err := s.mediaRepo.DeleteMedia(ctx, media); err != nil {
+		return err
+	}
+	s.deleteStored(ctx, media.StorageKey)
+	return nil
+}
+
+func (s *mediaService) ReorderMedia(ctx context.Context, productID int64, mediaIDs []int64) error {
+	current, err := s.GetMedia(ctx, productID)
+	if err != nil {
+		return err
+	}
+
+	if len(mediaIDs) != len(current) {
+		return ErrInvalidMediaOrder
+	}
+	known := make(map[int64]bool, len(current))
+	for _, m := range current {
+		known[m.ID] = true
+	}
+	for _, id := range mediaIDs {
+		if !known[id] {
+			return ErrInvalidMediaOrder
+		}
+		delete(known, id)
+	}
+
+	return s.mediaRepo.ReorderMedia(ctx, productID, mediaIDs)
+}
+
+// deleteStored removes a stored file, logging failures since the database is the source of truth.
+func (s *mediaService) deleteStored(ctx context.Context, key string) {
+	if err := s.storage.Delete(ctx, key); err != nil {
+		log.Logger.Error().Err(err).Str("key", key).Msg("Failed to delete stored media")
+	}
+}
+
+// newMediaKey returns a unique storage key for a product image.
+func newMediaKey(productID int64, extension string) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate media key: %w", err)
+	}
+	return fmt.Sprintf("products/%d/%s%s", productID, hex.EncodeToString(b), extension), nil
+}
+
+// countingReader counts the bytes read through it.
+type countingReader struct {
+	reader io.Reader
+	count  int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.count += int64(n)
+	return n, err
+}
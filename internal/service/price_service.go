@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"product-catalog-service/infrastructure/log"
+	"product-catalog-service/internal/apperror"
+	"product-catalog-service/internal/entity"
+	"product-catalog-service/internal/repository"
+	"time"
+)
+
+// ErrPriceNotFound is returned when no price is recorded for a product at the requested time.
+var ErrPriceNotFound = apperror.New(apperror.KindNotFound, "price_not_found", "no price recorded at the requested time")
+
+type PriceService interface {
+	ChangePrice(ctx context.Context, productID int64, req entity.PriceChangeRequest) (*entity.PriceChange, error)
+	GetPriceAt(ctx context.Context, productID int64, at time.Time) (*entity.PriceChange, error)
+	GetPriceHistory(ctx context.Context, productID int64) ([]entity.PriceChange, error)
+	ApplyScheduledPrices(ctx context.Context, now time.Time) (int, error)
+}
+
+type priceService struct {
+	priceRepo   repository.PriceRepository
+	productRepo repository.ProductRepository
+}
+
+// NewPriceService creates and returns a new instance of priceService.
+func NewPriceService(priceRepo repository.PriceRepository, productRepo repository.ProductRepository) PriceService {
+	return &priceService{
+		priceRepo:   priceRepo,
+		productRepo: productRepo,
+	}
+}
+
+// ChangePrice changes the price of a product now or schedules it for a future time. The history
+// is append-only for the past, so effective times before now are rejected.
+func (s *priceService) ChangePrice(ctx context.Context, productID int64, req entity.PriceChangeRequest) (*entity.PriceChange, error) {
+	product, err := s.productRepo.GetProductByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if product == nil {
+		log.Logger.Warn().Int64("productID", productID).Msg("Product not found for price change")
+		return nil, ErrProductNotFound
+	}
+
+	if req.Price.Currency == "" || req.Price.IsNegative() {
+		return nil, fmt.Errorf("%w: a non-negative price with a currency is required", ErrInvalidPrice)
+	}
+	// Variant price overrides are in the product currency, so it cannot change here.
+	if req.Price.Currency != product.Price.Currency {
+		return nil, fmt.Errorf("%w: price must be in %s like the current price", ErrInvalidPrice, product.Price.Currency)
+	}
+
+	now := time.Now()
+	from := now
+	if req.EffectiveFrom != nil {
+		if req.EffectiveFrom.Before(now) {
+			return nil, fmt.Errorf("%w: effective_from must not be in the past", ErrInvalidPrice)
+		}
+		from = *req.EffectiveFrom
+	}
+
+	change, err := s.priceRepo.SchedulePrice(ctx, productID, req.Price, from, now)
+	if err != nil {
+		return nil, err
+	}
+	log.Logger.Info().Int64("productID", productID).Str("price", req.Price.String()).Time("effectiveFrom", change.EffectiveFrom).Msg("Product price changed")
+	return change, nil
+}
+
+func (s *priceService) GetPriceAt(ctx context.Context, productID int64, at time.Time) (*entity.PriceChange, error) {
+	change, err := s.priceRepo.GetPriceAt(ctx, productID, at)
+	if err != nil {
+		return nil, err
+	}
+	if change == nil {
+		log.Logger.Warn().Int64("productID", productID).Time("at", at).Msg("No price recorded at the requested time")
+		return nil, ErrPriceNotFound
+	}
+	return change, nil
+}
+
+func (s *priceService) GetPriceHistory(ctx context.Context, productID int64) ([]entity.PriceChange, error) {
+	return s.priceRepo.GetPriceHistory(ctx, productID)
+}
+
+// ApplyScheduledPrices sets the price of products whose scheduled price has taken effect.
+func (s *priceService) ApplyScheduledPrices(ctx context.Context, now time.Time) (int, error) {
+	productIDs, err := s.priceRepo.ApplyDuePrices(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+	return len(productIDs), nil
+}
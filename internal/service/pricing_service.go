@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"product-catalog-service/infrastructure/log"
+	"product-catalog-service/internal/apperror"
+	"product-catalog-service/internal/entity"
+	"product-catalog-service/internal/repository"
+	"product-catalog-service/pkg/money"
+	"time"
+)
+
+// maxPercent is 100%, the largest discount that can be applied.
+var maxPercent, _ = money.ParsePercent("100")
+
+var (
+	// ErrInvalidQuote is returned when a quote request is malformed, e.g. an empty line list or a negative quantity.
+	ErrInvalidQuote = apperror.New(apperror.KindInvalid, "invalid_quote", "invalid price quote request")
+	// ErrPriceMismatch is returned when an order line price differs from the computed price beyond the tolerance.
+	ErrPriceMismatch = apperror.New(apperror.KindConflict, "price_mismatch", "order price does not match the current price")
+)
+
+type PricingService interface {
+	QuotePrices(ctx context.Context, req entity.PriceQuoteRequest) (*entity.PriceQuote, error)
+	VerifyOrderPrices(ctx context.Context, order *entity.Order) error
+}
+
+type pricingService struct {
+	productRepo repository.ProductRepository
+	variantRepo repository.VariantRepository
+	currencySvc CurrencyService
+	// tolerance is the largest accepted difference, in minor units, between a submitted and a computed unit price.
+	tolerance int64
+}
+
+// NewPricingService creates and returns a new instance of pricingService.
+func NewPricingService(productRepo repository.ProductRepository, variantRepo repository.VariantRepository, currencySvc CurrencyService, tolerance int64) PricingService {
+	return &pricingService{
+		productRepo: productRepo,
+		variantRepo: variantRepo,
+		currencySvc: currencySvc,
+		tolerance:   tolerance,
+	}
+}
+
+// QuotePrices computes the authoritative price of each line from the current product or variant price.
+// Lines are priced in the requested currency, or else in the product currency, in which case all
+// lines must be priced in the same currency.
+func (s *pricingService) QuotePrices(ctx context.Context, req entity.PriceQuoteRequest) (*entity.PriceQuote, error) {
+	if len(req.Lines) == 0 {
+		return nil, fmt.Errorf("%w: at least one line is required", ErrInvalidQuote)
+	}
+
+	quote := &entity.PriceQuote{Lines: make([]entity.PricedLine, 0, len(req.Lines))}
+	for i, line := range req.Lines {
+		priced, err := s.priceLine(ctx, line, req.Currency)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+
+		if i == 0 {
+			quote.Total = money.New(0, priced.LineTotal.Currency)
+		}
+		if quote.Total, err = quote.Total.Add(priced.LineTotal); err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidQuote, i+1, err)
+		}
+		quote.Lines = append(quote.Lines, *priced)
+	}
+	return quote, nil
+}
+
+// VerifyOrderPrices checks the FinalPrice of every line of an order, which is a unit price, against
+// the computed price. Lines are priced in the currency of the order total, or of the line itself when
+// the order has no total, so an order cannot mix currencies. The first line outside the tolerance
+// fails the whole order.
+func (s *pricingService) VerifyOrderPrices(ctx context.Context, order *entity.Order) error {
+	for _, req := range order.ProductRequests {
+		currency := order.TotalPrice.Currency
+		if currency == "" {
+			currency = req.FinalPrice.Currency
+		}
+		priced, err := s.priceLine(ctx, entity.PriceQuoteLine{
+			ProductID: req.ProductID,
+			VariantID: req.VariantID,
+			Quantity:  req.Quantity,
+			MarkUp:    req.MarkUp,
+			Discount:  req.Discount,
+		}, currency)
+		if err != nil {
+			return err
+		}
+
+		if req.FinalPrice.Currency != priced.UnitPrice.Currency {
+			log.Logger.Warn().Int64("orderID", order.ID).Int64("productID", req.ProductID).
+				Str("submitted", req.FinalPrice.String()).Str("computed", priced.UnitPrice.String()).
+				Msg("Order price currency mismatch")
+			return fmt.Errorf("%w: product %d is priced in %s, not %q", ErrPriceMismatch, req.ProductID, priced.UnitPrice.Currency, req.FinalPrice.Currency)
+		}
+		diff := req.FinalPrice.Amount - priced.UnitPrice.Amount
+		if diff > s.tolerance || -diff > s.tolerance {
+			log.Logger.Warn().Int64("orderID", order.ID).Int64("productID", req.ProductID).Int64("variantID", req.VariantID).
+				Str("submitted", req.FinalPrice.String()).Str("computed", priced.UnitPrice.String()).
+				Msg("Order price outside tolerance")
+			return fmt.Errorf("%w: product %d costs %s, order has %s", ErrPriceMismatch, req.ProductID, priced.UnitPrice, req.FinalPrice)
+		}
+	}
+	return nil
+}
+
+// priceLine applies the markup and then the discount to the current price of a product or variant
+// in currency, or in the product currency when currency is empty.
+func (s *pricingService) priceLine(ctx context.Context, line entity.PriceQuoteLine, currency string) (*entity.PricedLine, error) {
+	if line.Quantity <= 0 {
+		return nil, fmt.Errorf("%w: quantity must be positive", ErrInvalidQuote)
+	}
+	if line.MarkUp < 0 {
+		return nil, fmt.Errorf("%w: markup must not be negative", ErrInvalidQuote)
+	}
+	if line.Discount < 0 || line.Discount > maxPercent {
+		return nil, fmt.Errorf("%w: discount must be between 0 and 100 percent", ErrInvalidQuote)
+	}
+
+	base, err := s.basePrice(ctx, line.ProductID, line.VariantID, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	priced := &entity.PricedLine{PriceQuoteLine: line, BasePrice: base}
+	if priced.MarkUpAmount, err = base.Percentage(line.MarkUp, money.DefaultRounding); err != nil {
+		return nil, err
+	}
+	markedUp, err := base.Add(priced.MarkUpAmount)
+	if err != nil {
+		return nil, err
+	}
+	if priced.DiscountAmount, err = markedUp.Percentage(line.Discount, money.DefaultRounding); err != nil {
+		return nil, err
+	}
+	if priced.UnitPrice, err = markedUp.Sub(priced.DiscountAmount); err != nil {
+		return nil, err
+	}
+	if priced.LineTotal, err = priced.UnitPrice.Mul(line.Quantity); err != nil {
+		return nil, err
+	}
+	return priced, nil
+}
+
+// basePrice returns the current price of a purchasable product, or the price override of its variant,
+// in currency.
+func (s *pricingService) basePrice(ctx context.Context, productID, variantID int64, currency string) (money.Money, error) {
+	product, err := s.productRepo.GetProductByID(ctx, productID)
+	if err != nil {
+		return money.Money{}, err
+	}
+	if product == nil {
+		log.Logger.Warn().Int64("productID", productID).Msg("Product not found for pricing")
+		return money.Money{}, ErrProductNotFound
+	}
+	if !product.IsPurchasable(time.Now()) {
+		return money.Money{}, ErrProductUnavailable
+	}
+	if variantID == 0 {
+		return s.currencySvc.PriceIn(ctx, product, nil, currency)
+	}
+
+	variant, err := s.variantRepo.GetVariantByID(ctx, variantID)
+	if err != nil {
+		return money.Money{}, err
+	}
+	if variant == nil || variant.ProductID != productID {
+		log.Logger.Warn().Int64("productID", productID).Int64("variantID", variantID).Msg("Variant not found for pricing")
+		return money.Money{}, ErrVariantNotFound
+	}
+	return s.currencySvc.PriceIn(ctx, product, variant, currency)
+}
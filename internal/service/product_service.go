@@ -3,34 +3,95 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
 	"product-catalog-service/infrastructure/log"
+	"product-catalog-service/infrastructure/storage"
+	"product-catalog-service/internal/apperror"
 	"product-catalog-service/internal/entity"
 	"product-catalog-service/internal/repository"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrProductNotFound is returned when a product does not exist or is soft-deleted.
+	ErrProductNotFound = apperror.New(apperror.KindNotFound, "product_not_found", "product not found")
+	// ErrProductUnavailable is returned when a product exists but cannot be purchased, e.g. a draft.
+	ErrProductUnavailable = apperror.New(apperror.KindConflict, "product_unavailable", "product is not available for purchase")
+	// ErrInsufficientStock is returned when a reservation asks for more stock than is available.
+	ErrInsufficientStock = apperror.New(apperror.KindConflict, "insufficient_stock", "insufficient stock for reservation")
+	// ErrVariantRequired is returned for product-level stock changes on a product whose stock is tracked per variant.
+	ErrVariantRequired = apperror.New(apperror.KindInvalid, "variant_required", "product has variants, a variant must be specified")
+	// ErrConcurrentUpdate is returned when a conditional write lost a race with another writer.
+	ErrConcurrentUpdate = apperror.New(apperror.KindConflict, "concurrent_update", "resource was changed concurrently, retry the request")
+	// ErrInvalidProductStatus is returned when a status is unknown or its schedule is inconsistent.
+	ErrInvalidProductStatus = apperror.New(apperror.KindInvalid, "invalid_product_status", "invalid product status")
+	// ErrProductStatusTransition is returned when the lifecycle does not allow the requested status change.
+	ErrProductStatusTransition = apperror.New(apperror.KindConflict, "product_status_transition", "product status transition not allowed")
+	// ErrInvalidPrice is returned when a price is missing, negative or in the wrong currency.
+	ErrInvalidPrice = apperror.New(apperror.KindInvalid, "invalid_price", "invalid price")
+	// ErrCartUnavailable is returned when any line of a cart reservation cannot be reserved; no line is reserved then.
+	ErrCartUnavailable = apperror.New(apperror.KindConflict, "cart_unavailable", "cart cannot be reserved")
+	// ErrReservationNotFound is returned when a cart reservation does not exist.
+	ErrReservationNotFound = apperror.New(apperror.KindNotFound, "reservation_not_found", "reservation not found")
+	// ErrReservationConfirmed is returned when releasing a reservation whose order has been placed.
+	ErrReservationConfirmed = apperror.New(apperror.KindConflict, "reservation_confirmed", "reservation is confirmed and cannot be released")
+	// ErrReservationReleased is returned when confirming a reservation whose stock has been returned.
+	ErrReservationReleased = apperror.New(apperror.KindConflict, "reservation_released", "reservation is released and cannot be confirmed")
 )
 
 type ProductService interface {
 	GetProductStock(ctx context.Context, productID int64) (int, error)
+	GetProductStocks(ctx context.Context, productIDs []int64) (*entity.StockBatch, error)
 	ReserveProductStock(ctx context.Context, productID int64, quantity int) (bool, error)
 	ReleaseProductStock(ctx context.Context, productID int64, quantity int) (bool, error)
-	GetAllProducts(ctx context.Context) ([]entity.Product, error)
+	AdjustProductStock(ctx context.Context, productID int64, delta int) (int, error)
+	ReserveVariantStock(ctx context.Context, productID, variantID int64, quantity int) (bool, error)
+	ReleaseVariantStock(ctx context.Context, productID, variantID int64, quantity int) (bool, error)
+	ReserveCart(ctx context.Context, lines []entity.StockReservation) (*entity.CartReservation, []entity.LineAvailability, error)
+	ReserveOrder(ctx context.Context, orderID int64, lines []entity.StockReservation) (*entity.CartReservation, []entity.LineAvailability, error)
+	GetReservation(ctx context.Context, reservationID string) (*entity.CartReservation, error)
+	ReleaseReservation(ctx context.Context, reservationID string) (*entity.CartReservation, error)
+	ConfirmReservation(ctx context.Context, reservationID string) (*entity.CartReservation, error)
+	GetAllProducts(ctx context.Context, filter entity.ProductFilter, currency string) ([]entity.Product, error)
 	CreateProduct(ctx context.Context, product *entity.Product) error
+	SearchProducts(ctx context.Context, query string, limit int, currency string) ([]entity.ProductSearchResult, error)
+	DeleteProduct(ctx context.Context, productID int64) error
+	RestoreProduct(ctx context.Context, productID int64) (*entity.Product, error)
+	ArchiveDeletedProducts(ctx context.Context, retention time.Duration, batchSize int) (int64, error)
+	ChangeProductStatus(ctx context.Context, productID int64, change entity.ProductStatusChange) (*entity.Product, error)
+	ApplyProductSchedules(ctx context.Context, now time.Time) (published, unpublished int, err error)
 }
 
 type productService struct {
-	productRepo repository.ProductRepository
+	productRepo     repository.ProductRepository
+	searchRepo      repository.SearchRepository
+	tagRepo         repository.TagRepository
+	variantRepo     repository.VariantRepository
+	mediaRepo       repository.MediaRepository
+	reservationRepo repository.ReservationRepository
+	currencySvc     CurrencyService
+	stockStream     StockStreamService
+	mediaStorage    storage.Storage
 }
 
 // NewProductService creates and returns a new instance of productService.
-func NewProductService(productRepo repository.ProductRepository) ProductService {
+func NewProductService(productRepo repository.ProductRepository, searchRepo repository.SearchRepository, tagRepo repository.TagRepository, variantRepo repository.VariantRepository, mediaRepo repository.MediaRepository, reservationRepo repository.ReservationRepository, currencySvc CurrencyService, stockStream StockStreamService, mediaStorage storage.Storage) ProductService {
 	return &productService{
-		productRepo: productRepo,
+		productRepo:     productRepo,
+		searchRepo:      searchRepo,
+		tagRepo:         tagRepo,
+		variantRepo:     variantRepo,
+		mediaRepo:       mediaRepo,
+		reservationRepo: reservationRepo,
+		currencySvc:     currencySvc,
+		stockStream:     stockStream,
+		mediaStorage:    mediaStorage,
 	}
 }
 
 func (p *productService) GetProductStock(ctx context.Context, productID int64) (int, error) {
-	// This is a placeholder implementation.
-	// In a real application, this method would interact with a database or other data source.
-	// For now, we return a fixed stock value and no error.
 	productDetail, err := p.productRepo.GetProductByID(ctx, productID)
 	if err != nil {
 		log.Logger.Error().Err(err).Int64("productID", productID).Msg("Failed to get product stock")
@@ -39,7 +100,7 @@ func (p *productService) GetProductStock(ctx context.Context, productID int64) (
 
 	if productDetail == nil {
 		log.Logger.Warn().Int64("productID", productID).Msg("Product not found")
-		return 0, errors.New("product not found")
+		return 0, ErrProductNotFound
 	}
 
 	if productDetail.Stock < 0 {
@@ -50,10 +111,38 @@ func (p *productService) GetProductStock(ctx context.Context, productID int64) (
 	return productDetail.Stock, nil
 }
 
+// GetProductStocks returns the stock of several products, listing the IDs of unknown products separately.
+func (p *productService) GetProductStocks(ctx context.Context, productIDs []int64) (*entity.StockBatch, error) {
+	seen := make(map[int64]bool, len(productIDs))
+	ids := make([]int64, 0, len(productIDs))
+	for _, id := range productIDs {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	products, err := p.productRepo.GetProductsByIDs(ctx, ids)
+	if err != nil {
+		log.Logger.Error().Err(err).Int("count", len(ids)).Msg("Failed to get product stocks")
+		return nil, err
+	}
+
+	batch := &entity.StockBatch{Stock: make(map[int64]int, len(products)), UnknownIDs: []int64{}}
+	for _, id := range ids {
+		product, ok := products[id]
+		if !ok {
+			batch.UnknownIDs = append(batch.UnknownIDs, id)
+			continue
+		}
+		batch.Stock[id] = product.Stock
+	}
+	return batch, nil
+}
+
+// ReserveProductStock takes quantity from the stock of a product. The product read here may be
+// stale, so the stock is taken with a conditional update rather than by writing the product back.
 func (p *productService) ReserveProductStock(ctx context.Context, productID int64, quantity int) (bool, error) {
-	// This is a placeholder implementation.
-	// In a real application, this method would interact with a database or other data source.
-	// For now, we assume the reservation is always successful and return true with no error.
 	productDetail, err := p.productRepo.GetProductByID(ctx, productID)
 	if err != nil {
 		log.Logger.Error().Err(err).Int64("productID", productID).Msg("Failed to reserve product stock")
@@ -62,22 +151,35 @@ func (p *productService) ReserveProductStock(ctx context.Context, productID int6
 
 	if productDetail == nil {
 		log.Logger.Warn().Int64("productID", productID).Msg("Product not found for reservation")
-		return false, errors.New("product not found")
+		return false, ErrProductNotFound
 	}
 
-	if productDetail.Stock < quantity {
-		log.Logger.Warn().Int64("productID", productID).Int("quantity", quantity).Msg("Insufficient stock for reservation")
-		return false, errors.New("insufficient stock for reservation")
+	if !productDetail.IsPurchasable(time.Now()) {
+		log.Logger.Warn().Int64("productID", productID).Str("status", string(productDetail.Status)).Msg("Product is not available for reservation")
+		return false, ErrProductUnavailable
+	}
+
+	if err := p.ensureNoVariants(ctx, productID); err != nil {
+		return false, err
 	}
-	productDetail.Stock -= quantity
-	_, err = p.productRepo.UpdateProduct(ctx, productDetail)
+
+	stock, reserved, err := p.productRepo.AdjustProductStock(ctx, productID, -quantity)
 	if err != nil {
 		log.Logger.Error().Err(err).Int64("productID", productID).Msg("Failed to update product stock after reservation")
 		return false, err
 	}
+	if !reserved {
+		log.Logger.Warn().Int64("productID", productID).Int("quantity", quantity).Msg("Insufficient stock for reservation")
+		return false, ErrInsufficientStock
+	}
+	productDetail.Stock = stock
+	p.indexProduct(ctx, productDetail)
+	p.stockStream.PublishStock(ctx, productID, stock)
 	return true, nil
 }
 
+// ReleaseProductStock returns quantity to the stock of a product with a conditional update, like
+// ReserveProductStock.
 func (p *productService) ReleaseProductStock(ctx context.Context, productID int64, quantity int) (bool, error) {
 	productDetail, err := p.productRepo.GetProductByID(ctx, productID)
 	if err != nil {
@@ -87,28 +189,612 @@ func (p *productService) ReleaseProductStock(ctx context.Context, productID int6
 
 	if productDetail == nil {
 		log.Logger.Warn().Int64("productID", productID).Msg("Product not found for stock release")
-		return false, errors.New("product not found")
+		return false, ErrProductNotFound
+	}
+
+	if err := p.ensureNoVariants(ctx, productID); err != nil {
+		return false, err
 	}
 
-	productDetail.Stock += quantity
-	_, err = p.productRepo.UpdateProduct(ctx, productDetail)
+	stock, released, err := p.productRepo.AdjustProductStock(ctx, productID, quantity)
 	if err != nil {
 		log.Logger.Error().Err(err).Int64("productID", productID).Msg("Failed to update product stock after release")
 		return false, err
 	}
+	if !released {
+		// The product was deleted since it was read.
+		log.Logger.Warn().Int64("productID", productID).Msg("Product not found for stock release")
+		return false, ErrProductNotFound
+	}
+	productDetail.Stock = stock
+	p.indexProduct(ctx, productDetail)
+	p.stockStream.PublishStock(ctx, productID, stock)
 	return true, nil
 }
 
-func (p *productService) GetAllProducts(ctx context.Context) ([]entity.Product, error) {
-	products, err := p.productRepo.GetProducts(ctx)
+// AdjustProductStock corrects the stock of a product by delta and returns the new stock. Products
+// with variants are adjusted per variant instead.
+func (p *productService) AdjustProductStock(ctx context.Context, productID int64, delta int) (int, error) {
+	product, err := p.productRepo.GetProductByID(ctx, productID)
+	if err != nil {
+		return 0, err
+	}
+	if product == nil {
+		log.Logger.Warn().Int64("productID", productID).Msg("Product not found for stock adjustment")
+		return 0, ErrProductNotFound
+	}
+	if err := p.ensureNoVariants(ctx, productID); err != nil {
+		return 0, err
+	}
+
+	stock, adjusted, err := p.productRepo.AdjustProductStock(ctx, productID, delta)
+	if err != nil {
+		return 0, err
+	}
+	if !adjusted {
+		log.Logger.Warn().Int64("productID", productID).Int("delta", delta).Msg("Stock adjustment would make stock negative")
+		return 0, fmt.Errorf("%w: adjustment would make stock negative", ErrInsufficientStock)
+	}
+	log.Logger.Info().Int64("productID", productID).Int("delta", delta).Int("stock", stock).Msg("Product stock adjusted")
+
+	product.Stock = stock
+	p.indexProduct(ctx, product)
+	p.stockStream.PublishStock(ctx, productID, stock)
+	return stock, nil
+}
+
+func (p *productService) ReserveVariantStock(ctx context.Context, productID, variantID int64, quantity int) (bool, error) {
+	variant, product, err := p.getProductVariant(ctx, productID, variantID)
+	if err != nil {
+		log.Logger.Error().Err(err).Int64("variantID", variantID).Msg("Failed to reserve variant stock")
+		return false, err
+	}
+	if !product.IsPurchasable(time.Now()) {
+		log.Logger.Warn().Int64("productID", product.ID).Str("status", string(product.Status)).Msg("Product is not available for reservation")
+		return false, ErrProductUnavailable
+	}
+
+	isReserved, err := p.variantRepo.AdjustVariantStock(ctx, variant, -quantity)
+	if err != nil {
+		log.Logger.Error().Err(err).Int64("variantID", variantID).Msg("Failed to update variant stock after reservation")
+		return false, err
+	}
+	if !isReserved {
+		log.Logger.Warn().Int64("variantID", variantID).Int("quantity", quantity).Msg("Insufficient variant stock for reservation")
+		return false, ErrInsufficientStock
+	}
+	// The stock of the product is the sum of its variants, and changed with them.
+	p.refreshStock(ctx, []int64{variant.ProductID})
+	return true, nil
+}
+
+func (p *productService) ReleaseVariantStock(ctx context.Context, productID, variantID int64, quantity int) (bool, error) {
+	variant, _, err := p.getProductVariant(ctx, productID, variantID)
+	if err != nil {
+		log.Logger.Error().Err(err).Int64("variantID", variantID).Msg("Failed to release variant stock")
+		return false, err
+	}
+
+	isReleased, err := p.variantRepo.AdjustVariantStock(ctx, variant, quantity)
+	if err != nil {
+		log.Logger.Error().Err(err).Int64("variantID", variantID).Msg("Failed to update variant stock after release")
+		return false, err
+	}
+	if isReleased {
+		p.refreshStock(ctx, []int64{variant.ProductID})
+	}
+	return isReleased, nil
+}
+
+// ReserveCart reserves every line of a cart under one reservation ID, or none of them. Repeated
+// lines for the same product or variant are merged. When the cart is rejected, the availability of
+// every line is returned with ErrCartUnavailable.
+func (p *productService) ReserveCart(ctx context.Context, requested []entity.StockReservation) (*entity.CartReservation, []entity.LineAvailability, error) {
+	return p.reserveCart(ctx, uuid.NewString(), requested)
+}
+
+// ReserveOrder reserves the lines of an order like ReserveCart, under the reservation ID of the
+// order. Reserving an order again returns its existing reservation, so a redelivered order message
+// does not take the stock twice.
+func (p *productService) ReserveOrder(ctx context.Context, orderID int64, requested []entity.StockReservation) (*entity.CartReservation, []entity.LineAvailability, error) {
+	reservationID := entity.OrderReservationID(orderID)
+	existing, err := p.reservationRepo.GetReservation(ctx, reservationID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if existing != nil {
+		log.Logger.Info().Int64("orderID", orderID).Msg("Order already reserved")
+		return existing, nil, nil
+	}
+
+	// A concurrent delivery of the same order may still win the insert of the reservation.
+	reservation, availability, err := p.reserveCart(ctx, reservationID, requested)
+	if errors.Is(err, repository.ErrDuplicate) {
+		log.Logger.Info().Int64("orderID", orderID).Msg("Order already reserved")
+		reservation, err = p.GetReservation(ctx, reservationID)
+		return reservation, nil, err
+	}
+	return reservation, availability, err
+}
+
+func (p *productService) reserveCart(ctx context.Context, reservationID string, requested []entity.StockReservation) (*entity.CartReservation, []entity.LineAvailability, error) {
+	lines := mergeReservationLines(requested)
+	productIDs := reservationProductIDs(lines)
+	products, err := p.productRepo.GetProductsByIDs(ctx, productIDs)
+	if err != nil {
+		log.Logger.Error().Err(err).Msg("Failed to load products for cart reservation")
+		return nil, nil, err
+	}
+
+	// Lines that can never be reserved are rejected up front. Stock is only checked for reporting
+	// here: the decision is taken on the locked rows when the stock is taken.
+	now := time.Now()
+	availability := make([]entity.LineAvailability, 0, len(lines))
+	rejected := false
+	for _, line := range lines {
+		line := entity.LineAvailability{ProductID: line.ProductID, VariantID: line.VariantID, Requested: line.Quantity}
+		product, ok := products[line.ProductID]
+		switch {
+		case !ok:
+			line.Reason = entity.LineProductNotFound
+		case !product.IsPurchasable(now):
+			line.Reason = entity.LineProductUnavailable
+		case line.VariantID != 0:
+			variant, err := p.variantRepo.GetVariantByID(ctx, line.VariantID)
+			if err != nil {
+				return nil, nil, err
+			}
+			if variant == nil || variant.ProductID != line.ProductID {
+				line.Reason = entity.LineVariantNotFound
+			} else {
+				line.Available = variant.Stock
+			}
+		default:
+			count, err := p.variantRepo.CountVariants(ctx, line.ProductID)
+			if err != nil {
+				return nil, nil, err
+			}
+			if count > 0 {
+				line.Reason = entity.LineVariantRequired
+			} else {
+				line.Available = product.Stock
+			}
+		}
+		if line.Reason != "" {
+			rejected = true
+		} else if line.Available < line.Requested {
+			line.Reason = entity.LineInsufficientStock
+		}
+		availability = append(availability, line)
+	}
+	if rejected {
+		log.Logger.Warn().Int("lines", len(lines)).Msg("Cart contains lines that cannot be reserved")
+		return nil, availability, ErrCartUnavailable
+	}
+
+	reservation := &entity.CartReservation{
+		ID:        reservationID,
+		Status:    entity.ReservationReserved,
+		Lines:     lines,
+		CreatedAt: now,
+	}
+	availability, reserved, err := p.reservationRepo.ReserveCart(ctx, reservation)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !reserved {
+		log.Logger.Warn().Int("lines", len(lines)).Msg("Insufficient stock for cart reservation")
+		return nil, availability, ErrCartUnavailable
+	}
+	log.Logger.Info().Str("reservationID", reservation.ID).Int("lines", len(lines)).Msg("Cart reserved")
+
+	p.refreshStock(ctx, productIDs)
+	return reservation, nil, nil
+}
+
+func (p *productService) GetReservation(ctx context.Context, reservationID string) (*entity.CartReservation, error) {
+	reservation, err := p.reservationRepo.GetReservation(ctx, reservationID)
+	if err != nil {
+		return nil, err
+	}
+	if reservation == nil {
+		return nil, ErrReservationNotFound
+	}
+	return reservation, nil
+}
+
+// ReleaseReservation returns the stock of a cart reservation. Releasing a reservation that was
+// already released has no effect, so clients can safely retry.
+func (p *productService) ReleaseReservation(ctx context.Context, reservationID string) (*entity.CartReservation, error) {
+	reservation, released, err := p.reservationRepo.ReleaseReservation(ctx, reservationID)
+	if err != nil {
+		return nil, err
+	}
+	if reservation == nil {
+		log.Logger.Warn().Str("reservationID", reservationID).Msg("Reservation not found for release")
+		return nil, ErrReservationNotFound
+	}
+	if reservation.Status == entity.ReservationConfirmed {
+		log.Logger.Warn().Str("reservationID", reservationID).Msg("Confirmed reservation cannot be released")
+		return nil, ErrReservationConfirmed
+	}
+	if released {
+		log.Logger.Info().Str("reservationID", reservationID).Msg("Cart reservation released")
+		p.refreshStock(ctx, reservationProductIDs(reservation.Lines))
+	}
+	return reservation, nil
+}
+
+// ConfirmReservation makes a cart reservation final once its order is placed. Confirming it again
+// has no effect.
+func (p *productService) ConfirmReservation(ctx context.Context, reservationID string) (*entity.CartReservation, error) {
+	reservation, err := p.reservationRepo.ConfirmReservation(ctx, reservationID)
+	if err != nil {
+		return nil, err
+	}
+	if reservation == nil {
+		log.Logger.Warn().Str("reservationID", reservationID).Msg("Reservation not found for confirmation")
+		return nil, ErrReservationNotFound
+	}
+	if reservation.Status == entity.ReservationReleased {
+		log.Logger.Warn().Str("reservationID", reservationID).Msg("Released reservation cannot be confirmed")
+		return nil, ErrReservationReleased
+	}
+	return reservation, nil
+}
+
+// GetAllProducts lists the products visible to shoppers, so only active ones are returned.
+// Prices are returned in currency when it is set.
+func (p *productService) GetAllProducts(ctx context.Context, filter entity.ProductFilter, currency string) ([]entity.Product, error) {
+	filter.ActiveOnly = true
+	products, err := p.productRepo.GetProducts(ctx, filter)
 	if err != nil {
 		log.Logger.Error().Err(err).Msg("Failed to get all products")
 		return nil, err
 	}
 
+	if err := p.enrichProducts(ctx, products); err != nil {
+		return nil, err
+	}
+	if err := p.currencySvc.LocalizeProducts(ctx, products, currency); err != nil {
+		log.Logger.Warn().Err(err).Str("currency", currency).Msg("Failed to localize product prices")
+		return nil, err
+	}
+
 	return products, nil
 }
 
+// CreateProduct creates a product as a draft unless another initial status is requested.
 func (p *productService) CreateProduct(ctx context.Context, product *entity.Product) error {
-	return p.productRepo.CreateProduct(ctx, product)
+	if product.Price.Currency == "" || product.Price.IsNegative() {
+		log.Logger.Warn().Str("price", product.Price.String()).Msg("Invalid product price")
+		return fmt.Errorf("%w: a non-negative price with a currency is required", ErrInvalidPrice)
+	}
+	if product.Status == "" {
+		product.Status = entity.ProductStatusDraft
+	}
+	err := validateProductSchedule(entity.ProductStatusChange{
+		Status:      product.Status,
+		PublishAt:   product.PublishAt,
+		UnpublishAt: product.UnpublishAt,
+	}, time.Now())
+	if err != nil {
+		log.Logger.Warn().Err(err).Str("status", string(product.Status)).Msg("Invalid product status")
+		return err
+	}
+
+	err = p.productRepo.CreateProduct(ctx, product)
+	if err != nil {
+		return err
+	}
+
+	p.indexProduct(ctx, product)
+	return nil
+}
+
+func (p *productService) SearchProducts(ctx context.Context, query string, limit int, currency string) ([]entity.ProductSearchResult, error) {
+	results, err := p.searchRepo.Search(ctx, query, limit)
+	if err != nil {
+		log.Logger.Error().Err(err).Str("query", query).Msg("Failed to search products")
+		return nil, err
+	}
+
+	// The index may lag behind an unpublish time that has just passed.
+	now := time.Now()
+	visible := results[:0]
+	for _, result := range results {
+		if result.Product.IsPurchasable(now) {
+			visible = append(visible, result)
+		}
+	}
+	results = visible
+
+	products := make([]entity.Product, 0, len(results))
+	for _, result := range results {
+		products = append(products, result.Product)
+	}
+	if err := p.enrichProducts(ctx, products); err != nil {
+		return nil, err
+	}
+	if err := p.currencySvc.LocalizeProducts(ctx, products, currency); err != nil {
+		log.Logger.Warn().Err(err).Str("currency", currency).Msg("Failed to localize product prices")
+		return nil, err
+	}
+	for i := range results {
+		results[i].Product = products[i]
+	}
+
+	return results, nil
+}
+
+// DeleteProduct soft-deletes a product. It stays referenced by past orders but can no longer be
+// listed, found or reserved until it is restored.
+func (p *productService) DeleteProduct(ctx context.Context, productID int64) error {
+	if err := p.productRepo.DeleteProduct(ctx, productID); err != nil {
+		log.Logger.Error().Err(err).Int64("productID", productID).Msg("Failed to delete product")
+		return err
+	}
+
+	if err := p.searchRepo.RemoveProduct(ctx, productID); err != nil {
+		log.Logger.Error().Err(err).Int64("productID", productID).Msg("Failed to remove product from search index")
+	}
+	return nil
+}
+
+func (p *productService) RestoreProduct(ctx context.Context, productID int64) (*entity.Product, error) {
+	product, err := p.productRepo.RestoreProduct(ctx, productID)
+	if err != nil {
+		log.Logger.Error().Err(err).Int64("productID", productID).Msg("Failed to restore product")
+		return nil, err
+	}
+	if product == nil {
+		log.Logger.Warn().Int64("productID", productID).Msg("Deleted product not found for restore")
+		return nil, fmt.Errorf("%w: no deleted product %d", ErrProductNotFound, productID)
+	}
+
+	p.indexProduct(ctx, product)
+	return product, nil
+}
+
+// ArchiveDeletedProducts archives, in batches, every product soft-deleted more than retention ago,
+// and deletes the stored files of their media.
+func (p *productService) ArchiveDeletedProducts(ctx context.Context, retention time.Duration, batchSize int) (int64, error) {
+	before := time.Now().Add(-retention)
+
+	var total int64
+	for {
+		archived, media, err := p.productRepo.ArchiveDeletedProducts(ctx, before, batchSize)
+		if err != nil {
+			return total, err
+		}
+		total += int64(len(archived))
+		// The archive keeps the storage keys, so a file that fails to delete can be found later.
+		for _, m := range media {
+			if err := p.mediaStorage.Delete(ctx, m.StorageKey); err != nil {
+				log.Logger.Error().Err(err).Int64("productID", m.ProductID).Str("key", m.StorageKey).Msg("Failed to delete stored media of archived product")
+			}
+		}
+		if len(archived) < batchSize {
+			return total, nil
+		}
+	}
+}
+
+// ChangeProductStatus moves a product to another lifecycle status, enforcing the allowed transitions.
+func (p *productService) ChangeProductStatus(ctx context.Context, productID int64, change entity.ProductStatusChange) (*entity.Product, error) {
+	if err := validateProductSchedule(change, time.Now()); err != nil {
+		log.Logger.Warn().Err(err).Int64("productID", productID).Str("status", string(change.Status)).Msg("Invalid product status change")
+		return nil, err
+	}
+
+	product, err := p.productRepo.GetProductByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if product == nil {
+		log.Logger.Warn().Int64("productID", productID).Msg("Product not found for status change")
+		return nil, ErrProductNotFound
+	}
+	if !product.Status.CanTransitionTo(change.Status) {
+		log.Logger.Warn().Int64("productID", productID).Str("from", string(product.Status)).Str("to", string(change.Status)).Msg("Product status transition not allowed")
+		return nil, fmt.Errorf("%w: %s to %s", ErrProductStatusTransition, product.Status, change.Status)
+	}
+
+	updated, err := p.productRepo.UpdateProductStatus(ctx, productID, product.Status, change)
+	if err != nil {
+		return nil, err
+	}
+	if updated == nil {
+		log.Logger.Warn().Int64("productID", productID).Msg("Product status changed concurrently")
+		return nil, fmt.Errorf("%w: product %d status", ErrConcurrentUpdate, productID)
+	}
+
+	p.indexProduct(ctx, updated)
+	return updated, nil
+}
+
+// ApplyProductSchedules publishes scheduled products and archives active products whose time has come.
+func (p *productService) ApplyProductSchedules(ctx context.Context, now time.Time) (int, int, error) {
+	published, err := p.productRepo.PublishDueProducts(ctx, now)
+	if err != nil {
+		return 0, 0, err
+	}
+	p.reindexProducts(ctx, published)
+
+	unpublished, err := p.productRepo.UnpublishDueProducts(ctx, now)
+	if err != nil {
+		return len(published), 0, err
+	}
+	p.reindexProducts(ctx, unpublished)
+
+	return len(published), len(unpublished), nil
+}
+
+// validateProductSchedule checks that a status is known and that its publish and unpublish times make sense.
+func validateProductSchedule(change entity.ProductStatusChange, now time.Time) error {
+	if !change.Status.IsValid() {
+		return fmt.Errorf("%w: unknown status %q", ErrInvalidProductStatus, change.Status)
+	}
+	if change.Status == entity.ProductStatusScheduled {
+		if change.PublishAt == nil {
+			return fmt.Errorf("%w: publish_at is required for scheduled products", ErrInvalidProductStatus)
+		}
+		if !change.PublishAt.After(now) {
+			return fmt.Errorf("%w: publish_at must be in the future", ErrInvalidProductStatus)
+		}
+	} else if change.PublishAt != nil {
+		return fmt.Errorf("%w: publish_at is only allowed for scheduled products", ErrInvalidProductStatus)
+	}
+	if change.UnpublishAt != nil {
+		if !change.UnpublishAt.After(now) {
+			return fmt.Errorf("%w: unpublish_at must be in the future", ErrInvalidProductStatus)
+		}
+		if change.PublishAt != nil && !change.UnpublishAt.After(*change.PublishAt) {
+			return fmt.Errorf("%w: unpublish_at must be after publish_at", ErrInvalidProductStatus)
+		}
+	}
+	return nil
+}
+
+// enrichProducts attaches the tags and image URLs of each product for API responses.
+func (p *productService) enrichProducts(ctx context.Context, products []entity.Product) error {
+	productIDs := make([]int64, 0, len(products))
+	for _, product := range products {
+		productIDs = append(productIDs, product.ID)
+	}
+
+	tagsByProduct, err := p.tagRepo.GetTagsByProductIDs(ctx, productIDs)
+	if err != nil {
+		log.Logger.Error().Err(err).Msg("Failed to get tags for products")
+		return err
+	}
+	mediaByProduct, err := p.mediaRepo.GetMediaByProductIDs(ctx, productIDs)
+	if err != nil {
+		log.Logger.Error().Err(err).Msg("Failed to get media for products")
+		return err
+	}
+
+	for i := range products {
+		products[i].Tags = tagsByProduct[products[i].ID]
+		for _, media := range mediaByProduct[products[i].ID] {
+			products[i].Images = append(products[i].Images, media.URL)
+		}
+	}
+	return nil
+}
+
+// getProductVariant loads a variant with its product and, when productID is set, checks that it
+// belongs to that product.
+func (p *productService) getProductVariant(ctx context.Context, productID, variantID int64) (*entity.Variant, *entity.Product, error) {
+	variant, err := p.variantRepo.GetVariantByID(ctx, variantID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if variant == nil {
+		log.Logger.Warn().Int64("variantID", variantID).Msg("Variant not found")
+		return nil, nil, ErrVariantNotFound
+	}
+	if productID != 0 && variant.ProductID != productID {
+		log.Logger.Warn().Int64("productID", productID).Int64("variantID", variantID).Msg("Variant does not belong to product")
+		return nil, nil, fmt.Errorf("%w: variant %d does not belong to product %d", ErrVariantNotFound, variantID, productID)
+	}
+
+	// Variants of soft-deleted products cannot be reserved or released.
+	product, err := p.productRepo.GetProductByID(ctx, variant.ProductID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if product == nil {
+		log.Logger.Warn().Int64("productID", variant.ProductID).Int64("variantID", variantID).Msg("Product not found for variant")
+		return nil, nil, ErrProductNotFound
+	}
+	return variant, product, nil
+}
+
+// ensureNoVariants rejects product-level stock changes for products whose stock is tracked per variant.
+func (p *productService) ensureNoVariants(ctx context.Context, productID int64) error {
+	count, err := p.variantRepo.CountVariants(ctx, productID)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		log.Logger.Warn().Int64("productID", productID).Msg("Product stock is tracked per variant")
+		return ErrVariantRequired
+	}
+	return nil
+}
+
+// indexProduct keeps the search index in sync with a created or updated product. Only purchasable
+// products are searchable, so any other product is removed from the index.
+// Indexing failures are logged but do not fail the write that triggered them.
+func (p *productService) indexProduct(ctx context.Context, product *entity.Product) {
+	if !product.IsPurchasable(time.Now()) {
+		if err := p.searchRepo.RemoveProduct(ctx, product.ID); err != nil {
+			log.Logger.Error().Err(err).Int64("productID", product.ID).Msg("Failed to remove product from search index")
+		}
+		return
+	}
+	if err := p.searchRepo.IndexProduct(ctx, product); err != nil {
+		log.Logger.Error().Err(err).Int64("productID", product.ID).Msg("Failed to index product for search")
+	}
+}
+
+// refreshStock reindexes and announces the new stock of products whose stock changed.
+func (p *productService) refreshStock(ctx context.Context, productIDs []int64) {
+	products, err := p.productRepo.GetProductsByIDs(ctx, productIDs)
+	if err != nil {
+		log.Logger.Error().Err(err).Msg("Failed to load products after stock change")
+		return
+	}
+	for _, id := range productIDs {
+		if product, ok := products[id]; ok {
+			p.indexProduct(ctx, product)
+			p.stockStream.PublishStock(ctx, id, product.Stock)
+		}
+	}
+}
+
+// mergeReservationLines merges the requested lines for the same product or variant, keeping the
+// order in which they first appear.
+func mergeReservationLines(requested []entity.StockReservation) []entity.ReservationLine {
+	type key struct{ productID, variantID int64 }
+	index := make(map[key]int, len(requested))
+	lines := make([]entity.ReservationLine, 0, len(requested))
+	for _, r := range requested {
+		k := key{r.ProductID, r.VariantID}
+		if i, ok := index[k]; ok {
+			lines[i].Quantity += r.Quantity
+			continue
+		}
+		index[k] = len(lines)
+		lines = append(lines, entity.ReservationLine{ProductID: r.ProductID, VariantID: r.VariantID, Quantity: r.Quantity})
+	}
+	return lines
+}
+
+// reservationProductIDs returns the distinct products of reservation lines.
+func reservationProductIDs(lines []entity.ReservationLine) []int64 {
+	seen := make(map[int64]bool, len(lines))
+	ids := make([]int64, 0, len(lines))
+	for _, line := range lines {
+		if !seen[line.ProductID] {
+			seen[line.ProductID] = true
+			ids = append(ids, line.ProductID)
+		}
+	}
+	return ids
+}
+
+// reindexProducts refreshes the search index entries of products changed in bulk.
+func (p *productService) reindexProducts(ctx context.Context, productIDs []int64) {
+	for _, id := range productIDs {
+		product, err := p.productRepo.GetProductByID(ctx, id)
+		if err != nil {
+			log.Logger.Error().Err(err).Int64("productID", id).Msg("Failed to load product for search indexing")
+			continue
+		}
+		if product != nil {
+			p.indexProduct(ctx, product)
+		}
+	}
 }
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"product-catalog-service/infrastructure/log"
+	"product-catalog-service/internal/entity"
+	"product-catalog-service/internal/repository"
+	"sort"
+	"sync"
+	"time"
+)
+
+// StockStreamService pushes stock changes to the clients of every instance. Changes are published
+// through Redis and relayed by each instance to its own subscribers, so a reservation handled by
+// one instance reaches sale pages streaming from any other.
+type StockStreamService interface {
+	// PublishStock announces the new stock of a product. A failure is logged but does not fail the
+	// stock change, which has already been committed.
+	PublishStock(ctx context.Context, productID int64, stock int)
+	// Subscribe starts receiving the changes to the given products. The subscription must be closed.
+	Subscribe(productIDs []int64) *StockSubscription
+	// Start relays published changes to the subscribers of this instance until ctx is done.
+	Start(ctx context.Context)
+}
+
+type stockStreamService struct {
+	eventRepo repository.StockEventRepository
+	// coalesceWindow is how long changes are collected before they are pushed, so a burst of
+	// reservations becomes one update per product.
+	coalesceWindow time.Duration
+
+	mu          sync.RWMutex
+	subscribers map[int64]map[*StockSubscription]struct{}
+}
+
+// NewStockStreamService creates and returns a new instance of stockStreamService.
+func NewStockStreamService(eventRepo repository.StockEventRepository, coalesceWindow time.Duration) StockStreamService {
+	return &stockStreamService{
+		eventRepo:      eventRepo,
+		coalesceWindow: coalesceWindow,
+		subscribers:    make(map[int64]map[*StockSubscription]struct{}),
+	}
+}
+
+func (s *stockStreamService) PublishStock(ctx context.Context, productID int64, stock int) {
+	update := entity.StockUpdate{ProductID: productID, Stock: stock, UpdatedAt: time.Now()}
+	if err := s.eventRepo.PublishStockUpdate(ctx, update); err != nil {
+		log.Logger.Warn().Err(err).Int64("productID", productID).Msg("Failed to publish stock update")
+	}
+}
+
+func (s *stockStreamService) Subscribe(productIDs []int64) *StockSubscription {
+	sub := &StockSubscription{
+		updates: make(chan []entity.StockUpdate),
+		pending: make(map[int64]entity.StockUpdate),
+		notify:  make(chan struct{}, 1),
+		done:    make(chan struct{}),
+	}
+
+	s.mu.Lock()
+	for _, id := range productIDs {
+		if s.subscribers[id] == nil {
+			s.subscribers[id] = make(map[*StockSubscription]struct{})
+		}
+		s.subscribers[id][sub] = struct{}{}
+	}
+	s.mu.Unlock()
+
+	sub.unsubscribe = func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for _, id := range productIDs {
+			delete(s.subscribers[id], sub)
+			if len(s.subscribers[id]) == 0 {
+				delete(s.subscribers, id)
+			}
+		}
+	}
+	go sub.run(s.coalesceWindow)
+	return sub
+}
+
+func (s *stockStreamService) Start(ctx context.Context) {
+	for update := range s.eventRepo.SubscribeStockUpdates(ctx) {
+		s.mu.RLock()
+		for sub := range s.subscribers[update.ProductID] {
+			sub.offer(update)
+		}
+		s.mu.RUnlock()
+	}
+}
+
+// StockSubscription receives the changes to a set of products. Changes are coalesced per product:
+// while the client is slow to read, newer changes replace older ones instead of queueing up, so a
+// slow client never holds up the others and only ever receives the latest stock.
+type StockSubscription struct {
+	updates     chan []entity.StockUpdate
+	unsubscribe func()
+	closeOnce   sync.Once
+
+	mu      sync.Mutex
+	pending map[int64]entity.StockUpdate
+	notify  chan struct{}
+	done    chan struct{}
+}
+
+// Updates delivers batches of changes, at most one per product and ordered by product ID.
+func (s *StockSubscription) Updates() <-chan []entity.StockUpdate {
+	return s.updates
+}
+
+// Close stops the subscription.
+func (s *StockSubscription) Close() {
+	s.closeOnce.Do(func() {
+		s.unsubscribe()
+		close(s.done)
+	})
+}
+
+// offer records a change without blocking. Updates relayed out of order by different instances
+// are resolved by keeping the most recent one.
+func (s *StockSubscription) offer(update entity.StockUpdate) {
+	s.mu.Lock()
+	if current, ok := s.pending[update.ProductID]; !ok || !update.UpdatedAt.Before(current.UpdatedAt) {
+		s.pending[update.ProductID] = update
+	}
+	s.mu.Unlock()
+
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
+
+// run turns pending changes into batches until the subscription is closed.
+func (s *StockSubscription) run(window time.Duration) {
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-s.notify:
+		}
+
+		if window > 0 {
+			timer := time.NewTimer(window)
+			select {
+			case <-s.done:
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+		}
+
+		batch := s.drain()
+		if len(batch) == 0 {
+			continue
+		}
+		select {
+		case <-s.done:
+			return
+		case s.updates <- batch:
+		}
+	}
+}
+
+func (s *StockSubscription) drain() []entity.StockUpdate {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	batch := make([]entity.StockUpdate, 0, len(s.pending))
+	for id, update := range s.pending {
+		batch = append(batch, update)
+		delete(s.pending, id)
+	}
+	sort.Slice(batch, func(i, j int) bool {
+		return batch[i].ProductID < batch[j].ProductID
+	})
+	return batch
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"product-catalog-service/infrastructure/log"
+	"product-catalog-service/internal/apperror"
+	"product-catalog-service/internal/entity"
+	"product-catalog-service/internal/repository"
+)
+
+var (
+	// ErrVariantNotFound is returned when a variant does not exist or belongs to another product.
+	ErrVariantNotFound = apperror.New(apperror.KindNotFound, "variant_not_found", "variant not found")
+	// ErrInvalidStock is returned when a stock level is negative.
+	ErrInvalidStock = apperror.New(apperror.KindInvalid, "invalid_stock", "variant stock cannot be negative")
+)
+
+type VariantService interface {
+	GetVariants(ctx context.Context, productID int64) ([]entity.Variant, error)
+	GetVariant(ctx context.Context, id int64) (*entity.Variant, error)
+	CreateVariant(ctx context.Context, variant *entity.Variant) error
+	UpdateVariant(ctx context.Context, variant *entity.Variant) error
+	DeleteVariant(ctx context.Context, id int64) error
+}
+
+type variantService struct {
+	variantRepo repository.VariantRepository
+	productRepo repository.ProductRepository
+}
+
+// NewVariantService creates and returns a new instance of variantService.
+func NewVariantService(variantRepo repository.VariantRepository, productRepo repository.ProductRepository) VariantService {
+	return &variantService{
+		variantRepo: variantRepo,
+		productRepo: productRepo,
+	}
+}
+
+func (s *variantService) GetVariants(ctx context.Context, productID int64) ([]entity.Variant, error) {
+	return s.variantRepo.GetVariantsByProductID(ctx, productID)
+}
+
+func (s *variantService) GetVariant(ctx context.Context, id int64) (*entity.Variant, error) {
+	return s.variantRepo.GetVariantByID(ctx, id)
+}
+
+// CreateVariant adds a SKU to a product. Once a product has variants, its stock is the sum
+// of the variant stock and any stock previously set on the product itself is replaced.
+func (s *variantService) CreateVariant(ctx context.Context, variant *entity.Variant) error {
+	product, err := s.productRepo.GetProductByID(ctx, variant.ProductID)
+	if err != nil {
+		return err
+	}
+	if product == nil {
+		log.Logger.Warn().Int64("productID", variant.ProductID).Msg("Product not found for variant creation")
+		return ErrProductNotFound
+	}
+
+	if variant.Stock < 0 {
+		return ErrInvalidStock
+	}
+	if err := validateVariantPrice(variant, product); err != nil {
+		return err
+	}
+	return s.variantRepo.CreateVariant(ctx, variant)
+}
+
+func (s *variantService) UpdateVariant(ctx context.Context, variant *entity.Variant) error {
+	existing, err := s.variantRepo.GetVariantByID(ctx, variant.ID)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		log.Logger.Warn().Int64("variantID", variant.ID).Msg("Variant not found for update")
+		return ErrVariantNotFound
+	}
+
+	if variant.Stock < 0 {
+		return ErrInvalidStock
+	}
+	// A variant cannot be moved to another product.
+	variant.ProductID = existing.ProductID
+
+	if variant.Price != nil {
+		product, err := s.productRepo.GetProductByID(ctx, variant.ProductID)
+		if err != nil {
+			return err
+		}
+		if product == nil {
+			return ErrProductNotFound
+		}
+		if err := validateVariantPrice(variant, product); err != nil {
+			return err
+		}
+	}
+	return s.variantRepo.UpdateVariant(ctx, variant)
+}
+
+func (s *variantService) DeleteVariant(ctx context.Context, id int64) error {
+	variant, err := s.variantRepo.GetVariantByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if variant == nil {
+		log.Logger.Warn().Int64("variantID", id).Msg("Variant not found for deletion")
+		return ErrVariantNotFound
+	}
+	return s.variantRepo.DeleteVariant(ctx, variant)
+}
+
+// validateVariantPrice checks that a price override is non-negative and in the currency of the product.
+func validateVariantPrice(variant *entity.Variant, product *entity.Product) error {
+	if variant.Price == nil {
+		return nil
+	}
+	if variant.Price.IsNegative() {
+		return fmt.Errorf("%w: variant price cannot be negative", ErrInvalidPrice)
+	}
+	if variant.Price.Currency != product.Price.Currency {
+		return fmt.Errorf("%w: variant price must be in %s like its product", ErrInvalidPrice, product.Price.Currency)
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+// Package validation checks requests against the declarative rules in the `validate` struct tags
+// of the entities. It backs Echo's Validator and is shared with the Kafka consumer, so an order
+// line is held to the same rules whichever way it arrives.
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"product-catalog-service/internal/apperror"
+	"product-catalog-service/pkg/money"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// maxPercent is 100%.
+var maxPercent, _ = money.ParsePercent("100")
+
+// ErrValidation is matched by every Error.
+var ErrValidation = apperror.New(apperror.KindInvalid, "validation_failed", "request validation failed")
+
+// FieldError is a rule broken by one field. Field is the JSON path of the field, e.g. "price" or
+// "lines[1].quantity", and Rule is the name of the rule, e.g. "required" or "gt".
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// Error lists every field that failed validation.
+type Error struct {
+	Fields []FieldError
+}
+
+func (e *Error) Error() string {
+	messages := make([]string, 0, len(e.Fields))
+	for _, field := range e.Fields {
+		messages = append(messages, field.Field+" "+field.Message)
+	}
+	return ErrValidation.Message + ": " + strings.Join(messages, "; ")
+}
+
+func (e *Error) Unwrap() error {
+	return ErrValidation
+}
+
+// Validator validates structs with go-playground/validator. It implements echo.Validator.
+type Validator struct {
+	validate *validator.Validate
+}
+
+// New creates a Validator that reports fields by their JSON names and knows two rules of its own:
+// "money" requires a money.Money with a supported currency and a non-negative amount, and
+// "percent" requires a money.Percent between 0 and 100 percent.
+func New() *Validator {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return field.Name
+		}
+		return name
+	})
+	if err := validate.RegisterValidation("money", validateMoney); err != nil {
+		panic(err)
+	}
+	if err := validate.RegisterValidation("percent", validatePercent); err != nil {
+		panic(err)
+	}
+
+	return &Validator{validate: validate}
+}
+
+// Validate checks i, which must be a struct or a pointer to one, and returns an *Error listing the
+// fields that break their rules.
+func (v *Validator) Validate(i interface{}) error {
+	err := v.validate.Struct(i)
+	if err == nil {
+		return nil
+	}
+
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return err
+	}
+	fields := make([]FieldError, 0, len(validationErrs))
+	for _, fieldErr := range validationErrs {
+		fields = append(fields, FieldError{
+			Field:   fieldPath(fieldErr.Namespace()),
+			Rule:    fieldErr.Tag(),
+			Message: message(fieldErr),
+		})
+	}
+	return &Error{Fields: fields}
+}
+
+func validateMoney(fl validator.FieldLevel) bool {
+	m, ok := fl.Field().Interface().(money.Money)
+	if !ok {
+		return false
+	}
+	if _, err := money.NormalizeCurrency(m.Currency); err != nil {
+		return false
+	}
+	return !m.IsNegative()
+}
+
+func validatePercent(fl validator.FieldLevel) bool {
+	p, ok := fl.Field().Interface().(money.Percent)
+	return ok && p >= 0 && p <= maxPercent
+}
+
+// fieldPath drops the name of the top-level struct from a namespace such as "Product.price".
+func fieldPath(namespace string) string {
+	if _, path, ok := strings.Cut(namespace, "."); ok {
+		return path
+	}
+	return namespace
+}
+
+// message describes a broken rule in words.
+func message(fieldErr validator.FieldError) string {
+	switch fieldErr.Tag() {
+	case "required":
+		return "is required"
+	case "gt":
+		return fmt.Sprintf("must be greater than %s", fieldErr.Param())
+	case "gte":
+		return fmt.Sprintf("must be at least %s", fieldErr.Param())
+	case "lte":
+		return fmt.Sprintf("must be at most %s", fieldErr.Param())
+	case "min":
+		if fieldErr.Kind() == reflect.Slice {
+			return fmt.Sprintf("must have at least %s items", fieldErr.Param())
+		}
+		return fmt.Sprintf("must be at least %s characters long", fieldErr.Param())
+	case "max":
+		if fieldErr.Kind() == reflect.Slice {
+			return fmt.Sprintf("must have at most %s items", fieldErr.Param())
+		}
+		return fmt.Sprintf("must be at most %s characters long", fieldErr.Param())
+	case "oneof":
+		return fmt.Sprintf("must be one of: %s", fieldErr.Param())
+	case "money":
+		return "must be a non-negative amount with a supported currency"
+	case "percent":
+		return "must be between 0 and 100 percent"
+	default:
+		return fmt.Sprintf("breaks the %q rule", fieldErr.Tag())
+	}
+}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Deprecated marks the responses of a deprecated route with the Deprecation (RFC 9745) and Sunset
+// (RFC 8594) headers and links the same request under successorPrefix, the version that replaces
+// the route. A zero sunset leaves the Sunset header out.
+func Deprecated(deprecatedAt, sunset time.Time, successorPrefix string) echo.MiddlewareFunc {
+	deprecation := fmt.Sprintf("@%d", deprecatedAt.Unix())
+	sunsetHeader := ""
+	if !sunset.IsZero() {
+		sunsetHeader = sunset.UTC().Format(http.TimeFormat)
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			header := c.Response().Header()
+			header.Set("Deprecation", deprecation)
+			if sunsetHeader != "" {
+				header.Set("Sunset", sunsetHeader)
+			}
+			successor := successorPrefix + c.Request().URL.EscapedPath()
+			if query := c.Request().URL.RawQuery; query != "" {
+				successor += "?" + query
+			}
+			header.Add("Link", fmt.Sprintf(`<%s>; rel="successor-version"`, successor))
+			return next(c)
+		}
+	}
+}
@@ -3,21 +3,49 @@ package msgBroker
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"product-catalog-service/infrastructure/log"
 	"product-catalog-service/internal/entity"
 	"product-catalog-service/internal/service"
+	"product-catalog-service/internal/validation"
+	"product-catalog-service/pkg/orderhash"
+	"strconv"
 	"strings"
 
 	"github.com/segmentio/kafka-go"
 )
 
+// Headers added to dead-lettered messages.
+const (
+	headerDeadLetterReason    = "dead-letter-reason"
+	headerDeadLetterTopic     = "dead-letter-source-topic"
+	headerDeadLetterPartition = "dead-letter-source-partition"
+	headerDeadLetterOffset    = "dead-letter-source-offset"
+)
+
 type MsgConsumer struct {
 	productSvc service.ProductService
+	pricingSvc service.PricingService
+	keyring    *orderhash.Keyring
+	deadLetter *kafka.Writer
+	validator  *validation.Validator
+	// legacyPriceCurrency is the currency of prices sent as bare numbers; empty rejects them.
+	legacyPriceCurrency string
+	// lineReleaseBeforeOrderID is the first order reserved as a whole; earlier orders are released
+	// line by line when cancelled. Zero releases no order line by line.
+	lineReleaseBeforeOrderID int64
 }
 
-func NewMsgConsumer(productSvc service.ProductService) *MsgConsumer {
+func NewMsgConsumer(productSvc service.ProductService, pricingSvc service.PricingService, keyring *orderhash.Keyring, deadLetter *kafka.Writer, validator *validation.Validator, legacyPriceCurrency string, lineReleaseBeforeOrderID int64) *MsgConsumer {
 	return &MsgConsumer{
-		productSvc: productSvc,
+		productSvc:               productSvc,
+		pricingSvc:               pricingSvc,
+		keyring:                  keyring,
+		deadLetter:               deadLetter,
+		validator:                validator,
+		legacyPriceCurrency:      legacyPriceCurrency,
+		lineReleaseBeforeOrderID: lineReleaseBeforeOrderID,
 	}
 }
 
@@ -45,38 +73,166 @@ func (c *MsgConsumer) StartConsumer(brokers []string, topic string, groupID stri
 }
 
 func (c *MsgConsumer) processMessage(ctx context.Context, msg kafka.Message) {
-	var order *entity.Order
-	err := json.Unmarshal(msg.Value, &order)
+	value, legacy, err := upgradeLegacyPrices(msg.Value, c.legacyPriceCurrency)
 	if err != nil {
+		log.Logger.Error().Err(err).Msg("Rejected order with bare-number prices")
+		c.sendToDeadLetter(ctx, msg, err.Error())
+		return
+	}
+	if legacy {
+		log.Logger.Warn().Str("currency", c.legacyPriceCurrency).Msg("Order message has bare-number prices; producers should send money objects")
+	}
+
+	var order *entity.Order
+	err = json.Unmarshal(value, &order)
+	if err != nil || order == nil {
 		log.Logger.Error().Err(err).Msg("Failed to unmarshal Kafka message")
+		c.sendToDeadLetter(ctx, msg, "invalid order message")
 		return
 	}
 
 	key := string(msg.Key)
 	listKey := strings.Split(key, ".")
+	if len(listKey) < 2 {
+		log.Logger.Error().Str("key", key).Msg("Kafka message key has no event")
+		c.sendToDeadLetter(ctx, msg, "message key has no event")
+		return
+	}
 	event := listKey[1]
 
+	// Order lines are held to the same rules as stock reservations made over HTTP.
+	if err := c.validator.Validate(order); err != nil {
+		log.Logger.Error().Err(err).Int64("orderID", order.ID).Msg("Rejected invalid order")
+		c.sendToDeadLetter(ctx, msg, err.Error())
+		return
+	}
+
+	if err := c.verifyOrderHash(order); err != nil {
+		log.Logger.Error().Err(err).Int64("orderID", order.ID).Msg("Rejected order with invalid hash")
+		c.sendToDeadLetter(ctx, msg, err.Error())
+		return
+	}
+
 	switch event {
 	case "created":
-		for _, orderReq := range order.ProductRequests {
-			isAvail, resvErr := c.productSvc.ReserveProductStock(ctx, orderReq.ProductID, int(orderReq.Quantity))
-			if resvErr != nil || !isAvail {
-				log.Logger.Error().Err(resvErr).Int64("productID", orderReq.ProductID).Msg("Failed to reserve product stock")
-			} else {
-				log.Logger.Info().Int64("productID", orderReq.ProductID).Msg("Successfully reserved product stock")
+		// Stale or tampered prices must not reserve stock, so the whole order is rejected.
+		if err := c.pricingSvc.VerifyOrderPrices(ctx, order); err != nil {
+			log.Logger.Error().Err(err).Int64("orderID", order.ID).Msg("Rejected order with invalid prices")
+			c.sendToDeadLetter(ctx, msg, err.Error())
+			return
+		}
+		// Every line is reserved or none is, like carts reserved over HTTP and gRPC.
+		reservation, availability, err := c.productSvc.ReserveOrder(ctx, order.ID, orderLines(order))
+		if err != nil {
+			for _, line := range availability {
+				if line.Reason != "" {
+					log.Logger.Error().Int64("orderID", order.ID).Int64("productID", line.ProductID).Int64("variantID", line.VariantID).
+						Str("reason", string(line.Reason)).Msg("Order line cannot be reserved")
+				}
 			}
+			log.Logger.Error().Err(err).Int64("orderID", order.ID).Msg("Failed to reserve order stock")
+			c.sendToDeadLetter(ctx, msg, err.Error())
+			return
 		}
+		log.Logger.Info().Int64("orderID", order.ID).Str("reservationID", reservation.ID).Msg("Successfully reserved order stock")
 
 	case "cancelled":
-		for _, orderReq := range order.ProductRequests {
-			isReleased, relErr := c.productSvc.ReleaseProductStock(ctx, orderReq.ProductID, int(orderReq.Quantity))
-			if relErr != nil || !isReleased {
-				log.Logger.Error().Err(relErr).Int64("productID", orderReq.ProductID).Msg("Failed to release product stock")
-			} else {
-				log.Logger.Info().Int64("productID", orderReq.ProductID).Msg("Successfully released product stock")
-			}
+		// Only the stock reserved for the order is returned. An order whose reservation was
+		// rejected has none, and neither has one reserved line by line before orders had
+		// reservations; the lines of the latter are released one by one instead.
+		reservation, err := c.productSvc.ReleaseReservation(ctx, entity.OrderReservationID(order.ID))
+		if errors.Is(err, service.ErrReservationNotFound) && order.ID < c.lineReleaseBeforeOrderID {
+			c.releaseOrderLines(ctx, order)
+			return
 		}
+		if err != nil {
+			log.Logger.Error().Err(err).Int64("orderID", order.ID).Msg("Failed to release order stock")
+			return
+		}
+		log.Logger.Info().Int64("orderID", order.ID).Str("reservationID", reservation.ID).Msg("Successfully released order stock")
 	default:
 		log.Logger.Warn().Str("event", event).Msg("Unknown event type")
 	}
 }
+
+// releaseOrderLines returns the stock of every line of an order reserved line by line.
+func (c *MsgConsumer) releaseOrderLines(ctx context.Context, order *entity.Order) {
+	for _, line := range orderLines(order) {
+		var isReleased bool
+		var err error
+		if line.VariantID != 0 {
+			isReleased, err = c.productSvc.ReleaseVariantStock(ctx, line.ProductID, line.VariantID, line.Quantity)
+		} else {
+			isReleased, err = c.productSvc.ReleaseProductStock(ctx, line.ProductID, line.Quantity)
+		}
+		if err != nil || !isReleased {
+			log.Logger.Error().Err(err).Int64("orderID", order.ID).Int64("productID", line.ProductID).Int64("variantID", line.VariantID).Msg("Failed to release product stock")
+		} else {
+			log.Logger.Info().Int64("orderID", order.ID).Int64("productID", line.ProductID).Int64("variantID", line.VariantID).Msg("Successfully released product stock")
+		}
+	}
+}
+
+// orderLines returns the stock reservation lines of an order.
+func orderLines(order *entity.Order) []entity.StockReservation {
+	lines := make([]entity.StockReservation, 0, len(order.ProductRequests))
+	for _, req := range order.ProductRequests {
+		lines = append(lines, entity.StockReservation{ProductID: req.ProductID, VariantID: req.VariantID, Quantity: int(req.Quantity)})
+	}
+	return lines
+}
+
+// verifyOrderHash checks the HMAC of the order and of every order line.
+func (c *MsgConsumer) verifyOrderHash(order *entity.Order) error {
+	signed := orderhash.Order{
+		ID:         order.ID,
+		UserID:     order.UserID,
+		Quantity:   int64(order.Quantity),
+		TotalPrice: order.TotalPrice,
+		Status:     order.Status,
+		Lines:      make([]orderhash.Line, 0, len(order.ProductRequests)),
+	}
+	for i, req := range order.ProductRequests {
+		line := orderhash.Line{
+			OrderID:    req.OrderID,
+			ProductID:  req.ProductID,
+			VariantID:  req.VariantID,
+			Quantity:   req.Quantity,
+			MarkUp:     req.MarkUp,
+			Discount:   req.Discount,
+			FinalPrice: req.FinalPrice,
+		}
+		if err := c.keyring.VerifyLine(line, req.HashValue); err != nil {
+			return fmt.Errorf("order line %d: %w", i+1, err)
+		}
+		signed.Lines = append(signed.Lines, line)
+	}
+
+	if err := c.keyring.VerifyOrder(signed, order.HashValue); err != nil {
+		return fmt.Errorf("order: %w", err)
+	}
+	return nil
+}
+
+// sendToDeadLetter forwards a rejected message unchanged, adding the rejection reason and its
+// original position as headers so it can be inspected and replayed.
+func (c *MsgConsumer) sendToDeadLetter(ctx context.Context, msg kafka.Message, reason string) {
+	headers := append([]kafka.Header{}, msg.Headers...)
+	headers = append(headers,
+		kafka.Header{Key: headerDeadLetterReason, Value: []byte(reason)},
+		kafka.Header{Key: headerDeadLetterTopic, Value: []byte(msg.Topic)},
+		kafka.Header{Key: headerDeadLetterPartition, Value: []byte(strconv.Itoa(msg.Partition))},
+		kafka.Header{Key: headerDeadLetterOffset, Value: []byte(strconv.FormatInt(msg.Offset, 10))},
+	)
+
+	err := c.deadLetter.WriteMessages(ctx, kafka.Message{
+		Key:     msg.Key,
+		Value:   msg.Value,
+		Headers: headers,
+	})
+	if err != nil {
+		log.Logger.Error().Err(err).Str("reason", reason).Int64("offset", msg.Offset).Msg("Failed to write message to dead-letter topic")
+		return
+	}
+	log.Logger.Warn().Str("reason", reason).Int64("offset", msg.Offset).Msg("Message sent to dead-letter topic")
+}
@@ -0,0 +1,63 @@
+package msgBroker
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// Order messages used to carry prices as bare JSON numbers. Until every producer sends
+// {"amount", "currency"} objects, bare numbers are read as amounts in the legacy price currency.
+// The order hash covers the upgraded prices, so legacy producers sign the amount with that currency.
+
+// upgradeLegacyPrices rewrites the bare-number total_price and final_price fields of an order
+// message into money objects in currency. It reports whether any field was rewritten; messages
+// without bare numbers are returned unchanged.
+func upgradeLegacyPrices(data []byte, currency string) ([]byte, bool, error) {
+	var order map[string]json.RawMessage
+	if err := json.Unmarshal(data, &order); err != nil || order == nil {
+		return data, false, nil
+	}
+
+	upgraded := upgradePrice(order, "total_price", currency)
+	var lines []map[string]json.RawMessage
+	if raw, ok := order["product_requests"]; ok && json.Unmarshal(raw, &lines) == nil {
+		linesUpgraded := false
+		for _, line := range lines {
+			if upgradePrice(line, "final_price", currency) {
+				linesUpgraded = true
+			}
+		}
+		if linesUpgraded {
+			raw, err := json.Marshal(lines)
+			if err != nil {
+				return nil, false, err
+			}
+			order["product_requests"] = raw
+			upgraded = true
+		}
+	}
+	if !upgraded {
+		return data, false, nil
+	}
+
+	if currency == "" {
+		return nil, false, fmt.Errorf("prices must be {\"amount\", \"currency\"} objects")
+	}
+	out, err := json.Marshal(order)
+	if err != nil {
+		return nil, false, err
+	}
+	return out, true, nil
+}
+
+// upgradePrice replaces a bare-number field of fields with a money object in currency. The number
+// is kept as its literal text, so it is parsed exactly.
+func upgradePrice(fields map[string]json.RawMessage, name, currency string) bool {
+	raw := bytes.TrimSpace(fields[name])
+	if len(raw) == 0 || (raw[0] != '-' && (raw[0] < '0' || raw[0] > '9')) {
+		return false
+	}
+	fields[name] = json.RawMessage(fmt.Sprintf(`{"amount":%s,"currency":%q}`, raw, currency))
+	return true
+}
@@ -0,0 +1,100 @@
+// Package bloom provides a counting Bloom filter of integer keys.
+//
+// A Bloom filter answers whether a key may be in a set in constant memory: a negative answer is
+// certain, a positive one is wrong with a bounded probability. The counting variant keeps a small
+// counter instead of a bit per slot, so keys can be removed as well as added. Counters saturate
+// instead of overflowing; a saturated counter is never decremented, which can only leave false
+// positives behind, never false negatives.
+package bloom
+
+import (
+	"math"
+	"sync"
+)
+
+// Counting is a counting Bloom filter of int64 keys. It is safe for concurrent use.
+type Counting struct {
+	mu       sync.RWMutex
+	counters []uint8
+	hashes   uint64
+}
+
+// NewCounting creates a filter sized for capacity keys with a false positive rate of about
+// falsePositiveRate once that many keys have been added.
+func NewCounting(capacity int, falsePositiveRate float64) *Counting {
+	if capacity < 1 {
+		capacity = 1
+	}
+	if falsePositiveRate <= 0 || falsePositiveRate >= 1 {
+		falsePositiveRate = 0.01
+	}
+	slots := math.Ceil(-float64(capacity) * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2))
+	hashes := math.Max(1, math.Round(slots/float64(capacity)*math.Ln2))
+	return &Counting{
+		counters: make([]uint8, uint64(slots)),
+		hashes:   uint64(hashes),
+	}
+}
+
+// Add adds key to the set.
+func (f *Counting) Add(key int64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.eachSlot(key, func(slot uint64) {
+		if f.counters[slot] < math.MaxUint8 {
+			f.counters[slot]++
+		}
+	})
+}
+
+// Remove removes key from the set. Only keys that were added may be removed; removing any other
+// key can hide keys that are in the set.
+func (f *Counting) Remove(key int64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.test(key) {
+		return
+	}
+	f.eachSlot(key, func(slot uint64) {
+		if f.counters[slot] < math.MaxUint8 {
+			f.counters[slot]--
+		}
+	})
+}
+
+// Test reports whether key may be in the set. False means it certainly is not.
+func (f *Counting) Test(key int64) bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.test(key)
+}
+
+func (f *Counting) test(key int64) bool {
+	present := true
+	f.eachSlot(key, func(slot uint64) {
+		if f.counters[slot] == 0 {
+			present = false
+		}
+	})
+	return present
+}
+
+// eachSlot calls fn with every slot of key, derived by double hashing from two mixes of the key.
+func (f *Counting) eachSlot(key int64, fn func(slot uint64)) {
+	h1 := mix(uint64(key))
+	h2 := mix(uint64(key)^0x9e3779b97f4a7c15) | 1
+	size := uint64(len(f.counters))
+	for i := uint64(0); i < f.hashes; i++ {
+		fn((h1 + i*h2) % size)
+	}
+}
+
+// mix is the finaliser of SplitMix64, which spreads consecutive keys such as database IDs evenly.
+func mix(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
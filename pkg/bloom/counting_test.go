@@ -0,0 +1,69 @@
+package bloom
+
+import "testing"
+
+func TestCountingRemoveKeepsAddedKeys(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		capacity int
+		keys     int64
+	}{
+		{"sized", 10000, 10000},
+		// Far more keys than the filter is sized for saturate many counters, which are then never decremented.
+		{"saturated", 100, 35000},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			f := NewCounting(tc.capacity, 0.01)
+			for key := int64(1); key <= tc.keys; key++ {
+				f.Add(key)
+			}
+			// Keys added twice stay after one removal.
+			for key := int64(1); key <= tc.keys; key += 3 {
+				f.Add(key)
+			}
+			for key := int64(1); key <= tc.keys; key++ {
+				if key%2 == 0 || key%3 == 1 {
+					f.Remove(key)
+				}
+			}
+
+			for key := int64(1); key <= tc.keys; key++ {
+				if key%2 != 0 && !f.Test(key) {
+					t.Fatalf("key %d is still added but Test reports it absent", key)
+				}
+			}
+		})
+	}
+}
+
+func TestCountingRemove(t *testing.T) {
+	f := NewCounting(1000, 0.01)
+	for key := int64(1); key <= 1000; key++ {
+		f.Add(key)
+	}
+	for key := int64(1); key <= 1000; key++ {
+		f.Remove(key)
+	}
+	for key := int64(1); key <= 1000; key++ {
+		if f.Test(key) {
+			t.Fatalf("key %d is removed but Test reports it present", key)
+		}
+	}
+}
+
+func TestCountingFalsePositiveRate(t *testing.T) {
+	f := NewCounting(10000, 0.01)
+	for key := int64(1); key <= 10000; key++ {
+		f.Add(key)
+	}
+	falsePositives := 0
+	for key := int64(10001); key <= 110000; key++ {
+		if f.Test(key) {
+			falsePositives++
+		}
+	}
+	// Allow twice the configured rate, so the bound holds for any reasonable hashing.
+	if rate := float64(falsePositives) / 100000; rate > 0.02 {
+		t.Errorf("false positive rate = %.4f, want at most 0.02", rate)
+	}
+}
@@ -0,0 +1,171 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+)
+
+// GetCategories lists every category.
+func (c *Client) GetCategories(ctx context.Context) ([]Category, error) {
+	var out []Category
+	err := c.doJSON(ctx, http.MethodGet, "/categories", nil, nil, &out)
+	return out, err
+}
+
+// GetCategory returns a category.
+func (c *Client) GetCategory(ctx context.Context, categoryID int64) (*Category, error) {
+	var out Category
+	if err := c.doJSON(ctx, http.MethodGet, pathf("/category/%s", categoryID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateCategory creates a category.
+func (c *Client) CreateCategory(ctx context.Context, category Category) (*Category, error) {
+	var out Category
+	if err := c.doJSON(ctx, http.MethodPost, "/category", nil, category, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateCategory renames or moves a category.
+func (c *Client) UpdateCategory(ctx context.Context, categoryID int64, category Category) (*Category, error) {
+	var out Category
+	if err := c.doJSON(ctx, http.MethodPut, pathf("/category/%s", categoryID), nil, category, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteCategory deletes a category without children.
+func (c *Client) DeleteCategory(ctx context.Context, categoryID int64) error {
+	return c.doJSON(ctx, http.MethodDelete, pathf("/category/%s", categoryID), nil, nil, nil)
+}
+
+// AssignProductCategory moves a product to a category, or out of any category when categoryID is nil.
+func (c *Client) AssignProductCategory(ctx context.Context, productID int64, categoryID *int64) error {
+	in := struct {
+		CategoryID *int64 `json:"category_id"`
+	}{categoryID}
+	return c.doJSON(ctx, http.MethodPut, pathf("/product/%s/category", productID), nil, in, nil)
+}
+
+// GetTags lists every tag.
+func (c *Client) GetTags(ctx context.Context) ([]Tag, error) {
+	var out []Tag
+	err := c.doJSON(ctx, http.MethodGet, "/tags", nil, nil, &out)
+	return out, err
+}
+
+// CreateTag creates a tag.
+func (c *Client) CreateTag(ctx context.Context, name string) (*Tag, error) {
+	var out Tag
+	if err := c.doJSON(ctx, http.MethodPost, "/tag", nil, Tag{Name: name}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteTag deletes a tag.
+func (c *Client) DeleteTag(ctx context.Context, tagID int64) error {
+	return c.doJSON(ctx, http.MethodDelete, pathf("/tag/%s", tagID), nil, nil, nil)
+}
+
+// SetProductTags replaces the tags of a product.
+func (c *Client) SetProductTags(ctx context.Context, productID int64, tags []string) error {
+	in := struct {
+		Tags []string `json:"tags"`
+	}{tags}
+	return c.doJSON(ctx, http.MethodPut, pathf("/product/%s/tags", productID), nil, in, nil)
+}
+
+// GetVariants lists the variants of a product.
+func (c *Client) GetVariants(ctx context.Context, productID int64) ([]Variant, error) {
+	var out []Variant
+	err := c.doJSON(ctx, http.MethodGet, pathf("/product/%s/variants", productID), nil, nil, &out)
+	return out, err
+}
+
+// GetVariant returns a variant.
+func (c *Client) GetVariant(ctx context.Context, variantID int64) (*Variant, error) {
+	var out Variant
+	if err := c.doJSON(ctx, http.MethodGet, pathf("/variant/%s", variantID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateVariant adds a variant to a product.
+func (c *Client) CreateVariant(ctx context.Context, productID int64, variant Variant) (*Variant, error) {
+	var out Variant
+	if err := c.doJSON(ctx, http.MethodPost, pathf("/product/%s/variant", productID), nil, variant, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateVariant updates a variant.
+func (c *Client) UpdateVariant(ctx context.Context, variantID int64, variant Variant) (*Variant, error) {
+	var out Variant
+	if err := c.doJSON(ctx, http.MethodPut, pathf("/variant/%s", variantID), nil, variant, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteVariant deletes a variant.
+func (c *Client) DeleteVariant(ctx context.Context, variantID int64) error {
+	return c.doJSON(ctx, http.MethodDelete, pathf("/variant/%s", variantID), nil, nil, nil)
+}
+
+// GetMedia lists the images of a product in display order.
+func (c *Client) GetMedia(ctx context.Context, productID int64) ([]ProductMedia, error) {
+	var out []ProductMedia
+	err := c.doJSON(ctx, http.MethodGet, pathf("/product/%s/media", productID), nil, nil, &out)
+	return out, err
+}
+
+// UploadMedia uploads an image of a product.
+func (c *Client) UploadMedia(ctx context.Context, productID int64, filename string, image io.Reader) (*ProductMedia, error) {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("file", filename)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(part, image); err != nil {
+		return nil, err
+	}
+	if err := form.Close(); err != nil {
+		return nil, err
+	}
+
+	res, err := c.do(ctx, http.MethodPost, pathf("/product/%s/media", productID), nil, &body, form.FormDataContentType())
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	var out ProductMedia
+	if err := decodeJSON(res.Body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ReorderMedia sets the display order of the images of a product.
+func (c *Client) ReorderMedia(ctx context.Context, productID int64, mediaIDs []int64) error {
+	in := struct {
+		MediaIDs []int64 `json:"media_ids"`
+	}{mediaIDs}
+	return c.doJSON(ctx, http.MethodPut, pathf("/product/%s/media/order", productID), nil, in, nil)
+}
+
+// DeleteMedia deletes an image of a product.
+func (c *Client) DeleteMedia(ctx context.Context, productID, mediaID int64) error {
+	return c.doJSON(ctx, http.MethodDelete, pathf("/product/%s/media/%s", productID, mediaID), nil, nil, nil)
+}
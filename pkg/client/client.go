@@ -0,0 +1,227 @@
+// Package client is a Go client of version 1 of the product catalog service API, written against
+// the OpenAPI document the service serves at /v1/openapi.json. Its types mirror the schemas of that
+// document, so callers do not depend on the internal packages of the service.
+//
+// Every method returns an *Error when the service answers with an error status; its Problem holds
+// the decoded problem+json body, including the per-field validation errors and the availability of
+// a rejected cart reservation. Its Code can be matched with errors.Is.
+//
+// Calls that fail with 429, a 5xx status or a network error are retried with jittered exponential
+// backoff, within the deadline of the context. POST calls are sent with an Idempotency-Key, so the
+// service handles a retried call once; WithIdempotencyKey sets the key for calls that are repeated
+// across processes. Imports and uploads stream their body and are sent once.
+//
+// The stock streams at /v1/products/stock/stream (Server-Sent Events) and /v1/products/stock/ws
+// (WebSocket) are not wrapped; any SSE or WebSocket client can follow them.
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// apiPrefix is the path prefix of the API version the client is written against.
+const apiPrefix = "/v1"
+
+// Client calls the product catalog service. It is safe for concurrent use.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	token      string
+	retry      RetryPolicy
+	timeout    time.Duration
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client used for requests. The default has a 30 second timeout per
+// attempt.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithToken sets the JWT sent as a bearer token with every request.
+func WithToken(token string) Option {
+	return func(c *Client) {
+		c.token = token
+	}
+}
+
+// WithRetryPolicy sets how failed calls are retried. The default is DefaultRetryPolicy.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+// WithTimeout bounds every call whose context has no deadline, including its retries. Zero, the
+// default, leaves such calls bounded by the HTTP client only.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// New creates a Client for the service at baseURL, e.g. "http://catalog:8080".
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		retry:      DefaultRetryPolicy,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+type idempotencyKeyContextKey struct{}
+
+// WithIdempotencyKey returns a context that sends key as the Idempotency-Key of the POST call made
+// with it, instead of a key generated for the call. Repeating the call with the same key, e.g.
+// after a restart, gets the response of the first call instead of handling it again.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyContextKey{}, key)
+}
+
+// doJSON sends a request with an optional JSON body and decodes the JSON response into out, if set.
+func (c *Client) doJSON(ctx context.Context, method, path string, query url.Values, in, out interface{}) error {
+	var body io.Reader
+	contentType := ""
+	if in != nil {
+		payload, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		body = bytes.NewReader(payload)
+		contentType = "application/json"
+	}
+
+	res, err := c.do(ctx, method, path, query, body, contentType)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if out == nil {
+		_, err = io.Copy(io.Discard, res.Body)
+		return err
+	}
+	return decodeJSON(res.Body, out)
+}
+
+// decodeJSON decodes a JSON response body into out.
+func decodeJSON(body io.Reader, out interface{}) error {
+	if err := json.NewDecoder(body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// do sends a request, retrying it as the retry policy allows, and returns the response of a
+// successful call. The caller must close its body.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body io.Reader, contentType string) (*http.Response, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	target := c.baseURL + apiPrefix + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	// A body that cannot be read again is sent once. The service handles a POST once per
+	// idempotency key only with a JSON body or none, so other POST calls are sent once too.
+	retryable := body == nil || req.GetBody != nil
+	if method == http.MethodPost {
+		if contentType == "" || contentType == "application/json" {
+			req.Header.Set("Idempotency-Key", idempotencyKey(ctx))
+		} else {
+			retryable = false
+		}
+	}
+
+	res, err := c.send(req, retryable)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	if res.StatusCode >= http.StatusBadRequest {
+		defer cancel()
+		defer res.Body.Close()
+		return nil, decodeError(res)
+	}
+	res.Body = &cancelOnClose{ReadCloser: res.Body, cancel: cancel}
+	return res, nil
+}
+
+// withTimeout applies the client timeout to a context without a deadline.
+func (c *Client) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok || c.timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, c.timeout)
+}
+
+// cancelOnClose releases the context of a call once its streamed response body is closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// idempotencyKey returns the key set with WithIdempotencyKey, or a new random key.
+func idempotencyKey(ctx context.Context) string {
+	if key, ok := ctx.Value(idempotencyKeyContextKey{}).(string); ok && key != "" {
+		return key
+	}
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// decodeError reads the problem body of an error response.
+func decodeError(res *http.Response) error {
+	apiErr := &Error{StatusCode: res.StatusCode}
+	// A proxy in front of the service may answer with a body that is not a problem.
+	_ = json.NewDecoder(io.LimitReader(res.Body, 1<<20)).Decode(&apiErr.Problem)
+	if apiErr.Problem.Status == 0 {
+		apiErr.Problem.Status = res.StatusCode
+	}
+	return apiErr
+}
+
+// pathf formats a path, escaping every argument as a path segment.
+func pathf(format string, args ...interface{}) string {
+	escaped := make([]interface{}, len(args))
+	for i, arg := range args {
+		escaped[i] = url.PathEscape(fmt.Sprint(arg))
+	}
+	return fmt.Sprintf(format, escaped...)
+}
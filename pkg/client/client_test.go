@@ -0,0 +1,506 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"product-catalog-service/infrastructure/log"
+	"product-catalog-service/internal/api"
+	"product-catalog-service/internal/entity"
+	"product-catalog-service/internal/repository"
+	"product-catalog-service/internal/service"
+	"product-catalog-service/internal/validation"
+	"product-catalog-service/routes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	echojwt "github.com/labstack/echo-jwt/v4"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/rs/zerolog"
+)
+
+const testProductID = 1
+
+var testSecret = []byte("test-secret")
+
+func TestMain(m *testing.M) {
+	logger := zerolog.Nop()
+	log.Logger = &logger
+	os.Exit(m.Run())
+}
+
+// fakeProductRepo holds one product in memory, or fails every read with err.
+type fakeProductRepo struct {
+	repository.ProductRepository
+
+	mu          sync.Mutex
+	product     entity.Product
+	err         error
+	adjustments int
+}
+
+func (r *fakeProductRepo) GetProductByID(ctx context.Context, id int64) (*entity.Product, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return nil, r.err
+	}
+	if id != r.product.ID {
+		return nil, nil
+	}
+	product := r.product
+	return &product, nil
+}
+
+func (r *fakeProductRepo) AdjustProductStock(ctx context.Context, id int64, delta int) (int, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if id != r.product.ID || r.product.Stock+delta < 0 {
+		return 0, false, nil
+	}
+	r.adjustments++
+	r.product.Stock += delta
+	return r.product.Stock, true, nil
+}
+
+func (r *fakeProductRepo) stock() (int, int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.product.Stock, r.adjustments
+}
+
+type fakeVariantRepo struct {
+	repository.VariantRepository
+}
+
+func (fakeVariantRepo) CountVariants(ctx context.Context, productID int64) (int64, error) {
+	return 0, nil
+}
+
+type fakeSearchRepo struct {
+	repository.SearchRepository
+}
+
+func (fakeSearchRepo) IndexProduct(ctx context.Context, product *entity.Product) error {
+	return nil
+}
+
+func (fakeSearchRepo) RemoveProduct(ctx context.Context, id int64) error {
+	return nil
+}
+
+type fakeStockEventRepo struct {
+	repository.StockEventRepository
+}
+
+func (fakeStockEventRepo) PublishStockUpdate(ctx context.Context, update entity.StockUpdate) error {
+	return nil
+}
+
+// fakeIdempotencyRepo keeps idempotency records in memory, without expiry.
+type fakeIdempotencyRepo struct {
+	mu      sync.Mutex
+	records map[string]entity.IdempotencyRecord
+}
+
+func (r *fakeIdempotencyRepo) ClaimKey(ctx context.Context, key string, record entity.IdempotencyRecord, ttl time.Duration) (bool, *entity.IdempotencyRecord, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if existing, ok := r.records[key]; ok {
+		return false, &existing, nil
+	}
+	r.records[key] = record
+	return true, nil, nil
+}
+
+func (r *fakeIdempotencyRepo) SaveRecord(ctx context.Context, key string, record entity.IdempotencyRecord, ttl time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.records[key] = record
+	return nil
+}
+
+func (r *fakeIdempotencyRepo) DeleteRecord(ctx context.Context, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.records, key)
+	return nil
+}
+
+// faultAction is how the fault injector answers an attempt. The zero value passes it to the service.
+type faultAction struct {
+	// status answers the attempt with an error status instead of the service.
+	status int
+	// retryAfter is the Retry-After header sent with status, if any.
+	retryAfter string
+	// afterHandling passes the attempt to the service first and drops its response.
+	afterHandling bool
+	// hang blocks until the client gives up.
+	hang bool
+}
+
+// fault returns the action for the attempt with the given number, counted from one.
+type fault func(attempt int) faultAction
+
+// faultInjector sits in front of the service, counts the attempts it receives and records their
+// Idempotency-Key headers.
+type faultInjector struct {
+	next  http.Handler
+	fault fault
+
+	mu       sync.Mutex
+	attempts int
+	keys     []string
+}
+
+func (f *faultInjector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	f.attempts++
+	attempt := f.attempts
+	f.keys = append(f.keys, r.Header.Get(api.HeaderIdempotencyKey))
+	f.mu.Unlock()
+
+	if f.fault == nil {
+		f.next.ServeHTTP(w, r)
+		return
+	}
+	action := f.fault(attempt)
+	switch {
+	case action.hang:
+		<-r.Context().Done()
+		return
+	case action.status == 0:
+		f.next.ServeHTTP(w, r)
+		return
+	case action.afterHandling:
+		// The service handles the request, but its response is lost on the way back.
+		f.next.ServeHTTP(httptest.NewRecorder(), r)
+	}
+	if action.retryAfter != "" {
+		w.Header().Set("Retry-After", action.retryAfter)
+	}
+	w.Header().Set("Content-Type", api.MIMEApplicationProblemJSON)
+	w.WriteHeader(action.status)
+	_, _ = fmt.Fprintf(w, `{"status":%d,"code":%q}`, action.status, codeOfStatus(action.status))
+}
+
+// codeOfStatus returns the code the service sets on an error raised outside the domain.
+func codeOfStatus(status int) string {
+	return strings.ReplaceAll(strings.ToLower(http.StatusText(status)), " ", "_")
+}
+
+func (f *faultInjector) recorded() (int, []string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.attempts, append([]string(nil), f.keys...)
+}
+
+// newTestServer serves the routes of the service with the middleware of cmd/main, the real
+// handlers and services, and fake repositories, behind a fault injector.
+func newTestServer(t *testing.T, productRepo *fakeProductRepo, fault fault) (*httptest.Server, *faultInjector) {
+	t.Helper()
+	productService := service.NewProductService(productRepo, fakeSearchRepo{}, nil, fakeVariantRepo{}, nil, nil, nil,
+		service.NewStockStreamService(fakeStockEventRepo{}, 0), nil)
+	idempotencyService := service.NewIdempotencyService(&fakeIdempotencyRepo{records: make(map[string]entity.IdempotencyRecord)}, time.Hour)
+
+	e := echo.New()
+	e.HTTPErrorHandler = api.HTTPErrorHandler
+	e.Validator = validation.New()
+	e.Use(middleware.RequestID())
+	e.Use(echojwt.WithConfig(echojwt.Config{SigningKey: testSecret}))
+	e.Use(api.Idempotency(idempotencyService, routes.V1))
+	routes.SetupRoutes(e, routes.V1Handlers{
+		Product:     api.NewProductHandler(productService),
+		Category:    api.NewCategoryHandler(nil),
+		Variant:     api.NewVariantHandler(nil),
+		Media:       api.NewMediaHandler(nil),
+		Import:      api.NewImportHandler(nil),
+		Export:      api.NewExportHandler(nil),
+		Pricing:     api.NewPricingHandler(nil),
+		Price:       api.NewPriceHandler(nil),
+		Currency:    api.NewCurrencyHandler(nil),
+		StockStream: api.NewStockStreamHandler(productService, nil, time.Second),
+		Cache:       api.NewCacheHandler(nil),
+	}, routes.Deprecation{DeprecatedAt: time.Now()})
+
+	injector := &faultInjector{next: e, fault: fault}
+	server := httptest.NewServer(injector)
+	t.Cleanup(server.Close)
+	return server, injector
+}
+
+func newProductRepo(stock int) *fakeProductRepo {
+	return &fakeProductRepo{product: entity.Product{ID: testProductID, Name: "Test", Status: entity.ProductStatusActive, Stock: stock}}
+}
+
+func testToken(t *testing.T) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "client-test"}).SignedString(testSecret)
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	return token
+}
+
+// fastRetries retries without noticeable delay.
+var fastRetries = RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+
+func TestClientSendsToken(t *testing.T) {
+	server, _ := newTestServer(t, newProductRepo(7), nil)
+
+	stock, err := New(server.URL, WithToken(testToken(t))).GetProductStock(context.Background(), testProductID)
+	if err != nil {
+		t.Fatalf("GetProductStock with token: %v", err)
+	}
+	if stock != 7 {
+		t.Errorf("stock = %d, want 7", stock)
+	}
+
+	_, err = New(server.URL).GetProductStock(context.Background(), testProductID)
+	if !errors.Is(err, CodeBadRequest) {
+		t.Errorf("GetProductStock without token = %v, want %s", err, CodeBadRequest)
+	}
+
+	_, err = New(server.URL, WithToken("not-a-jwt")).GetProductStock(context.Background(), testProductID)
+	if !errors.Is(err, CodeUnauthorized) {
+		t.Errorf("GetProductStock with invalid token = %v, want %s", err, CodeUnauthorized)
+	}
+}
+
+func TestClientRetriesWithSameIdempotencyKey(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		fault        fault
+		wantAttempts int
+	}{
+		{
+			name: "rate limited",
+			fault: func(attempt int) faultAction {
+				if attempt == 1 {
+					return faultAction{status: http.StatusTooManyRequests, retryAfter: "0"}
+				}
+				return faultAction{}
+			},
+			wantAttempts: 2,
+		},
+		{
+			name: "unavailable",
+			fault: func(attempt int) faultAction {
+				if attempt < 3 {
+					return faultAction{status: http.StatusServiceUnavailable}
+				}
+				return faultAction{}
+			},
+			wantAttempts: 3,
+		},
+		{
+			name: "response lost",
+			fault: func(attempt int) faultAction {
+				if attempt == 1 {
+					return faultAction{status: http.StatusServiceUnavailable, afterHandling: true}
+				}
+				return faultAction{}
+			},
+			wantAttempts: 2,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			productRepo := newProductRepo(10)
+			server, injector := newTestServer(t, productRepo, tc.fault)
+			c := New(server.URL, WithToken(testToken(t)), WithRetryPolicy(fastRetries))
+
+			err := c.ReserveProductStock(context.Background(), StockReservation{ProductID: testProductID, Quantity: 3})
+			if err != nil {
+				t.Fatalf("ReserveProductStock: %v", err)
+			}
+
+			attempts, keys := injector.recorded()
+			if attempts != tc.wantAttempts {
+				t.Errorf("attempts = %d, want %d", attempts, tc.wantAttempts)
+			}
+			for _, key := range keys {
+				if key == "" || key != keys[0] {
+					t.Fatalf("Idempotency-Key headers = %q, want one key on every attempt", keys)
+				}
+			}
+			// The stock is taken once, even when the first attempt was handled.
+			if stock, adjustments := productRepo.stock(); stock != 7 || adjustments != 1 {
+				t.Errorf("stock = %d after %d adjustments, want 7 after 1", stock, adjustments)
+			}
+		})
+	}
+}
+
+func TestClientUsesIdempotencyKeyFromContext(t *testing.T) {
+	productRepo := newProductRepo(10)
+	server, injector := newTestServer(t, productRepo, nil)
+	c := New(server.URL, WithToken(testToken(t)))
+	ctx := WithIdempotencyKey(context.Background(), "order-42")
+
+	for i := 0; i < 2; i++ {
+		if err := c.ReserveProductStock(ctx, StockReservation{ProductID: testProductID, Quantity: 4}); err != nil {
+			t.Fatalf("ReserveProductStock %d: %v", i+1, err)
+		}
+	}
+
+	_, keys := injector.recorded()
+	if strings.Join(keys, ",") != "order-42,order-42" {
+		t.Errorf("Idempotency-Key headers = %q, want order-42 twice", keys)
+	}
+	if stock, adjustments := productRepo.stock(); stock != 6 || adjustments != 1 {
+		t.Errorf("stock = %d after %d adjustments, want 6 after 1", stock, adjustments)
+	}
+}
+
+func TestClientGivesUpAfterMaxAttempts(t *testing.T) {
+	server, injector := newTestServer(t, newProductRepo(10), func(int) faultAction {
+		return faultAction{status: http.StatusServiceUnavailable}
+	})
+	c := New(server.URL, WithToken(testToken(t)), WithRetryPolicy(fastRetries))
+
+	_, err := c.GetProductStock(context.Background(), testProductID)
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable || !apiErr.Temporary() {
+		t.Fatalf("GetProductStock = %v, want a temporary 503 error", err)
+	}
+	if attempts, _ := injector.recorded(); attempts != fastRetries.MaxAttempts {
+		t.Errorf("attempts = %d, want %d", attempts, fastRetries.MaxAttempts)
+	}
+}
+
+func TestClientStopsAtContextDeadline(t *testing.T) {
+	server, injector := newTestServer(t, newProductRepo(10), func(int) faultAction {
+		return faultAction{hang: true}
+	})
+	c := New(server.URL, WithToken(testToken(t)), WithRetryPolicy(fastRetries))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := c.GetProductStock(ctx, testProductID)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("GetProductStock = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("GetProductStock returned after %s, want about the 100ms deadline", elapsed)
+	}
+	if attempts, _ := injector.recorded(); attempts != 1 {
+		t.Errorf("attempts = %d, want 1", attempts)
+	}
+}
+
+func TestClientDoesNotWaitPastTimeout(t *testing.T) {
+	server, injector := newTestServer(t, newProductRepo(10), func(int) faultAction {
+		return faultAction{status: http.StatusTooManyRequests, retryAfter: "1"}
+	})
+	c := New(server.URL, WithToken(testToken(t)), WithTimeout(50*time.Millisecond), WithRetryPolicy(fastRetries))
+
+	// Waiting the second asked for by Retry-After would outlive the timeout, so the call fails at once.
+	start := time.Now()
+	_, err := c.GetProductStock(context.Background(), testProductID)
+	if !errors.Is(err, CodeTooManyRequests) {
+		t.Fatalf("GetProductStock = %v, want %s", err, CodeTooManyRequests)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("GetProductStock returned after %s, want before the Retry-After delay", elapsed)
+	}
+	if attempts, _ := injector.recorded(); attempts != 1 {
+		t.Errorf("attempts = %d, want 1", attempts)
+	}
+}
+
+func TestBackoffIsJitteredUpToCeiling(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 5, BaseDelay: 10 * time.Millisecond, MaxDelay: 40 * time.Millisecond}
+	for attempt, ceiling := range map[int]time.Duration{1: 10 * time.Millisecond, 2: 20 * time.Millisecond, 3: 40 * time.Millisecond, 4: 40 * time.Millisecond} {
+		seen := make(map[time.Duration]bool)
+		for i := 0; i < 100; i++ {
+			delay := policy.backoff(attempt, nil)
+			if delay <= 0 || delay > ceiling {
+				t.Fatalf("backoff(%d) = %s, want within (0, %s]", attempt, delay, ceiling)
+			}
+			seen[delay] = true
+		}
+		if len(seen) < 2 {
+			t.Errorf("backoff(%d) returned the same delay 100 times, want jitter", attempt)
+		}
+	}
+
+	res := &http.Response{Header: http.Header{"Retry-After": []string{"2"}}}
+	if delay := policy.backoff(1, res); delay != 2*time.Second {
+		t.Errorf("backoff with Retry-After: 2 = %s, want 2s", delay)
+	}
+}
+
+// TestClientMapsServerErrorCodes checks that every domain error of the service reaches the client
+// as its Code.
+func TestClientMapsServerErrorCodes(t *testing.T) {
+	for _, tc := range []struct {
+		err  error
+		want Code
+	}{
+		{repository.ErrNotFound, CodeNotFound},
+		{repository.ErrDuplicate, CodeDuplicate},
+		{repository.ErrInvalidReference, CodeInvalidReference},
+		{validation.ErrValidation, CodeValidationFailed},
+		{service.ErrInvalidCurrency, CodeInvalidCurrency},
+		{service.ErrCurrencyUnavailable, CodeCurrencyUnavailable},
+		{service.ErrCurrencyNotFound, CodeCurrencyNotFound},
+		{service.ErrVariantNotFound, CodeVariantNotFound},
+		{service.ErrInvalidStock, CodeInvalidStock},
+		{service.ErrUnsupportedImportFormat, CodeUnsupportedImportFormat},
+		{service.ErrUnsupportedExportFormat, CodeUnsupportedExportFormat},
+		{service.ErrCategoryNotFound, CodeCategoryNotFound},
+		{service.ErrCategoryHasChildren, CodeCategoryHasChildren},
+		{service.ErrInvalidCategory, CodeInvalidCategory},
+		{service.ErrInvalidTag, CodeInvalidTag},
+		{service.ErrPriceNotFound, CodePriceNotFound},
+		{service.ErrInvalidQuote, CodeInvalidQuote},
+		{service.ErrPriceMismatch, CodePriceMismatch},
+		{service.ErrProductNotFound, CodeProductNotFound},
+		{service.ErrProductUnavailable, CodeProductUnavailable},
+		{service.ErrInsufficientStock, CodeInsufficientStock},
+		{service.ErrVariantRequired, CodeVariantRequired},
+		{service.ErrConcurrentUpdate, CodeConcurrentUpdate},
+		{service.ErrInvalidProductStatus, CodeInvalidProductStatus},
+		{service.ErrProductStatusTransition, CodeProductStatusTransition},
+		{service.ErrInvalidPrice, CodeInvalidPrice},
+		{service.ErrCartUnavailable, CodeCartUnavailable},
+		{service.ErrReservationNotFound, CodeReservationNotFound},
+		{service.ErrReservationConfirmed, CodeReservationConfirmed},
+		{service.ErrReservationReleased, CodeReservationReleased},
+		{service.ErrIdempotencyInProgress, CodeIdempotencyInProgress},
+		{service.ErrIdempotencyKeyReused, CodeIdempotencyKeyReused},
+		{service.ErrInvalidIdempotencyKey, CodeInvalidIdempotencyKey},
+		{service.ErrUnsupportedMediaType, CodeUnsupportedMediaType},
+		{service.ErrMediaTooLarge, CodeMediaTooLarge},
+		{service.ErrMediaNotFound, CodeMediaNotFound},
+		{service.ErrInvalidMediaOrder, CodeInvalidMediaOrder},
+		{errors.New("database is down"), CodeInternalError},
+	} {
+		t.Run(string(tc.want), func(t *testing.T) {
+			productRepo := newProductRepo(10)
+			productRepo.err = tc.err
+			server, _ := newTestServer(t, productRepo, nil)
+			c := New(server.URL, WithToken(testToken(t)), WithRetryPolicy(RetryPolicy{MaxAttempts: 1}))
+
+			_, err := c.GetProductStock(context.Background(), testProductID)
+			if !errors.Is(err, tc.want) || ErrorCode(err) != tc.want {
+				t.Errorf("GetProductStock = %v, want code %s", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestErrorMessage(t *testing.T) {
+	err := &Error{StatusCode: http.StatusConflict, Problem: Problem{Code: CodeInsufficientStock, Detail: "not enough stock"}}
+	if got, want := err.Error(), "product catalog: insufficient_stock: not enough stock"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
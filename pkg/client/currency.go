@@ -0,0 +1,58 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"product-catalog-service/pkg/money"
+)
+
+// QuotePrices returns the authoritative prices of order lines.
+func (c *Client) QuotePrices(ctx context.Context, request PriceQuoteRequest) (*PriceQuote, error) {
+	var out PriceQuote
+	if err := c.doJSON(ctx, http.MethodPost, "/pricing/quote", nil, request, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetExchangeRates lists every exchange rate.
+func (c *Client) GetExchangeRates(ctx context.Context) ([]ExchangeRate, error) {
+	var out []ExchangeRate
+	err := c.doJSON(ctx, http.MethodGet, "/exchange-rates", nil, nil, &out)
+	return out, err
+}
+
+// SetExchangeRate creates or replaces the rate of a currency pair.
+func (c *Client) SetExchangeRate(ctx context.Context, rate ExchangeRate) (*ExchangeRate, error) {
+	var out ExchangeRate
+	if err := c.doJSON(ctx, http.MethodPut, "/exchange-rate", nil, rate, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteExchangeRate deletes the rate from base to quote.
+func (c *Client) DeleteExchangeRate(ctx context.Context, base, quote string) error {
+	return c.doJSON(ctx, http.MethodDelete, pathf("/exchange-rate/%s/%s", base, quote), nil, nil, nil)
+}
+
+// GetCurrencyPrices lists the explicit prices of a product in other currencies.
+func (c *Client) GetCurrencyPrices(ctx context.Context, productID int64) ([]CurrencyPrice, error) {
+	var out []CurrencyPrice
+	err := c.doJSON(ctx, http.MethodGet, pathf("/product/%s/currency-prices", productID), nil, nil, &out)
+	return out, err
+}
+
+// SetCurrencyPrice sets the price of a product in another currency, overriding conversion.
+func (c *Client) SetCurrencyPrice(ctx context.Context, productID int64, price money.Money) (*CurrencyPrice, error) {
+	var out CurrencyPrice
+	if err := c.doJSON(ctx, http.MethodPut, pathf("/product/%s/currency-price", productID), nil, price, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteCurrencyPrice deletes the price of a product in a currency, which falls back to conversion.
+func (c *Client) DeleteCurrencyPrice(ctx context.Context, productID int64, currency string) error {
+	return c.doJSON(ctx, http.MethodDelete, pathf("/product/%s/currency-price/%s", productID, currency), nil, nil, nil)
+}
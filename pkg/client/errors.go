@@ -0,0 +1,108 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Code is the stable error code the service sets on every error response, the code member of its
+// problem body. Codes are errors, so a returned error can be matched with errors.Is:
+//
+//	if errors.Is(err, client.CodeInsufficientStock) {
+//		// Show the product as sold out
+//	}
+type Code string
+
+func (c Code) Error() string {
+	return "product catalog: " + string(c)
+}
+
+// Codes of the domain errors of the service.
+const (
+	CodeValidationFailed        Code = "validation_failed"
+	CodeNotFound                Code = "not_found"
+	CodeDuplicate               Code = "duplicate"
+	CodeInvalidReference        Code = "invalid_reference"
+	CodeConcurrentUpdate        Code = "concurrent_update"
+	CodeProductNotFound         Code = "product_not_found"
+	CodeProductUnavailable      Code = "product_unavailable"
+	CodeInvalidProductStatus    Code = "invalid_product_status"
+	CodeProductStatusTransition Code = "product_status_transition"
+	CodeInvalidPrice            Code = "invalid_price"
+	CodeInsufficientStock       Code = "insufficient_stock"
+	CodeInvalidStock            Code = "invalid_stock"
+	CodeVariantRequired         Code = "variant_required"
+	CodeVariantNotFound         Code = "variant_not_found"
+	CodeCartUnavailable         Code = "cart_unavailable"
+	CodeReservationNotFound     Code = "reservation_not_found"
+	CodeReservationConfirmed    Code = "reservation_confirmed"
+	CodeReservationReleased     Code = "reservation_released"
+	CodeCategoryNotFound        Code = "category_not_found"
+	CodeCategoryHasChildren     Code = "category_has_children"
+	CodeInvalidCategory         Code = "invalid_category"
+	CodeInvalidTag              Code = "invalid_tag"
+	CodeMediaNotFound           Code = "media_not_found"
+	CodeMediaTooLarge           Code = "media_too_large"
+	CodeInvalidMediaOrder       Code = "invalid_media_order"
+	CodeUnsupportedMediaType    Code = "unsupported_media_type"
+	CodePriceNotFound           Code = "price_not_found"
+	CodePriceMismatch           Code = "price_mismatch"
+	CodeInvalidQuote            Code = "invalid_quote"
+	CodeInvalidCurrency         Code = "invalid_currency"
+	CodeCurrencyUnavailable     Code = "currency_unavailable"
+	CodeCurrencyNotFound        Code = "currency_not_found"
+	CodeUnsupportedImportFormat Code = "unsupported_import_format"
+	CodeUnsupportedExportFormat Code = "unsupported_export_format"
+	CodeIdempotencyInProgress   Code = "idempotency_in_progress"
+	CodeIdempotencyKeyReused    Code = "idempotency_key_reused"
+	CodeInvalidIdempotencyKey   Code = "invalid_idempotency_key"
+)
+
+// Codes of errors raised outside the domain, derived from their HTTP status.
+const (
+	CodeBadRequest            Code = "bad_request" // Also a missing bearer token
+	CodeUnauthorized          Code = "unauthorized"
+	CodeMethodNotAllowed      Code = "method_not_allowed"
+	CodeRequestEntityTooLarge Code = "request_entity_too_large"
+	CodeTooManyRequests       Code = "too_many_requests"
+	CodeServiceUnavailable    Code = "service_unavailable" // The request timed out
+	CodeInternalError         Code = "internal_error"
+)
+
+// Error is an error response of the service.
+type Error struct {
+	StatusCode int
+	Problem    Problem
+}
+
+func (e *Error) Error() string {
+	if e.Problem.Code == "" {
+		return fmt.Sprintf("product catalog: status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("product catalog: %s: %s", string(e.Problem.Code), e.Problem.Detail)
+}
+
+// Is reports whether target is the Code of the error.
+func (e *Error) Is(target error) bool {
+	code, ok := target.(Code)
+	return ok && e.Problem.Code == code
+}
+
+// ErrorCode returns the code of the first *Error in the chain of err, or "" if there is none.
+func ErrorCode(err error) Code {
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return apiErr.Problem.Code
+	}
+	return ""
+}
+
+// Temporary reports whether the error is a response that may succeed when the call is repeated
+// later: rate limiting, a server error or a concurrent request with the same idempotency key.
+// The client has already retried such calls by the time they are returned.
+func (e *Error) Temporary() bool {
+	return e.StatusCode == http.StatusTooManyRequests ||
+		(e.StatusCode >= http.StatusInternalServerError && e.StatusCode != http.StatusNotImplemented) ||
+		e.Problem.Code == CodeIdempotencyInProgress
+}
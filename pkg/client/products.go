@@ -0,0 +1,220 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// GetProductStock returns the stock of a product.
+func (c *Client) GetProductStock(ctx context.Context, productID int64) (int, error) {
+	var out struct {
+		Stock int `json:"stock"`
+	}
+	err := c.doJSON(ctx, http.MethodGet, pathf("/product/%s/stock", productID), nil, nil, &out)
+	return out.Stock, err
+}
+
+// GetProductStocks returns the stock of up to 200 products at once.
+func (c *Client) GetProductStocks(ctx context.Context, productIDs []int64) (*StockBatch, error) {
+	in := struct {
+		ProductIDs []int64 `json:"product_ids"`
+	}{productIDs}
+	var out StockBatch
+	if err := c.doJSON(ctx, http.MethodPost, "/products/stock:batch", nil, in, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ReserveProductStock reserves stock of a product, or of one of its variants when VariantID is set.
+func (c *Client) ReserveProductStock(ctx context.Context, reservation StockReservation) error {
+	return c.doJSON(ctx, http.MethodPost, "/product/reserve", nil, reservation, nil)
+}
+
+// ReleaseProductStock releases stock of a product, or of one of its variants when VariantID is set.
+func (c *Client) ReleaseProductStock(ctx context.Context, reservation StockReservation) error {
+	return c.doJSON(ctx, http.MethodPost, "/product/release", nil, reservation, nil)
+}
+
+// ReserveCart reserves every line of a cart under one reservation ID, or none of them. When the
+// cart is rejected, the Problem of the returned *Error lists the availability of every line.
+func (c *Client) ReserveCart(ctx context.Context, lines []StockReservation) (*CartReservation, error) {
+	in := struct {
+		Lines []StockReservation `json:"lines"`
+	}{lines}
+	var out CartReservation
+	if err := c.doJSON(ctx, http.MethodPost, "/cart/reserve", nil, in, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetReservation returns a cart reservation.
+func (c *Client) GetReservation(ctx context.Context, reservationID string) (*CartReservation, error) {
+	var out CartReservation
+	if err := c.doJSON(ctx, http.MethodGet, pathf("/cart/reservation/%s", reservationID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ReleaseReservation returns the stock of a cart reservation. Releasing it again has no effect.
+func (c *Client) ReleaseReservation(ctx context.Context, reservationID string) (*CartReservation, error) {
+	var out CartReservation
+	if err := c.doJSON(ctx, http.MethodPost, pathf("/cart/reservation/%s/release", reservationID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ConfirmReservation makes a cart reservation final once its order is placed. Confirming it again
+// has no effect.
+func (c *Client) ConfirmReservation(ctx context.Context, reservationID string) (*CartReservation, error) {
+	var out CartReservation
+	if err := c.doJSON(ctx, http.MethodPost, pathf("/cart/reservation/%s/confirm", reservationID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// AdjustProductStock corrects the stock of a product by delta and returns the new stock.
+func (c *Client) AdjustProductStock(ctx context.Context, productID int64, delta int) (int, error) {
+	in := struct {
+		Delta int `json:"delta"`
+	}{delta}
+	var out struct {
+		Stock int `json:"stock"`
+	}
+	err := c.doJSON(ctx, http.MethodPost, pathf("/admin/product/%s/stock", productID), nil, in, &out)
+	return out.Stock, err
+}
+
+// GetAllProducts lists the active products matching the filter.
+func (c *Client) GetAllProducts(ctx context.Context, filter ProductFilter) ([]Product, error) {
+	query := url.Values{}
+	if filter.CategoryID != 0 {
+		query.Set("category_id", strconv.FormatInt(filter.CategoryID, 10))
+	}
+	if filter.Tag != "" {
+		query.Set("tag", filter.Tag)
+	}
+	if filter.Currency != "" {
+		query.Set("currency", filter.Currency)
+	}
+	var out []Product
+	err := c.doJSON(ctx, http.MethodGet, "/products", query, nil, &out)
+	return out, err
+}
+
+// SearchProducts runs a full-text search over product names and descriptions. A zero limit uses
+// the server default; an empty currency returns prices in their own currency.
+func (c *Client) SearchProducts(ctx context.Context, q string, limit int, currency string) ([]ProductSearchResult, error) {
+	query := url.Values{"q": {q}}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	if currency != "" {
+		query.Set("currency", currency)
+	}
+	var out []ProductSearchResult
+	err := c.doJSON(ctx, http.MethodGet, "/products/search", query, nil, &out)
+	return out, err
+}
+
+// ImportProducts upserts products from a CSV or JSON Lines body and returns the per-row report.
+// When the import fails part way, the Problem of the returned *Error holds the partial report.
+func (c *Client) ImportProducts(ctx context.Context, body io.Reader, opts ImportOptions) (*ImportReport, error) {
+	query := url.Values{}
+	if opts.Format != "" {
+		query.Set("format", opts.Format)
+	}
+	if opts.DryRun {
+		query.Set("dry_run", "true")
+	}
+	if opts.BatchSize > 0 {
+		query.Set("batch_size", strconv.Itoa(opts.BatchSize))
+	}
+	contentType := "text/csv"
+	if opts.Format == "jsonl" {
+		contentType = "application/x-ndjson"
+	}
+
+	res, err := c.do(ctx, http.MethodPost, "/products/import", query, body, contentType)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	var out ImportReport
+	if err := decodeJSON(res.Body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ExportProducts streams the catalogue in format ("csv", "jsonl" or "parquet"). The caller must
+// close the returned body; its checksum and row count are in the trailers of the response once
+// the body has been read to the end.
+func (c *Client) ExportProducts(ctx context.Context, format string) (*http.Response, error) {
+	return c.do(ctx, http.MethodGet, "/products/export", url.Values{"format": {format}}, nil, "")
+}
+
+// CreateProduct creates a product.
+func (c *Client) CreateProduct(ctx context.Context, product Product) error {
+	return c.doJSON(ctx, http.MethodPost, "/product", nil, product, nil)
+}
+
+// ChangeProductStatus moves a product through its lifecycle.
+func (c *Client) ChangeProductStatus(ctx context.Context, productID int64, change ProductStatusChange) (*Product, error) {
+	var out Product
+	if err := c.doJSON(ctx, http.MethodPut, pathf("/product/%s/status", productID), nil, change, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteProduct soft-deletes a product.
+func (c *Client) DeleteProduct(ctx context.Context, productID int64) error {
+	return c.doJSON(ctx, http.MethodDelete, pathf("/product/%s", productID), nil, nil, nil)
+}
+
+// RestoreProduct restores a soft-deleted product.
+func (c *Client) RestoreProduct(ctx context.Context, productID int64) (*Product, error) {
+	var out Product
+	if err := c.doJSON(ctx, http.MethodPost, pathf("/admin/product/%s/restore", productID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetPrice returns the price of a product at a point in time, or now when at is zero.
+func (c *Client) GetPrice(ctx context.Context, productID int64, at time.Time) (*PriceChange, error) {
+	query := url.Values{}
+	if !at.IsZero() {
+		query.Set("at", at.Format(time.RFC3339Nano))
+	}
+	var out PriceChange
+	if err := c.doJSON(ctx, http.MethodGet, pathf("/product/%s/price", productID), query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ChangePrice changes the price of a product now or, with EffectiveFrom set, schedules the change.
+func (c *Client) ChangePrice(ctx context.Context, productID int64, change PriceChangeRequest) (*PriceChange, error) {
+	var out PriceChange
+	if err := c.doJSON(ctx, http.MethodPost, pathf("/product/%s/price", productID), nil, change, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetPriceHistory returns the past, current and scheduled prices of a product.
+func (c *Client) GetPriceHistory(ctx context.Context, productID int64) ([]PriceChange, error) {
+	var out []PriceChange
+	err := c.doJSON(ctx, http.MethodGet, pathf("/product/%s/prices", productID), nil, nil, &out)
+	return out, err
+}
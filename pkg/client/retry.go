@@ -0,0 +1,115 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how failed calls are retried. Calls are retried on 429, on a 5xx status
+// other than 501, on a network error and while another call with the same idempotency key is in
+// progress.
+type RetryPolicy struct {
+	// MaxAttempts is the number of times a call is sent, including the first. One disables retries.
+	MaxAttempts int
+	// BaseDelay is the backoff before the first retry; it doubles with every further retry. Each
+	// delay is drawn at random up to the backoff, so that clients retrying together spread out.
+	BaseDelay time.Duration
+	// MaxDelay caps the backoff.
+	MaxDelay time.Duration
+}
+
+// DefaultRetryPolicy sends a call up to three times.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   100 * time.Millisecond,
+	MaxDelay:    2 * time.Second,
+}
+
+// send sends req until it succeeds, fails for good, runs out of attempts or would outlive the
+// deadline of its context, and returns the last response.
+func (c *Client) send(req *http.Request, retryable bool) (*http.Response, error) {
+	ctx := req.Context()
+	for attempt := 1; ; attempt++ {
+		res, err := c.httpClient.Do(req)
+		if !retryable || attempt >= c.retry.MaxAttempts || ctx.Err() != nil || !shouldRetry(res, err) {
+			return res, err
+		}
+
+		delay := c.retry.backoff(attempt, res)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return res, err
+		}
+		if res != nil {
+			_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 1<<20))
+			res.Body.Close()
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+	}
+}
+
+// backoff returns the delay before retry number attempt. A Retry-After header of the response
+// takes precedence.
+func (p RetryPolicy) backoff(attempt int, res *http.Response) time.Duration {
+	if res != nil {
+		if seconds, err := strconv.Atoi(res.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+			return time.Duration(seconds) * time.Second
+		}
+	}
+	ceiling := p.BaseDelay << (attempt - 1)
+	if ceiling > p.MaxDelay || ceiling <= 0 {
+		ceiling = p.MaxDelay
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return rand.N(ceiling) + 1
+}
+
+// shouldRetry reports whether a call that ended with res or err may succeed when sent again.
+func shouldRetry(res *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	switch {
+	case res.StatusCode == http.StatusTooManyRequests:
+		return true
+	case res.StatusCode >= http.StatusInternalServerError:
+		return res.StatusCode != http.StatusNotImplemented
+	case res.StatusCode == http.StatusConflict:
+		return inProgress(res)
+	default:
+		return false
+	}
+}
+
+// inProgress reports whether a conflict is another call with the same idempotency key still being
+// handled. The body is left readable.
+func inProgress(res *http.Response) bool {
+	body, err := io.ReadAll(io.LimitReader(res.Body, 1<<20))
+	res.Body.Close()
+	res.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return false
+	}
+	var problem Problem
+	return json.Unmarshal(body, &problem) == nil && problem.Code == CodeIdempotencyInProgress
+}
@@ -0,0 +1,223 @@
+package client
+
+import (
+	"product-catalog-service/pkg/money"
+	"time"
+)
+
+// The types below mirror the schemas of the OpenAPI document served at /v1/openapi.json.
+
+// ProductStatus is the lifecycle status of a product.
+type ProductStatus string
+
+const (
+	ProductStatusDraft     ProductStatus = "draft"
+	ProductStatusScheduled ProductStatus = "scheduled"
+	ProductStatusActive    ProductStatus = "active"
+	ProductStatusArchived  ProductStatus = "archived"
+)
+
+type Product struct {
+	ID          int64         `json:"id,omitempty"`
+	ExternalSKU *string       `json:"external_sku,omitempty"`
+	Name        string        `json:"name"`
+	Description string        `json:"description"`
+	Price       money.Money   `json:"price"`
+	Stock       int           `json:"stock"`
+	CategoryID  *int64        `json:"category_id"`
+	Status      ProductStatus `json:"status,omitempty"`
+	PublishAt   *time.Time    `json:"publish_at,omitempty"`
+	UnpublishAt *time.Time    `json:"unpublish_at,omitempty"`
+	Tags        []string      `json:"tags,omitempty"`
+	Images      []string      `json:"images,omitempty"`
+	DeletedAt   *time.Time    `json:"deleted_at,omitempty"`
+}
+
+type ProductStatusChange struct {
+	Status      ProductStatus `json:"status"`
+	PublishAt   *time.Time    `json:"publish_at"`
+	UnpublishAt *time.Time    `json:"unpublish_at"`
+}
+
+type ProductSearchResult struct {
+	Product    Product           `json:"product"`
+	Score      float64           `json:"score"`
+	Highlights map[string]string `json:"highlights,omitempty"`
+}
+
+// ProductFilter narrows GetAllProducts. Zero fields are not applied.
+type ProductFilter struct {
+	CategoryID int64
+	Tag        string
+	Currency   string // Return prices in this currency
+}
+
+type StockReservation struct {
+	ProductID int64 `json:"product_id"`
+	VariantID int64 `json:"variant_id,omitempty"`
+	Quantity  int   `json:"quantity"`
+}
+
+type StockBatch struct {
+	Stock      map[int64]int `json:"stock"`
+	UnknownIDs []int64       `json:"unknown_ids"`
+}
+
+// ReservationStatus is the state of a cart reservation.
+type ReservationStatus string
+
+const (
+	ReservationReserved  ReservationStatus = "reserved"
+	ReservationReleased  ReservationStatus = "released"
+	ReservationConfirmed ReservationStatus = "confirmed"
+)
+
+type CartReservation struct {
+	ID          string            `json:"reservation_id"`
+	Status      ReservationStatus `json:"status"`
+	Lines       []ReservationLine `json:"lines"`
+	CreatedAt   time.Time         `json:"created_at"`
+	ReleasedAt  *time.Time        `json:"released_at,omitempty"`
+	ConfirmedAt *time.Time        `json:"confirmed_at,omitempty"`
+}
+
+type ReservationLine struct {
+	ProductID int64 `json:"product_id"`
+	VariantID int64 `json:"variant_id,omitempty"`
+	Quantity  int   `json:"quantity"`
+}
+
+// LineAvailability is reported for every line of a rejected cart reservation, see Problem.Lines.
+type LineAvailability struct {
+	ProductID int64  `json:"product_id"`
+	VariantID int64  `json:"variant_id,omitempty"`
+	Requested int    `json:"requested"`
+	Available int    `json:"available"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+type Category struct {
+	ID       int64  `json:"id,omitempty"`
+	ParentID *int64 `json:"parent_id"`
+	Name     string `json:"name"`
+}
+
+type Tag struct {
+	ID   int64  `json:"id,omitempty"`
+	Name string `json:"name"`
+}
+
+type Variant struct {
+	ID         int64             `json:"id,omitempty"`
+	ProductID  int64             `json:"product_id,omitempty"`
+	SKU        string            `json:"sku"`
+	Attributes map[string]string `json:"attributes"`
+	Price      *money.Money      `json:"price"`
+	Stock      int               `json:"stock"`
+}
+
+type ProductMedia struct {
+	ID          int64  `json:"id"`
+	ProductID   int64  `json:"product_id"`
+	URL         string `json:"url"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+	Position    int    `json:"position"`
+}
+
+type PriceChange struct {
+	ID            int64       `json:"id"`
+	ProductID     int64       `json:"product_id"`
+	Price         money.Money `json:"price"`
+	EffectiveFrom time.Time   `json:"effective_from"`
+	EffectiveTo   *time.Time  `json:"effective_to"`
+	AppliedAt     *time.Time  `json:"applied_at,omitempty"`
+	CreatedAt     time.Time   `json:"created_at"`
+}
+
+type PriceChangeRequest struct {
+	Price         money.Money `json:"price"`
+	EffectiveFrom *time.Time  `json:"effective_from"` // Defaults to now
+}
+
+type PriceQuoteLine struct {
+	ProductID int64         `json:"product_id"`
+	VariantID int64         `json:"variant_id,omitempty"`
+	Quantity  int64         `json:"quantity"`
+	MarkUp    money.Percent `json:"markup"`
+	Discount  money.Percent `json:"discount"`
+}
+
+type PriceQuoteRequest struct {
+	Lines    []PriceQuoteLine `json:"lines"`
+	Currency string           `json:"currency,omitempty"`
+}
+
+type PricedLine struct {
+	PriceQuoteLine
+	BasePrice      money.Money `json:"base_price"`
+	MarkUpAmount   money.Money `json:"markup_amount"`
+	DiscountAmount money.Money `json:"discount_amount"`
+	UnitPrice      money.Money `json:"unit_price"`
+	LineTotal      money.Money `json:"line_total"`
+}
+
+type PriceQuote struct {
+	Lines []PricedLine `json:"lines"`
+	Total money.Money  `json:"total"`
+}
+
+type ExchangeRate struct {
+	BaseCurrency  string     `json:"base_currency"`
+	QuoteCurrency string     `json:"quote_currency"`
+	Rate          money.Rate `json:"rate"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+type CurrencyPrice struct {
+	ProductID int64       `json:"product_id"`
+	Price     money.Money `json:"price"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+// ImportOptions controls ImportProducts.
+type ImportOptions struct {
+	Format    string // "csv" or "jsonl"
+	DryRun    bool
+	BatchSize int // Zero uses the server default
+}
+
+type ImportRowError struct {
+	Row         int      `json:"row"`
+	ExternalSKU string   `json:"external_sku,omitempty"`
+	Errors      []string `json:"errors"`
+}
+
+type ImportReport struct {
+	DryRun          bool             `json:"dry_run"`
+	RowsRead        int              `json:"rows_read"`
+	RowsValid       int              `json:"rows_valid"`
+	RowsInvalid     int              `json:"rows_invalid"`
+	RowsUpserted    int              `json:"rows_upserted"`
+	Errors          []ImportRowError `json:"errors"`
+	ErrorsTruncated bool             `json:"errors_truncated,omitempty"`
+}
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// Problem is the problem+json body of an error response.
+type Problem struct {
+	Type      string             `json:"type"`
+	Title     string             `json:"title"`
+	Status    int                `json:"status"`
+	Code      Code               `json:"code"`
+	Detail    string             `json:"detail"`
+	RequestID string             `json:"request_id,omitempty"`
+	Errors    []FieldError       `json:"errors,omitempty"` // Fields that failed validation
+	Lines     []LineAvailability `json:"lines,omitempty"`  // Availability of a rejected cart
+	Report    *ImportReport      `json:"report,omitempty"` // Partial report of a failed import
+}
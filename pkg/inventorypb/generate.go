@@ -0,0 +1,5 @@
+package inventorypb
+
+// The code in this package is generated from proto/inventory/v1/inventory.proto. Output paths
+// follow the go_package option, relative to the module root, so the files land in this directory.
+//go:generate protoc -I ../../proto --go_out=../.. --go_opt=module=product-catalog-service --go-grpc_out=../.. --go-grpc_opt=module=product-catalog-service inventory/v1/inventory.proto
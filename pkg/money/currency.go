@@ -0,0 +1,47 @@
+package money
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrUnknownCurrency is returned for currency codes that are not supported.
+var ErrUnknownCurrency = errors.New("unknown currency")
+
+// currencyExponents maps the supported ISO 4217 currency codes to the number of digits of their minor unit.
+var currencyExponents = map[string]int{
+	"AUD": 2,
+	"BHD": 3,
+	"CNY": 2,
+	"EUR": 2,
+	"GBP": 2,
+	"IDR": 2,
+	"INR": 2,
+	"JPY": 0,
+	"KRW": 0,
+	"KWD": 3,
+	"MYR": 2,
+	"PHP": 2,
+	"SGD": 2,
+	"THB": 2,
+	"USD": 2,
+	"VND": 0,
+}
+
+// NormalizeCurrency upper-cases a currency code and checks that it is supported.
+func NormalizeCurrency(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if _, ok := currencyExponents[code]; !ok {
+		return "", ErrUnknownCurrency
+	}
+	return code, nil
+}
+
+// Exponent returns the number of minor-unit digits of a currency, e.g. 2 for USD and 0 for JPY.
+func Exponent(currency string) (int, error) {
+	exponent, ok := currencyExponents[currency]
+	if !ok {
+		return 0, ErrUnknownCurrency
+	}
+	return exponent, nil
+}
@@ -0,0 +1,235 @@
+// Package money provides exact monetary amounts and percentages.
+//
+// Amounts are held as integer minor units (e.g. cents) together with their ISO 4217 currency
+// code, so sums and totals never pick up floating-point rounding errors. Rounding only happens
+// when a percentage is applied, and always with an explicit RoundingMode.
+package money
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+)
+
+var (
+	// ErrCurrencyMismatch is returned when combining amounts of different currencies.
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+	// ErrOverflow is returned when a result does not fit in int64 minor units.
+	ErrOverflow = errors.New("money amount overflow")
+	// ErrInvalidAmount is returned when a decimal amount cannot be parsed exactly.
+	ErrInvalidAmount = errors.New("invalid money amount")
+)
+
+// Money is an exact amount of a currency. The zero value has no currency and is only
+// meaningful as "no amount".
+type Money struct {
+	// Amount is the value in minor units of Currency, e.g. 1234 for 12.34 USD.
+	Amount int64
+	// Currency is the ISO 4217 code of the amount.
+	Currency string
+}
+
+// New returns an amount of minor units in a currency.
+func New(amount int64, currency string) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+// Parse converts a decimal string such as "12.34" into Money. Amounts with more decimal places
+// than the currency has minor-unit digits are rejected instead of being rounded.
+func Parse(amount, currency string) (Money, error) {
+	currency, err := NormalizeCurrency(currency)
+	if err != nil {
+		return Money{}, err
+	}
+	exponent, _ := Exponent(currency)
+
+	minor, err := parseDecimal(amount, exponent)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: minor, Currency: currency}, nil
+}
+
+// Decimal formats the amount as a decimal string in major units, e.g. "12.34".
+func (m Money) Decimal() string {
+	exponent, err := Exponent(m.Currency)
+	if err != nil {
+		exponent = 0
+	}
+	return formatDecimal(m.Amount, exponent)
+}
+
+// String formats the amount with its currency, e.g. "12.34 USD".
+func (m Money) String() string {
+	return m.Decimal() + " " + m.Currency
+}
+
+// IsZero reports whether the amount is zero.
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+// IsNegative reports whether the amount is below zero.
+func (m Money) IsNegative() bool {
+	return m.Amount < 0
+}
+
+// Add returns m + other. Both amounts must be in the same currency.
+func (m Money) Add(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, ErrCurrencyMismatch
+	}
+	sum := m.Amount + other.Amount
+	if (other.Amount > 0 && sum < m.Amount) || (other.Amount < 0 && sum > m.Amount) {
+		return Money{}, ErrOverflow
+	}
+	return Money{Amount: sum, Currency: m.Currency}, nil
+}
+
+// Sub returns m - other. Both amounts must be in the same currency.
+func (m Money) Sub(other Money) (Money, error) {
+	if other.Amount == math.MinInt64 {
+		return Money{}, ErrOverflow
+	}
+	return m.Add(Money{Amount: -other.Amount, Currency: other.Currency})
+}
+
+// Mul returns m multiplied by a quantity.
+func (m Money) Mul(quantity int64) (Money, error) {
+	product := new(big.Int).Mul(big.NewInt(m.Amount), big.NewInt(quantity))
+	if !product.IsInt64() {
+		return Money{}, ErrOverflow
+	}
+	return Money{Amount: product.Int64(), Currency: m.Currency}, nil
+}
+
+// Cmp compares m and other, returning -1, 0 or +1. Both amounts must be in the same currency.
+func (m Money) Cmp(other Money) (int, error) {
+	if m.Currency != other.Currency {
+		return 0, ErrCurrencyMismatch
+	}
+	switch {
+	case m.Amount < other.Amount:
+		return -1, nil
+	case m.Amount > other.Amount:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// Percentage returns p percent of m, rounded to a whole minor unit with mode.
+func (m Money) Percentage(p Percent, mode RoundingMode) (Money, error) {
+	num := new(big.Int).Mul(big.NewInt(m.Amount), big.NewInt(int64(p)))
+	result := divRound(num, big.NewInt(100*percentUnit), mode)
+	if !result.IsInt64() {
+		return Money{}, ErrOverflow
+	}
+	return Money{Amount: result.Int64(), Currency: m.Currency}, nil
+}
+
+type moneyJSON struct {
+	Amount   json.RawMessage `json:"amount"`
+	Currency string          `json:"currency"`
+}
+
+// MarshalJSON encodes Money as {"amount": "12.34", "currency": "USD"}. The amount is a string so
+// clients do not read it into a float.
+func (m Money) MarshalJSON() ([]byte, error) {
+	amount, err := json.Marshal(m.Decimal())
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(moneyJSON{Amount: amount, Currency: m.Currency})
+}
+
+// UnmarshalJSON decodes {"amount": "12.34", "currency": "USD"}. The amount may also be a JSON
+// number; its literal text is parsed exactly.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var raw moneyJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	amount, err := decimalLiteral(raw.Amount)
+	if err != nil {
+		return err
+	}
+
+	parsed, err := Parse(amount, raw.Currency)
+	if err != nil {
+		return fmt.Errorf("money %s %q: %w", amount, raw.Currency, err)
+	}
+	*m = parsed
+	return nil
+}
+
+// decimalLiteral returns the text of a JSON string or number holding a decimal value.
+func decimalLiteral(raw json.RawMessage) (string, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 {
+		return "", ErrInvalidAmount
+	}
+	if raw[0] == '"' {
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return "", err
+		}
+		return s, nil
+	}
+	return string(raw), nil
+}
+
+// parseDecimal converts a decimal string into an integer scaled by 10^scale.
+func parseDecimal(s string, scale int) (int64, error) {
+	s = strings.TrimSpace(s)
+	negative := false
+	if strings.HasPrefix(s, "-") || strings.HasPrefix(s, "+") {
+		negative = s[0] == '-'
+		s = s[1:]
+	}
+
+	whole, fraction, hasPoint := strings.Cut(s, ".")
+	if whole == "" && fraction == "" || hasPoint && fraction == "" {
+		return 0, ErrInvalidAmount
+	}
+	if len(fraction) > scale {
+		return 0, fmt.Errorf("%w: at most %d decimal places are allowed", ErrInvalidAmount, scale)
+	}
+	digits := whole + fraction + strings.Repeat("0", scale-len(fraction))
+	for _, r := range digits {
+		if r < '0' || r > '9' {
+			return 0, ErrInvalidAmount
+		}
+	}
+
+	value, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return 0, ErrInvalidAmount
+	}
+	if negative {
+		value.Neg(value)
+	}
+	if !value.IsInt64() {
+		return 0, ErrOverflow
+	}
+	return value.Int64(), nil
+}
+
+// formatDecimal formats an integer scaled by 10^scale as a decimal string.
+func formatDecimal(value int64, scale int) string {
+	digits := new(big.Int).Abs(big.NewInt(value)).String()
+	sign := ""
+	if value < 0 {
+		sign = "-"
+	}
+	if scale == 0 {
+		return sign + digits
+	}
+	if len(digits) <= scale {
+		digits = strings.Repeat("0", scale-len(digits)+1) + digits
+	}
+	return sign + digits[:len(digits)-scale] + "." + digits[len(digits)-scale:]
+}
@@ -0,0 +1,228 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestPercentageRounding(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		amount  int64
+		percent Percent
+		mode    RoundingMode
+		want    int64
+	}{
+		{"exact", 1000, 125000, RoundHalfUp, 125},
+		{"half up", 10, 50000, RoundHalfUp, 1},
+		{"half even down", 10, 50000, RoundHalfEven, 0},
+		{"half even up", 30, 50000, RoundHalfEven, 2},
+		{"half down", 30, 50000, RoundDown, 1},
+		{"above half", 13, 50000, RoundHalfEven, 1},
+		{"below half", 9, 50000, RoundHalfUp, 0},
+		{"negative half up", -10, 50000, RoundHalfUp, -1},
+		{"negative half even", -10, 50000, RoundHalfEven, 0},
+		{"negative half even odd", -30, 50000, RoundHalfEven, -2},
+		{"negative down", -30, 50000, RoundDown, -1},
+		{"negative percent", 10, -50000, RoundHalfUp, -1},
+		{"fractional percent", 10000, 1, RoundHalfUp, 0},
+		{"fractional percent half", 500000, 1, RoundHalfUp, 1},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := New(tc.amount, "USD").Percentage(tc.percent, tc.mode)
+			if err != nil {
+				t.Fatalf("Percentage: %v", err)
+			}
+			if got != New(tc.want, "USD") {
+				t.Errorf("%d × %s%% = %v, want %d", tc.amount, tc.percent, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	usd := func(amount int64) Money { return New(amount, "USD") }
+	for _, tc := range []struct {
+		name    string
+		op      func() (Money, error)
+		want    Money
+		wantErr error
+	}{
+		{"add", func() (Money, error) { return usd(150).Add(usd(-200)) }, usd(-50), nil},
+		{"sub", func() (Money, error) { return usd(150).Sub(usd(200)) }, usd(-50), nil},
+		{"mul", func() (Money, error) { return usd(-150).Mul(3) }, usd(-450), nil},
+		{"add mismatch", func() (Money, error) { return usd(1).Add(New(1, "EUR")) }, Money{}, ErrCurrencyMismatch},
+		{"sub mismatch", func() (Money, error) { return usd(1).Sub(New(1, "EUR")) }, Money{}, ErrCurrencyMismatch},
+		{"add overflow", func() (Money, error) { return usd(math.MaxInt64).Add(usd(1)) }, Money{}, ErrOverflow},
+		{"add underflow", func() (Money, error) { return usd(math.MinInt64).Add(usd(-1)) }, Money{}, ErrOverflow},
+		{"sub overflow", func() (Money, error) { return usd(0).Sub(usd(math.MinInt64)) }, Money{}, ErrOverflow},
+		{"mul overflow", func() (Money, error) { return usd(math.MaxInt64/2 + 1).Mul(2) }, Money{}, ErrOverflow},
+		{"mul negative overflow", func() (Money, error) { return usd(math.MinInt64).Mul(-1) }, Money{}, ErrOverflow},
+		{"percentage overflow", func() (Money, error) { return usd(math.MaxInt64).Percentage(2000000, RoundHalfUp) }, Money{}, ErrOverflow},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.op()
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("result = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCmp(t *testing.T) {
+	if c, err := New(100, "USD").Cmp(New(99, "USD")); err != nil || c != 1 {
+		t.Errorf("Cmp = %d, %v, want 1", c, err)
+	}
+	if c, err := New(-100, "USD").Cmp(New(99, "USD")); err != nil || c != -1 {
+		t.Errorf("Cmp = %d, %v, want -1", c, err)
+	}
+	if _, err := New(100, "USD").Cmp(New(100, "EUR")); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Cmp across currencies = %v, want %v", err, ErrCurrencyMismatch)
+	}
+}
+
+func TestParse(t *testing.T) {
+	for _, tc := range []struct {
+		amount   string
+		currency string
+		want     Money
+		wantErr  error
+	}{
+		{"12.34", "usd", New(1234, "USD"), nil},
+		{"12.3", "USD", New(1230, "USD"), nil},
+		{"-0.05", "USD", New(-5, "USD"), nil},
+		{"+7", "USD", New(700, "USD"), nil},
+		{"1500", "JPY", New(1500, "JPY"), nil},
+		{"1.234", "KWD", New(1234, "KWD"), nil},
+		{"92233720368547758.07", "USD", New(math.MaxInt64, "USD"), nil},
+		{"12.345", "USD", Money{}, ErrInvalidAmount},
+		{"1.5", "JPY", Money{}, ErrInvalidAmount},
+		{"12.", "USD", Money{}, ErrInvalidAmount},
+		{"", "USD", Money{}, ErrInvalidAmount},
+		{"1e3", "USD", Money{}, ErrInvalidAmount},
+		{"92233720368547758.08", "USD", Money{}, ErrOverflow},
+		{"1", "XXX", Money{}, ErrUnknownCurrency},
+	} {
+		t.Run(tc.amount+" "+tc.currency, func(t *testing.T) {
+			got, err := Parse(tc.amount, tc.currency)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("Parse = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDecimal(t *testing.T) {
+	for _, tc := range []struct {
+		money Money
+		want  string
+	}{
+		{New(1234, "USD"), "12.34"},
+		{New(5, "USD"), "0.05"},
+		{New(-5, "USD"), "-0.05"},
+		{New(-1500, "JPY"), "-1500"},
+		{New(1, "KWD"), "0.001"},
+		{New(math.MinInt64, "USD"), "-92233720368547758.08"},
+	} {
+		if got := tc.money.Decimal(); got != tc.want {
+			t.Errorf("%d %s Decimal() = %q, want %q", tc.money.Amount, tc.money.Currency, got, tc.want)
+		}
+	}
+}
+
+func TestJSON(t *testing.T) {
+	data, err := json.Marshal(New(-1234, "USD"))
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if string(data) != `{"amount":"-12.34","currency":"USD"}` {
+		t.Errorf("Marshal = %s", data)
+	}
+
+	for _, input := range []string{`{"amount":"-12.34","currency":"USD"}`, `{"amount":-12.34,"currency":"usd"}`} {
+		var m Money
+		if err := json.Unmarshal([]byte(input), &m); err != nil {
+			t.Fatalf("Unmarshal %s: %v", input, err)
+		}
+		if m != New(-1234, "USD") {
+			t.Errorf("Unmarshal %s = %v, want -12.34 USD", input, m)
+		}
+	}
+
+	var m Money
+	if err := json.Unmarshal([]byte(`{"amount":0.1234,"currency":"USD"}`), &m); !errors.Is(err, ErrInvalidAmount) {
+		t.Errorf("Unmarshal with too many decimals = %v, want %v", err, ErrInvalidAmount)
+	}
+}
+
+func TestParsePercent(t *testing.T) {
+	for _, tc := range []struct {
+		s       string
+		want    Percent
+		wantErr error
+	}{
+		{"12.5", 125000, nil},
+		{"-0.0001", -1, nil},
+		{"100", 1000000, nil},
+		{"0.00001", 0, ErrInvalidAmount},
+		{"ten", 0, ErrInvalidAmount},
+	} {
+		got, err := ParsePercent(tc.s)
+		if !errors.Is(err, tc.wantErr) || got != tc.want {
+			t.Errorf("ParsePercent(%q) = %d, %v, want %d, %v", tc.s, got, err, tc.want, tc.wantErr)
+		}
+		if err == nil && got.String() != tc.s {
+			t.Errorf("Percent(%d).String() = %q, want %q", got, got.String(), tc.s)
+		}
+	}
+}
+
+func TestConvert(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		from     Money
+		currency string
+		rate     string
+		mode     RoundingMode
+		want     Money
+	}{
+		{"more minor digits", New(100, "USD"), "IDR", "15800.5", RoundHalfUp, New(1580050, "IDR")},
+		{"fewer minor digits", New(1999, "USD"), "JPY", "150", RoundHalfUp, New(2999, "JPY")},
+		{"fewer minor digits half even", New(1999, "USD"), "JPY", "150", RoundHalfEven, New(2998, "JPY")},
+		{"round up", New(1, "JPY"), "USD", "0.0066666667", RoundHalfUp, New(1, "USD")},
+		{"round down", New(1, "JPY"), "USD", "0.0066666667", RoundDown, New(0, "USD")},
+		{"negative", New(-1, "JPY"), "USD", "0.0066666667", RoundHalfUp, New(-1, "USD")},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rate, err := ParseRate(tc.rate)
+			if err != nil {
+				t.Fatalf("ParseRate: %v", err)
+			}
+			got, err := tc.from.Convert(tc.currency, rate, tc.mode)
+			if err != nil {
+				t.Fatalf("Convert: %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("Convert = %v, want %v", got, tc.want)
+			}
+		})
+	}
+
+	if _, err := New(1, "USD").Convert("XXX", 1, RoundHalfUp); !errors.Is(err, ErrUnknownCurrency) {
+		t.Errorf("Convert to unknown currency = %v, want %v", err, ErrUnknownCurrency)
+	}
+	if _, err := New(math.MaxInt64, "USD").Convert("IDR", Rate(2*rateUnit), RoundHalfUp); !errors.Is(err, ErrOverflow) {
+		t.Errorf("Convert overflow = %v, want %v", err, ErrOverflow)
+	}
+	if _, err := ParseRate("0"); !errors.Is(err, ErrInvalidRate) {
+		t.Errorf("ParseRate(0) = %v, want %v", err, ErrInvalidRate)
+	}
+}
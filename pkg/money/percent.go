@@ -0,0 +1,52 @@
+package money
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// percentDigits is the number of decimal places a Percent keeps.
+const percentDigits = 4
+
+// percentUnit is the number of Percent units in one percent.
+const percentUnit = 10000
+
+// Percent is an exact percentage with four decimal places, stored as ten-thousandths of a percent:
+// 12.5% is Percent(125000).
+type Percent int64
+
+// ParsePercent converts a decimal string such as "12.5" into a Percent. More than four decimal
+// places are rejected instead of being rounded.
+func ParsePercent(s string) (Percent, error) {
+	value, err := parseDecimal(s, percentDigits)
+	if err != nil {
+		return 0, err
+	}
+	return Percent(value), nil
+}
+
+// String formats the percentage without trailing zeros, e.g. "12.5".
+func (p Percent) String() string {
+	s := formatDecimal(int64(p), percentDigits)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+// MarshalJSON encodes a Percent as a decimal string, e.g. "12.5".
+func (p Percent) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.String())
+}
+
+// UnmarshalJSON decodes a Percent from a decimal string or a JSON number, parsed exactly.
+func (p *Percent) UnmarshalJSON(data []byte) error {
+	literal, err := decimalLiteral(data)
+	if err != nil {
+		return err
+	}
+	parsed, err := ParsePercent(literal)
+	if err != nil {
+		return err
+	}
+	*p = parsed
+	return nil
+}
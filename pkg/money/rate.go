@@ -0,0 +1,112 @@
+package money
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// rateDigits is the number of decimal places a Rate keeps.
+const rateDigits = 10
+
+// rateUnit is the number of Rate units in a rate of 1.
+const rateUnit = 10000000000
+
+// ErrInvalidRate is returned for exchange rates that are not positive decimals.
+var ErrInvalidRate = errors.New("invalid exchange rate")
+
+// Rate is an exact exchange rate with ten decimal places: the number of major units of the quote
+// currency one major unit of the base currency buys. 0.0000625 is Rate(625000).
+type Rate int64
+
+// ParseRate converts a positive decimal string such as "15800.5" into a Rate.
+func ParseRate(s string) (Rate, error) {
+	value, err := parseDecimal(s, rateDigits)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrInvalidRate, err)
+	}
+	if value <= 0 {
+		return 0, fmt.Errorf("%w: must be positive", ErrInvalidRate)
+	}
+	return Rate(value), nil
+}
+
+// String formats the rate without trailing zeros, e.g. "15800.5".
+func (r Rate) String() string {
+	s := formatDecimal(int64(r), rateDigits)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+// MarshalJSON encodes a Rate as a decimal string.
+func (r Rate) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.String())
+}
+
+// UnmarshalJSON decodes a Rate from a decimal string or a JSON number, parsed exactly.
+func (r *Rate) UnmarshalJSON(data []byte) error {
+	literal, err := decimalLiteral(data)
+	if err != nil {
+		return err
+	}
+	parsed, err := ParseRate(literal)
+	if err != nil {
+		return err
+	}
+	*r = parsed
+	return nil
+}
+
+// Value implements driver.Valuer, storing the rate as a DECIMAL string.
+func (r Rate) Value() (driver.Value, error) {
+	return formatDecimal(int64(r), rateDigits), nil
+}
+
+// Scan implements sql.Scanner for DECIMAL columns.
+func (r *Rate) Scan(value interface{}) error {
+	var s string
+	switch v := value.(type) {
+	case []byte:
+		s = string(v)
+	case string:
+		s = v
+	default:
+		return fmt.Errorf("%w: unsupported type %T", ErrInvalidRate, value)
+	}
+	parsed, err := ParseRate(s)
+	if err != nil {
+		return err
+	}
+	*r = parsed
+	return nil
+}
+
+// Convert returns m in another currency at the given rate, rounded to a whole minor unit with mode.
+func (m Money) Convert(currency string, rate Rate, mode RoundingMode) (Money, error) {
+	fromExponent, err := Exponent(m.Currency)
+	if err != nil {
+		return Money{}, err
+	}
+	toExponent, err := Exponent(currency)
+	if err != nil {
+		return Money{}, err
+	}
+
+	num := new(big.Int).Mul(big.NewInt(m.Amount), big.NewInt(int64(rate)))
+	den := big.NewInt(rateUnit)
+	// Rescale from the minor unit of the source currency to the minor unit of the target one.
+	if shift := toExponent - fromExponent; shift > 0 {
+		num.Mul(num, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(shift)), nil))
+	} else if shift < 0 {
+		den.Mul(den, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(-shift)), nil))
+	}
+
+	result := divRound(num, den, mode)
+	if !result.IsInt64() {
+		return Money{}, ErrOverflow
+	}
+	return Money{Amount: result.Int64(), Currency: currency}, nil
+}
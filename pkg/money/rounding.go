@@ -0,0 +1,39 @@
+package money
+
+import "math/big"
+
+// RoundingMode decides how a result that falls between two minor units is rounded.
+type RoundingMode int
+
+const (
+	// RoundHalfUp rounds halves away from zero: 0.5 becomes 1 and -0.5 becomes -1.
+	RoundHalfUp RoundingMode = iota
+	// RoundHalfEven rounds halves to the nearest even unit (banker's rounding): 0.5 becomes 0, 1.5 becomes 2.
+	RoundHalfEven
+	// RoundDown truncates towards zero.
+	RoundDown
+)
+
+// DefaultRounding is the rounding mode used for prices unless stated otherwise.
+const DefaultRounding = RoundHalfUp
+
+// divRound returns num / den rounded with mode. den must be positive.
+func divRound(num, den *big.Int, mode RoundingMode) *big.Int {
+	quo, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+	if rem.Sign() == 0 || mode == RoundDown {
+		return quo
+	}
+
+	// Compare twice the remainder with the divisor to find out which side of the half it is on.
+	twiceRem := new(big.Int).Abs(rem)
+	twiceRem.Lsh(twiceRem, 1)
+	cmp := twiceRem.Cmp(den)
+	roundAway := cmp > 0 || (cmp == 0 && (mode == RoundHalfUp || quo.Bit(0) == 1))
+	if !roundAway {
+		return quo
+	}
+	if num.Sign() < 0 {
+		return quo.Sub(quo, big.NewInt(1))
+	}
+	return quo.Add(quo, big.NewInt(1))
+}
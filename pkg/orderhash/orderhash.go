@@ -0,0 +1,173 @@
+// Package orderhash signs and verifies order messages exchanged between the order service and the
+// product service.
+//
+// An order and each of its lines are turned into a canonical byte form and signed with
+// HMAC-SHA256. A hash has the form "<key id>:<hex digest>", so the signing key can be rotated:
+// producers sign with the current key while consumers still accept every key in their keyring.
+// The producer side only needs this package and the shared keys to compute matching hashes.
+package orderhash
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"product-catalog-service/pkg/money"
+	"strconv"
+	"strings"
+)
+
+// canonicalVersion prefixes the canonical form so it can evolve without ambiguity.
+const canonicalVersion = "orderhash/v1"
+
+var (
+	// ErrMalformedHash is returned when a hash is not of the form "<key id>:<hex digest>".
+	ErrMalformedHash = errors.New("malformed order hash")
+	// ErrUnknownKey is returned when a hash was made with a key that is not in the keyring.
+	ErrUnknownKey = errors.New("unknown order hash key")
+	// ErrHashMismatch is returned when a hash does not match the signed content.
+	ErrHashMismatch = errors.New("order hash mismatch")
+)
+
+// Order is the signed content of an order message.
+type Order struct {
+	ID         int64
+	UserID     int64
+	Quantity   int64
+	TotalPrice money.Money
+	Status     string
+	Lines      []Line
+}
+
+// Line is the signed content of one order line.
+type Line struct {
+	OrderID    int64
+	ProductID  int64
+	VariantID  int64
+	Quantity   int64
+	MarkUp     money.Percent
+	Discount   money.Percent
+	FinalPrice money.Money
+}
+
+// CanonicalOrder returns the canonical form of an order: one "name=value" field per line, in a
+// fixed order, followed by the canonical form of every line in order. Strings are quoted with Go
+// escaping and amounts are written as integer minor units with their currency, so equal orders
+// always produce identical bytes.
+func CanonicalOrder(o Order) []byte {
+	var b bytes.Buffer
+	b.WriteString(canonicalVersion + " order\n")
+	writeField(&b, "id", strconv.FormatInt(o.ID, 10))
+	writeField(&b, "user_id", strconv.FormatInt(o.UserID, 10))
+	writeField(&b, "quantity", strconv.FormatInt(o.Quantity, 10))
+	writeField(&b, "total_price", canonicalMoney(o.TotalPrice))
+	writeField(&b, "status", strconv.Quote(o.Status))
+	writeField(&b, "lines", strconv.Itoa(len(o.Lines)))
+	for _, line := range o.Lines {
+		b.Write(CanonicalLine(line))
+	}
+	return b.Bytes()
+}
+
+// CanonicalLine returns the canonical form of an order line.
+func CanonicalLine(l Line) []byte {
+	var b bytes.Buffer
+	b.WriteString(canonicalVersion + " line\n")
+	writeField(&b, "order_id", strconv.FormatInt(l.OrderID, 10))
+	writeField(&b, "product_id", strconv.FormatInt(l.ProductID, 10))
+	writeField(&b, "variant_id", strconv.FormatInt(l.VariantID, 10))
+	writeField(&b, "quantity", strconv.FormatInt(l.Quantity, 10))
+	writeField(&b, "markup", strconv.FormatInt(int64(l.MarkUp), 10))
+	writeField(&b, "discount", strconv.FormatInt(int64(l.Discount), 10))
+	writeField(&b, "final_price", canonicalMoney(l.FinalPrice))
+	return b.Bytes()
+}
+
+func writeField(b *bytes.Buffer, name, value string) {
+	b.WriteString(name)
+	b.WriteByte('=')
+	b.WriteString(value)
+	b.WriteByte('\n')
+}
+
+func canonicalMoney(m money.Money) string {
+	return strconv.FormatInt(m.Amount, 10) + " " + strconv.Quote(m.Currency)
+}
+
+// Keyring holds the shared HMAC keys by key ID and the ID of the key used for signing.
+type Keyring struct {
+	currentKeyID string
+	keys         map[string][]byte
+}
+
+// NewKeyring creates a keyring that signs with currentKeyID and verifies with any key in keys.
+func NewKeyring(currentKeyID string, keys map[string][]byte) (*Keyring, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("orderhash: at least one key is required")
+	}
+	copied := make(map[string][]byte, len(keys))
+	for id, key := range keys {
+		if id == "" || strings.Contains(id, ":") {
+			return nil, fmt.Errorf("orderhash: invalid key ID %q", id)
+		}
+		if len(key) == 0 {
+			return nil, fmt.Errorf("orderhash: key %q is empty", id)
+		}
+		copied[id] = append([]byte(nil), key...)
+	}
+	if _, ok := copied[currentKeyID]; !ok {
+		return nil, fmt.Errorf("orderhash: current key %q is not in the keyring", currentKeyID)
+	}
+	return &Keyring{currentKeyID: currentKeyID, keys: copied}, nil
+}
+
+// SignOrder returns the hash of an order made with the current key.
+func (k *Keyring) SignOrder(o Order) string {
+	return k.sign(CanonicalOrder(o))
+}
+
+// SignLine returns the hash of an order line made with the current key.
+func (k *Keyring) SignLine(l Line) string {
+	return k.sign(CanonicalLine(l))
+}
+
+// VerifyOrder checks the hash of an order.
+func (k *Keyring) VerifyOrder(o Order, hash string) error {
+	return k.verify(CanonicalOrder(o), hash)
+}
+
+// VerifyLine checks the hash of an order line.
+func (k *Keyring) VerifyLine(l Line, hash string) error {
+	return k.verify(CanonicalLine(l), hash)
+}
+
+func (k *Keyring) sign(content []byte) string {
+	return k.currentKeyID + ":" + hex.EncodeToString(digest(k.keys[k.currentKeyID], content))
+}
+
+func (k *Keyring) verify(content []byte, hash string) error {
+	keyID, encoded, ok := strings.Cut(hash, ":")
+	if !ok {
+		return ErrMalformedHash
+	}
+	signature, err := hex.DecodeString(encoded)
+	if err != nil {
+		return ErrMalformedHash
+	}
+	key, ok := k.keys[keyID]
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownKey, keyID)
+	}
+	if !hmac.Equal(signature, digest(key, content)) {
+		return ErrHashMismatch
+	}
+	return nil
+}
+
+func digest(key, content []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(content)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,132 @@
+package orderhash
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"product-catalog-service/pkg/money"
+	"strings"
+	"testing"
+)
+
+func testOrder() Order {
+	return Order{
+		ID:         42,
+		UserID:     7,
+		Quantity:   3,
+		TotalPrice: money.New(4500, "USD"),
+		Status:     "created",
+		Lines: []Line{
+			{OrderID: 42, ProductID: 1, Quantity: 1, FinalPrice: money.New(1500, "USD")},
+			{OrderID: 42, ProductID: 2, VariantID: 5, Quantity: 2, MarkUp: 100000, Discount: 50000, FinalPrice: money.New(3000, "USD")},
+		},
+	}
+}
+
+func newKeyring(t *testing.T, currentKeyID string, keys map[string]string) *Keyring {
+	t.Helper()
+	secrets := make(map[string][]byte, len(keys))
+	for id, secret := range keys {
+		secrets[id] = []byte(secret)
+	}
+	keyring, err := NewKeyring(currentKeyID, secrets)
+	if err != nil {
+		t.Fatalf("NewKeyring: %v", err)
+	}
+	return keyring
+}
+
+func TestSignLineMatchesCanonicalForm(t *testing.T) {
+	line := testOrder().Lines[1]
+	want := "orderhash/v1 line\norder_id=42\nproduct_id=2\nvariant_id=5\nquantity=2\nmarkup=100000\ndiscount=50000\nfinal_price=3000 \"USD\"\n"
+	if got := string(CanonicalLine(line)); got != want {
+		t.Fatalf("CanonicalLine = %q, want %q", got, want)
+	}
+
+	// Producers compute the same hash from the canonical form with any HMAC-SHA256 implementation.
+	mac := hmac.New(sha256.New, []byte("secret-1"))
+	mac.Write([]byte(want))
+	wantHash := "k1:" + hex.EncodeToString(mac.Sum(nil))
+	if got := newKeyring(t, "k1", map[string]string{"k1": "secret-1"}).SignLine(line); got != wantHash {
+		t.Errorf("SignLine = %s, want %s", got, wantHash)
+	}
+}
+
+func TestVerifyAcceptsCurrentAndRetiredKeys(t *testing.T) {
+	before := newKeyring(t, "k1", map[string]string{"k1": "secret-1"})
+	after := newKeyring(t, "k2", map[string]string{"k1": "secret-1", "k2": "secret-2"})
+	order := testOrder()
+
+	signedBefore := before.SignOrder(order)
+	signedAfter := after.SignOrder(order)
+	if !strings.HasPrefix(signedBefore, "k1:") || !strings.HasPrefix(signedAfter, "k2:") {
+		t.Fatalf("hashes %s and %s are not made with the current keys", signedBefore, signedAfter)
+	}
+
+	for name, hash := range map[string]string{"current key": signedAfter, "retired key": signedBefore} {
+		if err := after.VerifyOrder(order, hash); err != nil {
+			t.Errorf("VerifyOrder with %s: %v", name, err)
+		}
+	}
+	for _, line := range order.Lines {
+		if err := after.VerifyLine(line, before.SignLine(line)); err != nil {
+			t.Errorf("VerifyLine with retired key: %v", err)
+		}
+	}
+}
+
+func TestVerifyRejects(t *testing.T) {
+	keyring := newKeyring(t, "k2", map[string]string{"k1": "secret-1", "k2": "secret-2"})
+	order := testOrder()
+	hash := keyring.SignOrder(order)
+	_, digest, _ := strings.Cut(hash, ":")
+
+	tampered := func(change func(o *Order)) Order {
+		o := testOrder()
+		o.Lines = append([]Line(nil), o.Lines...)
+		change(&o)
+		return o
+	}
+	for _, tc := range []struct {
+		name    string
+		order   Order
+		hash    string
+		wantErr error
+	}{
+		{"unknown key", order, newKeyring(t, "k3", map[string]string{"k3": "secret-3"}).SignOrder(order), ErrUnknownKey},
+		{"listed key with another secret", order, newKeyring(t, "k1", map[string]string{"k1": "other"}).SignOrder(order), ErrHashMismatch},
+		{"hash relabelled with another key", order, "k1:" + digest, ErrHashMismatch},
+		{"quantity changed", tampered(func(o *Order) { o.Quantity++ }), hash, ErrHashMismatch},
+		{"status changed", tampered(func(o *Order) { o.Status = "cancelled" }), hash, ErrHashMismatch},
+		{"total currency changed", tampered(func(o *Order) { o.TotalPrice.Currency = "IDR" }), hash, ErrHashMismatch},
+		{"line price changed", tampered(func(o *Order) { o.Lines[0].FinalPrice.Amount-- }), hash, ErrHashMismatch},
+		{"line dropped", tampered(func(o *Order) { o.Lines = o.Lines[:1] }), hash, ErrHashMismatch},
+		{"digest changed", order, "k2:" + strings.Repeat("0", len(digest)), ErrHashMismatch},
+		{"no key ID", order, digest, ErrMalformedHash},
+		{"digest not hex", order, "k2:xyz", ErrMalformedHash},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := keyring.VerifyOrder(tc.order, tc.hash); !errors.Is(err, tc.wantErr) {
+				t.Errorf("VerifyOrder = %v, want %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewKeyringRejectsInvalidKeys(t *testing.T) {
+	for name, tc := range map[string]struct {
+		current string
+		keys    map[string][]byte
+	}{
+		"no keys":                 {"k1", nil},
+		"current key not listed":  {"k2", map[string][]byte{"k1": []byte("secret")}},
+		"empty secret":            {"k1", map[string][]byte{"k1": nil}},
+		"key ID with a separator": {"k:1", map[string][]byte{"k:1": []byte("secret")}},
+		"empty key ID":            {"", map[string][]byte{"": []byte("secret")}},
+	} {
+		if _, err := NewKeyring(tc.current, tc.keys); err == nil {
+			t.Errorf("NewKeyring with %s succeeded, want an error", name)
+		}
+	}
+}
@@ -2,14 +2,137 @@ package routes
 
 import (
 	"product-catalog-service/internal/api"
+	infrastructure "product-catalog-service/middleware"
+	"time"
 
 	"github.com/labstack/echo/v4"
 )
 
-func SetupRoutes(e *echo.Echo, ph api.ProductHandler) {
-	e.GET("/product/:id/stock", ph.GetProductStock)    // Get product stock by ID
-	e.POST("/product/reserve", ph.ReserveProductStock) // Reserve product stock
-	e.POST("/product/release", ph.ReleaseProductStock) // Release product stock
-	e.GET("/products", ph.GetAllProducts)
-	e.POST("/product", ph.CreateProduct)
+// V1 is the path prefix of version 1 of the API.
+const V1 = "/v1"
+
+// V1Handlers are the handlers of version 1 of the API. A later version that changes the shape of
+// requests or responses gets its own handler set and register function, with handlers built on the
+// same services, and is mounted next to V1 under its own prefix.
+type V1Handlers struct {
+	Product     api.ProductHandler
+	Category    api.CategoryHandler
+	Variant     api.VariantHandler
+	Media       api.MediaHandler
+	Import      api.ImportHandler
+	Export      api.ExportHandler
+	Pricing     api.PricingHandler
+	Price       api.PriceHandler
+	Currency    api.CurrencyHandler
+	StockStream api.StockStreamHandler
+	Cache       api.CacheHandler
+}
+
+// Deprecation is when the unversioned aliases of the V1 routes were deprecated and when they are
+// removed. A zero Sunset leaves the removal date unannounced.
+type Deprecation struct {
+	DeprecatedAt time.Time
+	Sunset       time.Time
+}
+
+// SetupRoutes mounts version 1 of the API under V1. The same routes stay reachable at the root,
+// where they were served before versioning, as deprecated aliases that point clients to V1.
+func SetupRoutes(e *echo.Echo, v1 V1Handlers, deprecation Deprecation) {
+	registerV1(e.Group(V1), v1)
+	registerV1(&deprecatedRouter{
+		router:     e,
+		middleware: infrastructure.Deprecated(deprecation.DeprecatedAt, deprecation.Sunset, V1),
+	}, v1)
+}
+
+// router is the part of echo.Echo and echo.Group that routes are registered with.
+type router interface {
+	GET(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	POST(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	PUT(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	DELETE(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+}
+
+// deprecatedRouter registers routes with the deprecation middleware. Unlike group middleware, it
+// runs only for the registered routes and not for unmatched paths.
+type deprecatedRouter struct {
+	router     router
+	middleware echo.MiddlewareFunc
+}
+
+func (d *deprecatedRouter) GET(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route {
+	return d.router.GET(path, h, append([]echo.MiddlewareFunc{d.middleware}, m...)...)
+}
+
+func (d *deprecatedRouter) POST(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route {
+	return d.router.POST(path, h, append([]echo.MiddlewareFunc{d.middleware}, m...)...)
+}
+
+func (d *deprecatedRouter) PUT(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route {
+	return d.router.PUT(path, h, append([]echo.MiddlewareFunc{d.middleware}, m...)...)
+}
+
+func (d *deprecatedRouter) DELETE(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route {
+	return d.router.DELETE(path, h, append([]echo.MiddlewareFunc{d.middleware}, m...)...)
+}
+
+// registerV1 registers the routes of version 1 of the API with r.
+func registerV1(r router, h V1Handlers) {
+	r.GET("/openapi.json", api.GetOpenAPISpec) // OpenAPI document of every route below
+
+	r.GET("/product/:id/stock", h.Product.GetProductStock)        // Get product stock by ID
+	r.POST("/products/stock\\:batch", h.Product.GetProductStocks) // Get the stock of many products at once
+	r.POST("/product/reserve", h.Product.ReserveProductStock)     // Reserve product stock
+	r.POST("/product/release", h.Product.ReleaseProductStock)     // Release product stock
+	r.POST("/cart/reserve", h.Product.ReserveCart)                // Reserve every line of a cart, or none
+	r.GET("/cart/reservation/:id", h.Product.GetReservation)
+	r.POST("/cart/reservation/:id/release", h.Product.ReleaseReservation) // Return the stock of a cart reservation
+	r.POST("/cart/reservation/:id/confirm", h.Product.ConfirmReservation) // Make a cart reservation final
+	r.GET("/products/stock/stream", h.StockStream.StreamStock)            // Server-Sent Events of stock changes
+	r.GET("/products/stock/ws", h.StockStream.StreamStockWebSocket)       // WebSocket of stock changes
+	r.POST("/admin/product/:id/stock", h.Product.AdjustProductStock)      // Correct the stock of a product
+	r.GET("/products", h.Product.GetAllProducts)
+	r.GET("/products/search", h.Product.SearchProducts) // Full-text search by name and description
+	r.POST("/products/import", h.Import.ImportProducts) // Bulk upsert from CSV or JSON Lines
+	r.GET("/products/export", h.Export.ExportProducts)  // Stream a consistent catalogue snapshot
+	r.POST("/product", h.Product.CreateProduct)
+	r.PUT("/product/:id/status", h.Product.ChangeProductStatus)      // Move a product through its lifecycle
+	r.DELETE("/product/:id", h.Product.DeleteProduct)                // Soft-delete a product
+	r.POST("/admin/product/:id/restore", h.Product.RestoreProduct)   // Restore a soft-deleted product
+	r.PUT("/product/:id/category", h.Category.AssignProductCategory) // Assign product to a category
+	r.PUT("/product/:id/tags", h.Category.SetProductTags)            // Replace product tags
+	r.GET("/product/:id/variants", h.Variant.GetVariants)            // List product SKUs
+	r.POST("/product/:id/variant", h.Variant.CreateVariant)          // Add a SKU to a product
+	r.GET("/product/:id/media", h.Media.GetMedia)                    // List product images
+	r.POST("/product/:id/media", h.Media.UploadMedia)                // Upload a product image
+	r.PUT("/product/:id/media/order", h.Media.ReorderMedia)          // Reorder product images
+	r.DELETE("/product/:id/media/:mediaId", h.Media.DeleteMedia)     // Delete a product image
+	r.GET("/product/:id/price", h.Price.GetPrice)                    // Price at a point in time, now by default
+	r.POST("/product/:id/price", h.Price.ChangePrice)                // Change or schedule the price
+	r.GET("/product/:id/prices", h.Price.GetPriceHistory)            // Past, current and scheduled prices
+	r.GET("/product/:id/currency-prices", h.Currency.GetCurrencyPrices)
+	r.PUT("/product/:id/currency-price", h.Currency.SetCurrencyPrice) // Price in another currency, overrides conversion
+	r.DELETE("/product/:id/currency-price/:currency", h.Currency.DeleteCurrencyPrice)
+
+	r.GET("/variant/:id", h.Variant.GetVariant)
+	r.PUT("/variant/:id", h.Variant.UpdateVariant)
+	r.DELETE("/variant/:id", h.Variant.DeleteVariant)
+
+	r.GET("/categories", h.Category.GetCategories)
+	r.POST("/category", h.Category.CreateCategory)
+	r.GET("/category/:id", h.Category.GetCategory)
+	r.PUT("/category/:id", h.Category.UpdateCategory)
+	r.DELETE("/category/:id", h.Category.DeleteCategory)
+
+	r.POST("/pricing/quote", h.Pricing.QuotePrices) // Authoritative order line prices
+
+	r.GET("/exchange-rates", h.Currency.GetExchangeRates)
+	r.PUT("/exchange-rate", h.Currency.SetExchangeRate)
+	r.DELETE("/exchange-rate/:base/:quote", h.Currency.DeleteExchangeRate)
+
+	r.GET("/admin/cache/stats", h.Cache.GetCacheStats) // In-process cache counters of this instance
+
+	r.GET("/tags", h.Category.GetTags)
+	r.POST("/tag", h.Category.CreateTag)
+	r.DELETE("/tag/:id", h.Category.DeleteTag)
 }
@@ -0,0 +1,51 @@
+package routes
+
+import (
+	"net/http"
+	"product-catalog-service/internal/api"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// testHandlers returns handlers without services, which is enough to register the routes.
+func testHandlers() V1Handlers {
+	return V1Handlers{
+		Product:     api.NewProductHandler(nil),
+		Category:    api.NewCategoryHandler(nil),
+		Variant:     api.NewVariantHandler(nil),
+		Media:       api.NewMediaHandler(nil),
+		Import:      api.NewImportHandler(nil),
+		Export:      api.NewExportHandler(nil),
+		Pricing:     api.NewPricingHandler(nil),
+		Price:       api.NewPriceHandler(nil),
+		Currency:    api.NewCurrencyHandler(nil),
+		StockStream: api.NewStockStreamHandler(nil, nil, time.Second),
+		Cache:       api.NewCacheHandler(nil),
+	}
+}
+
+func TestRoutesMatchOpenAPISpec(t *testing.T) {
+	e := echo.New()
+	SetupRoutes(e, testHandlers(), Deprecation{DeprecatedAt: time.Now()})
+
+	if err := api.VerifyRoutes(e.Routes(), V1); err != nil {
+		t.Fatalf("routes and OpenAPI document diverge: %v", err)
+	}
+}
+
+func TestVerifyRoutesReportsUndocumentedRoute(t *testing.T) {
+	e := echo.New()
+	SetupRoutes(e, testHandlers(), Deprecation{DeprecatedAt: time.Now()})
+	e.GET(V1+"/product/:id/reviews", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+
+	err := api.VerifyRoutes(e.Routes(), V1)
+	if err == nil {
+		t.Fatal("expected an error for a route missing from the OpenAPI document")
+	}
+	if !strings.Contains(err.Error(), "GET /product/{id}/reviews") {
+		t.Errorf("error does not name the undocumented route: %v", err)
+	}
+}